@@ -0,0 +1,13 @@
+# The following example shows how to derive the same suffix for a bucket name on every run, without storing it in
+# state.
+
+data "random_string" "suffix" {
+  seed    = "${var.environment}-artifacts"
+  length  = 8
+  upper   = false
+  special = false
+}
+
+resource "aws_s3_bucket" "artifacts" {
+  bucket = "artifacts-${data.random_string.suffix.result}"
+}
@@ -0,0 +1,5 @@
+# Random bytes can be imported by specifying the value as base64 string.
+terraform import random_bytes.basic "8/fu3q+2DcgSJ19i0jZ5Cw=="
+
+# The b32_std or b32_nopad value may be given instead, prefixed with b32_std:
+terraform import random_bytes.basic "b32_std:6P365XVPWYG4QERHL5RNENTZBM======"
\ No newline at end of file
@@ -0,0 +1,5 @@
+# Random choices can be imported using the chosen item. This can be used
+# to replace a config value with a value interpolated from the random
+# provider without experiencing diffs.
+
+terraform import random_choice.region us-west-1
\ No newline at end of file
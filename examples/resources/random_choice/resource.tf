@@ -0,0 +1,11 @@
+resource "random_choice" "region" {
+  input   = ["us-west-1", "us-east-1", "eu-west-1"]
+  weights = [70, 20, 10]
+}
+
+resource "aws_s3_bucket" "example" {
+  # Place the bucket in one of the given regions, favouring us-west-1.
+  bucket = "example-${random_choice.region.result}"
+
+  # ... and other aws_s3_bucket arguments ...
+}
@@ -0,0 +1,5 @@
+# Random colors can be imported using a hex triplet of the form #rrggbb. This
+# can be used to replace a config value with a value interpolated from the
+# random provider without experiencing diffs.
+
+terraform import random_color.label "#1a2b3c"
\ No newline at end of file
@@ -0,0 +1,12 @@
+# The following example shows how to give a dashboard label a random color
+# that is dark enough to be read against a white background.
+
+resource "random_color" "label" {
+  max_brightness = 128
+}
+
+resource "github_issue_label" "team" {
+  repository = "example"
+  name       = "team"
+  color      = trimprefix(random_color.label.hex, "#")
+}
@@ -6,4 +6,20 @@
 terraform import random_id.server p-9hUg
 
 # Example with prefix (prefix is separated by a ,):
-$ terraform import random_id.server my-prefix-,p-9hUg
\ No newline at end of file
+$ terraform import random_id.server my-prefix-,p-9hUg
+
+# The value is taken from after the last , so the prefix may contain commas:
+terraform import random_id.server my,prefix-,p-9hUg
+
+# When prefix_separator is configured, give the prefix without the separator:
+terraform import random_id.server my-prefix,p-9hUg
+
+# The b64_std or b64_std_nopad value may be given instead of b64_url, with or
+# without = padding:
+terraform import random_id.server p+9hUg==
+
+# Example using the b32_checksummed encoding, whose check character is verified:
+terraform import random_id.server b32_checksummed:U7XWCUQ2
+
+# Example using the b32_std or b32_nopad encoding, with or without = padding:
+terraform import random_id.server b32_std:U7XWCUQ=
\ No newline at end of file
@@ -0,0 +1,5 @@
+# Random MAC addresses can be imported from an address in colon, hyphen or dot
+# separated form. This can be used to replace a config value with a value
+# interpolated from the random provider without experiencing diffs.
+
+terraform import random_mac.vm 02:00:5e:10:00:01
\ No newline at end of file
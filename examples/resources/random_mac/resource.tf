@@ -0,0 +1,18 @@
+# The following example shows how to give a virtual machine a stable,
+# locally administered MAC address that changes only when a new image is used.
+
+resource "random_mac" "vm" {
+  keepers = {
+    # Generate a new address each time we switch to a new image
+    image = var.image
+  }
+}
+
+resource "libvirt_domain" "vm" {
+  name = "vm"
+
+  network_interface {
+    network_name = "default"
+    mac          = random_mac.vm.result
+  }
+}
@@ -0,0 +1,10 @@
+# Random ports can be imported using the result, with an optional min and
+# max that otherwise default to the dynamic port range. This can be used to
+# replace a config value with a value interpolated from the random provider
+# without experiencing diffs.
+
+# Example using the default min and max:
+terraform import random_port.service 50123
+
+# Example with min and max (values are separated by a ,):
+terraform import random_port.service 8080,8000,8999
\ No newline at end of file
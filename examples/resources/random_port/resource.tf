@@ -0,0 +1,18 @@
+# The following example shows how to choose a port for a service from the
+# dynamic port range, avoiding ports already used by other services.
+
+resource "random_port" "service" {
+  exclude_ranges {
+    from = 50000
+    to   = 50100
+  }
+}
+
+resource "aws_security_group_rule" "service" {
+  type              = "ingress"
+  protocol          = "tcp"
+  from_port         = random_port.service.result
+  to_port           = random_port.service.result
+  cidr_blocks       = ["10.0.0.0/8"]
+  security_group_id = aws_security_group.service.id
+}
@@ -1,2 +1,15 @@
-# Strings can be imported by just specifying the value of the string:
-terraform import random_string.test test
\ No newline at end of file
+# Strings can be imported by just specifying the value of the string. The length
+# and the upper, lower, numeric and special arguments are inferred from the value,
+# so that no changes are planned for a configuration that matches it. The length
+# of a value containing multi-byte characters is counted in runes, with
+# length_unit set to runes:
+terraform import random_string.test test
+
+# Keepers can be imported along with the string by appending them after
+# |keepers:, separated by commas. Keeper values must not contain , characters:
+terraform import random_string.test 'test|keepers:ami_id=ami-0123456789,zone=eu-west-1a'
+
+# The results of a string with quantity set can be imported as a newline-delimited
+# list, or as a comma-delimited list prefixed with results:, of which the first is
+# the result. Results containing commas must be newline-delimited:
+terraform import random_string.pool 'results:abc123,def456,ghi789'
@@ -2,4 +2,12 @@
 # value with a value interpolated from the random provider without
 # experiencing diffs.
 
-terraform import random_uuid.main aabbccdd-eeff-0011-2233-445566778899
\ No newline at end of file
+terraform import random_uuid.main aabbccdd-eeff-0011-2233-445566778899
+
+# The uuid may be followed by the format of the result, separated by a ,
+# (e.g., uppercase or compact). The uuid itself may be in any format.
+terraform import random_uuid.main AABBCCDDEEFF00112233445566778899,compact
+
+# The format may be followed by the prefix and suffix of the result, which are
+# removed from the uuid when present. The format may be left empty.
+terraform import random_uuid.main order-aabbccdd-eeff-0011-2233-445566778899,,order-
\ No newline at end of file
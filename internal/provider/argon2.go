@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	hashAlgorithmBcrypt   = "bcrypt"
+	hashAlgorithmArgon2id = "argon2id"
+
+	// The default argon2id parameters are the second recommended option of RFC 9106, section 4, for environments
+	// where memory is constrained.
+	argon2DefaultMemory      = 64 * 1024
+	argon2DefaultIterations  = 3
+	argon2DefaultParallelism = 4
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var hashAlgorithms = []string{hashAlgorithmBcrypt, hashAlgorithmArgon2id}
+
+// argon2Params are the tunable parameters of argon2id. memory is in KiB.
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// generateArgon2idHash returns an argon2id hash of toHash in the PHC string format, i.e.
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>, using a random salt read from reader.
+func generateArgon2idHash(reader io.Reader, toHash string, params argon2Params) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := io.ReadFull(reader, salt); err != nil {
+		return "", err
+	}
+
+	return argon2idHash(toHash, salt, params), nil
+}
+
+// argon2idHash returns an argon2id hash of toHash with the given salt in the PHC string format.
+func argon2idHash(toHash string, salt []byte, params argon2Params) string {
+	key := argon2.IDKey([]byte(toHash), salt, params.iterations, params.memory, params.parallelism, argon2KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, params.memory, params.iterations,
+		params.parallelism, base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
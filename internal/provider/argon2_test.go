@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestGenerateArgon2idHash(t *testing.T) {
+	params := argon2Params{memory: 64, iterations: 2, parallelism: 1}
+
+	hash, err := generateArgon2idHash(rand.Reader, "hunter2", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !regexp.MustCompile(`^\$argon2id\$v=19\$m=64,t=2,p=1\$[A-Za-z0-9+/]{22}\$[A-Za-z0-9+/]{43}$`).MatchString(hash) {
+		t.Fatalf("hash %q is not in the expected format", hash)
+	}
+
+	if err := verifyArgon2idHash("hunter2", hash); err != nil {
+		t.Error(err)
+	}
+	if err := verifyArgon2idHash("hunter3", hash); err == nil {
+		t.Error("expected hash not to verify against a different password")
+	}
+
+	other, err := generateArgon2idHash(rand.Reader, "hunter2", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == hash {
+		t.Error("expected a different random salt for each hash")
+	}
+}
+
+// verifyArgon2idHash returns an error unless hash is the argon2id hash of key, using the parameters and salt held in
+// hash.
+func verifyArgon2idHash(key, hash string) error {
+	var version int
+	var params argon2Params
+	var saltAndKey string
+	if _, err := fmt.Sscanf(hash, "$argon2id$v=%d$m=%d,t=%d,p=%d$%s", &version, &params.memory, &params.iterations,
+		&params.parallelism, &saltAndKey); err != nil {
+		return fmt.Errorf("error parsing hash %q: %w", hash, err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(strings.SplitN(saltAndKey, "$", 2)[0])
+	if err != nil {
+		return fmt.Errorf("error decoding salt of hash %q: %w", hash, err)
+	}
+
+	if got := argon2idHash(key, salt, params); got != hash {
+		return fmt.Errorf("hash %q does not verify against the key, got %q", hash, got)
+	}
+
+	return nil
+}
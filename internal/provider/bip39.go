@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// bip39English is the BIP-39 English wordlist, as published at
+// https://github.com/bitcoin/bips/blob/master/bip-0039/english.txt.
+//
+//go:embed bip39_english.txt
+var bip39English string
+
+// bip39Wordlist returns the 2048 words of the BIP-39 English wordlist, in order.
+func bip39Wordlist() []string {
+	return strings.Fields(bip39English)
+}
+
+// bip39WordCounts are the mnemonic lengths defined by BIP-39, which correspond to 128 to 256 bits of entropy.
+var bip39WordCounts = []int{12, 15, 18, 21, 24}
+
+// generateBIP39Mnemonic reads entropy from reader and encodes it as a BIP-39 mnemonic of wordCount words. The final
+// word includes a checksum of the entropy, taken from the first wordCount/3 bits of its SHA-256 hash.
+func generateBIP39Mnemonic(reader io.Reader, wordCount int) (string, error) {
+	if wordCount%3 != 0 || wordCount < bip39WordCounts[0] || wordCount > bip39WordCounts[len(bip39WordCounts)-1] {
+		return "", fmt.Errorf("word count (%d) must be one of %v", wordCount, bip39WordCounts)
+	}
+
+	entropy := make([]byte, wordCount*4/3)
+	if _, err := io.ReadFull(reader, entropy); err != nil {
+		return "", err
+	}
+
+	checksum := sha256.Sum256(entropy)
+	data := append(entropy, checksum[0])
+
+	words := bip39Wordlist()
+	mnemonic := make([]string, wordCount)
+	for i := range mnemonic {
+		idx := 0
+		for bit := i * 11; bit < (i+1)*11; bit++ {
+			idx = idx<<1 | int(data[bit/8]>>(7-bit%8)&1)
+		}
+		mnemonic[i] = words[idx]
+	}
+
+	return strings.Join(mnemonic, " "), nil
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBIP39Wordlist(t *testing.T) {
+	words := bip39Wordlist()
+
+	if len(words) != 2048 {
+		t.Fatalf("expected 2048 words, got %d", len(words))
+	}
+
+	hash := sha256.Sum256([]byte(bip39English))
+	if got, want := hex.EncodeToString(hash[:]), "2f5eed53a4727b4bf8880d8f3f199efc90e58503646d9ff8eff3a2ed3b24dbda"; got != want {
+		t.Errorf("wordlist sha256 is %s; want %s", got, want)
+	}
+}
+
+func TestGenerateBIP39Mnemonic(t *testing.T) {
+	// Test vectors from https://github.com/trezor/python-mnemonic/blob/master/vectors.json.
+	cases := []struct {
+		name     string
+		entropy  string
+		expected string
+	}{
+		{
+			name:     "128 bits zero",
+			entropy:  "00000000000000000000000000000000",
+			expected: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		},
+		{
+			name:     "128 bits",
+			entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			expected: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		},
+		{
+			name:     "256 bits",
+			entropy:  "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			expected: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			entropy, err := hex.DecodeString(c.entropy)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			mnemonic, err := generateBIP39Mnemonic(bytes.NewReader(entropy), len(entropy)*3/4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mnemonic != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, mnemonic)
+			}
+		})
+	}
+
+	for _, wordCount := range bip39WordCounts {
+		mnemonic, err := generateBIP39Mnemonic(rand.Reader, wordCount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := validateBIP39Mnemonic(mnemonic, wordCount); err != nil {
+			t.Errorf("generated mnemonic %q is invalid: %s", mnemonic, err)
+		}
+	}
+
+	if _, err := generateBIP39Mnemonic(rand.Reader, 13); err == nil {
+		t.Error("expected error for invalid word count")
+	}
+}
+
+// validateBIP39Mnemonic returns an error if mnemonic does not consist of wordCount words from the BIP-39 English
+// wordlist with a valid checksum.
+func validateBIP39Mnemonic(mnemonic string, wordCount int) error {
+	words := strings.Split(mnemonic, " ")
+	if len(words) != wordCount {
+		return fmt.Errorf("expected %d words, got %d", wordCount, len(words))
+	}
+
+	indexes := map[string]int{}
+	for i, word := range bip39Wordlist() {
+		indexes[word] = i
+	}
+
+	data := make([]byte, (wordCount*11+7)/8)
+	for i, word := range words {
+		idx, ok := indexes[word]
+		if !ok {
+			return fmt.Errorf("word %q is not in the wordlist", word)
+		}
+		for bit := 0; bit < 11; bit++ {
+			if idx&(1<<(10-bit)) != 0 {
+				pos := i*11 + bit
+				data[pos/8] |= 1 << (7 - pos%8)
+			}
+		}
+	}
+
+	entropyLen := wordCount * 4 / 3
+	checksumBits := wordCount / 3
+	checksum := sha256.Sum256(data[:entropyLen])
+	if got, want := data[entropyLen]>>(8-checksumBits), checksum[0]>>(8-checksumBits); got != want {
+		return fmt.Errorf("checksum is %x; want %x", got, want)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// luhnCheckCharacter computes the Luhn mod N check character of body, where
+// N is the size of alphabet. The check character detects every single
+// character substitution and most transpositions of adjacent characters.
+func luhnCheckCharacter(body, alphabet string) (byte, error) {
+	n := len(alphabet)
+	factor := 2
+	sum := 0
+
+	for i := len(body) - 1; i >= 0; i-- {
+		codePoint := strings.IndexByte(alphabet, body[i])
+		if codePoint < 0 {
+			return 0, fmt.Errorf("character %q is not in the checksum alphabet", body[i])
+		}
+
+		addend := factor * codePoint
+		addend = addend/n + addend%n
+		sum += addend
+
+		factor = 3 - factor
+	}
+
+	return alphabet[(n-sum%n)%n], nil
+}
+
+// luhnValid reports whether the last character of s is the Luhn mod N check
+// character of the characters preceding it.
+func luhnValid(s, alphabet string) bool {
+	if len(s) < 2 {
+		return false
+	}
+
+	check, err := luhnCheckCharacter(s[:len(s)-1], alphabet)
+	if err != nil {
+		return false
+	}
+
+	return check == s[len(s)-1]
+}
+
+// luhnCheckDigit computes the Luhn check digit of digits, which must consist
+// only of decimal digits, as used by payment card and account numbers.
+func luhnCheckDigit(digits string) (int, error) {
+	check, err := luhnCheckCharacter(digits, numChars)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(check - '0'), nil
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestLuhnCheckCharacter(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		alphabet string
+		expected byte
+	}{
+		{"decimal", "7992739871", "0123456789", '3'},
+		{"decimal zero", "0", "0123456789", '0'},
+		{"hex", "1f", "0123456789abcdef", '0'},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := luhnCheckCharacter(c.body, c.alphabet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.expected {
+				t.Errorf("got %q; want %q", got, c.expected)
+			}
+			if !luhnValid(c.body+string(got), c.alphabet) {
+				t.Errorf("expected %q to be valid", c.body+string(got))
+			}
+		})
+	}
+
+	if _, err := luhnCheckCharacter("12a", "0123456789"); err == nil {
+		t.Error("expected error for character outside the alphabet")
+	}
+}
+
+func TestLuhnCheckDigit(t *testing.T) {
+	got, err := luhnCheckDigit("7992739871")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 3 {
+		t.Errorf("got %d; want 3", got)
+	}
+
+	if _, err := luhnCheckDigit("-12"); err == nil {
+		t.Error("expected error for a non-digit character")
+	}
+}
@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceString() *schema.Resource {
+	return &schema.Resource{
+		Description: "The data source `random_string` deterministically derives a string of the given length from " +
+			"`seed`, e.g., for naming conventions in `locals`, without creating a managed resource. The same " +
+			"`seed` and arguments always produce the same result, which is recomputed on each plan and is not " +
+			"stored between runs.\n" +
+			"\n" +
+			"The result is derived using HMAC_DRBG with SHA-256, as specified by NIST SP 800-90A, instantiated " +
+			"from `seed`. As anyone who knows `seed` can reproduce the result, it must not be used as a secret. " +
+			"Use the `random_string` or `random_password` resource for a random result.",
+		ReadContext: readStringDataSource,
+		Schema: map[string]*schema.Schema{
+			"seed": {
+				Description: "Arbitrary string from which the result is derived.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+
+			"length": {
+				Description: "The length of the string desired. The minimum value for length is 1 and, length " +
+					"must also be >= (`min_upper` + `min_lower` + `min_numeric` + `min_special`).",
+				Type:             schema.TypeInt,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			},
+
+			"special": {
+				Description: "Include special characters in the result. These are `!@#$%&*()-_=+[]{}<>:?`. Default value is `true`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+
+			"upper": {
+				Description: "Include uppercase alphabet characters in the result. Default value is `true`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+
+			"lower": {
+				Description: "Include lowercase alphabet characters in the result. Default value is `true`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+
+			"numeric": {
+				Description: "Include numeric characters in the result. Default value is `true`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+
+			"min_numeric": {
+				Description: "Minimum number of numeric characters in the result. Default value is `0`.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+			},
+
+			"min_upper": {
+				Description: "Minimum number of uppercase alphabet characters in the result. Default value is `0`.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+			},
+
+			"min_lower": {
+				Description: "Minimum number of lowercase alphabet characters in the result. Default value is `0`.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+			},
+
+			"min_special": {
+				Description: "Minimum number of special characters in the result. Default value is `0`.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+			},
+
+			"override_special": {
+				Description: "Supply your own list of special characters to use for string generation. This " +
+					"overrides the default character list in the special argument, including any " +
+					"`default_special_override` set in the provider configuration. The `special` argument must " +
+					"still be set to true for any overwritten characters to be used in generation. An empty string " +
+					"means that there are no special characters, in which case `min_special` must be `0`.",
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"result": {
+				Description: "The derived string.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+
+			"id": {
+				Description: "The derived string.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// readStringDataSource derives the result from seed using an hmacDRBG, choosing characters as createStringFunc does.
+func readStringDataSource(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	length := d.Get("length").(int)
+	if maxLength := maxStringLength(meta); length > maxLength {
+		return diag.Errorf("length (%d) must be <= max_string_length (%d), set max_string_length in the "+
+			"provider configuration to allow a longer result", length, maxLength)
+	}
+
+	chars, minimums := stringCharSets(d, meta)
+	if chars == "" {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "upper, lower, numeric and special are all false",
+			Detail:   "At least one of upper, lower, numeric or special must be true to generate a result.",
+		}}
+	}
+
+	var minTotal int
+	for _, m := range minimums {
+		minTotal += m.min
+	}
+	if length < minTotal {
+		return diag.Errorf("length (%d) must be >= min_upper + min_lower + min_numeric + min_special (%d)", length, minTotal)
+	}
+
+	if specialChars, ok := overrideSpecialChars(d); ok && specialChars == "" && d.Get("min_special").(int) > 0 {
+		return diag.Diagnostics{emptyOverrideSpecialDiagnostic(d.Get("min_special").(int))}
+	}
+
+	reader := newHMACDRBG([]byte(d.Get("seed").(string)))
+
+	units, err := generateString(reader, chars, minimums, length, splitBytes)
+	if err != nil {
+		return diag.Errorf("error deriving string: %s", err)
+	}
+
+	result := strings.Join(units, "")
+	if err := d.Set("result", result); err != nil {
+		return diag.Errorf("error setting result: %s", err)
+	}
+
+	d.SetId(result)
+
+	return nil
+}
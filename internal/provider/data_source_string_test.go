@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceString(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceStringConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("data.random_string.a", "result", regexp.MustCompile(`^[a-z0-9]{12}$`)),
+					resource.TestCheckResourceAttrPair("data.random_string.a", "result", "data.random_string.b", "result"),
+				),
+			},
+		},
+	})
+}
+
+func TestReadStringDataSource(t *testing.T) {
+	read := func(seed string) string {
+		d := dataSourceString().TestResourceData()
+		for k, v := range map[string]interface{}{
+			"seed":        seed,
+			"length":      16,
+			"upper":       true,
+			"lower":       true,
+			"numeric":     true,
+			"min_numeric": 4,
+		} {
+			if err := d.Set(k, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if diags := readStringDataSource(context.Background(), d, nil); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if d.Id() != d.Get("result").(string) {
+			t.Errorf("expected id %q to equal result %q", d.Id(), d.Get("result"))
+		}
+
+		return d.Get("result").(string)
+	}
+
+	a, b, other := read("naming"), read("naming"), read("other")
+	if a != b {
+		t.Errorf("expected the same result for the same seed, got: %q and %q", a, b)
+	}
+	if a == other {
+		t.Errorf("expected different results for different seeds, got: %q", a)
+	}
+	if numeric := len(regexp.MustCompile(`[0-9]`).FindAllString(a, -1)); numeric < 4 {
+		t.Errorf("expected at least 4 numeric characters in %q, got: %d", a, numeric)
+	}
+}
+
+const testAccDataSourceStringConfig = `
+data "random_string" "a" {
+  seed    = "naming"
+  length  = 12
+  upper   = false
+  special = false
+}
+
+data "random_string" "b" {
+  seed    = "naming"
+  length  = 12
+  upper   = false
+  special = false
+}`
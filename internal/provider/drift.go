@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// detectDriftSchema returns the detect_drift entry for resources whose Read can check that the result held in
+// state is still valid. It is not ForceNew, as it only affects how the resource is read.
+func detectDriftSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Check that the result held in state is still valid each time the resource is read, e.g., " +
+			"after the state has been edited or corrupted outside of Terraform. When it is not, a warning is " +
+			"returned and the resource is removed from state, so that a new result is generated by the next " +
+			"apply. Default value is `false`.",
+		Type:     schema.TypeBool,
+		Optional: true,
+	}
+}
+
+// removeOnDrift removes the resource held in d from state, so that it is recreated by the next apply, and returns a
+// warning explaining that the result held in state is invalid because of err.
+func removeOnDrift(d *schema.ResourceData, err error) diag.Diagnostics {
+	id := d.Id()
+	d.SetId("")
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Invalid result in state",
+		Detail: fmt.Sprintf("The result held in state for id %q is invalid, so the resource has been removed from "+
+			"state and will be recreated: %s", id, err),
+	}}
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// generationSchema returns the computed generation entry. The generation is 1 when a resource is created or imported,
+// and is incremented each time the resource generates a new result in place, which resources with a
+// replace_on_keeper_change entry do when keepers change. inPlace selects the description for such resources.
+//
+// Replacing a resource starts again at 1, as Terraform plans and creates the new resource without the state of the
+// one it replaces.
+func generationSchema(inPlace bool) *schema.Schema {
+	description := "The number of times a result has been generated by this resource, which is `1` when it is " +
+		"created or imported. Replacing the resource starts again at `1`."
+	if inPlace {
+		description = "The number of times a result has been generated by this resource, which is `1` when it is " +
+			"created or imported and is incremented each time a new result is generated in place when `keepers` " +
+			"change and `replace_on_keeper_change` is `false`. Replacing the resource starts again at `1`."
+	}
+
+	return &schema.Schema{
+		Description: description,
+		Type:        schema.TypeInt,
+		Computed:    true,
+	}
+}
+
+// setGeneration sets the generation in d to one more than that of its prior state, which is 1 when the resource is
+// created and the incremented generation, as planned by planGeneration, when a new result is generated in place.
+func setGeneration(d *schema.ResourceData) error {
+	o, _ := d.GetChange("generation")
+
+	return d.Set("generation", o.(int)+1)
+}
+
+// planGeneration is a CustomizeDiffFunc that plans the incremented generation when planKeepersChange plans a new
+// result in place.
+func planGeneration(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChange("keepers") || replaceOnKeeperChange(d.GetRawConfig()) {
+		return nil
+	}
+
+	o, _ := d.GetChange("generation")
+
+	return d.SetNew("generation", o.(int)+1)
+}
+
+// generationStateUpgrade sets the generation of a resource created before it was added to 1.
+func generationStateUpgrade(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return nil, fmt.Errorf("generation state upgrade failed, state is nil")
+	}
+
+	rawState["generation"] = 1
+
+	return rawState, nil
+}
@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestPlanGeneration(t *testing.T) {
+	keepers := func(v string) cty.Value {
+		return cty.MapVal(map[string]cty.Value{"a": cty.StringVal(v)})
+	}
+
+	cases := []struct {
+		name     string
+		config   map[string]cty.Value
+		expected int64
+	}{
+		{
+			name:     "keepers unchanged",
+			config:   map[string]cty.Value{"keepers": keepers("1"), "replace_on_keeper_change": cty.False},
+			expected: 2,
+		},
+		{
+			name:     "keepers changed with replace_on_keeper_change false",
+			config:   map[string]cty.Value{"keepers": keepers("2"), "replace_on_keeper_change": cty.False},
+			expected: 3,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			prior := map[string]cty.Value{
+				"id":         cty.StringVal("9fb4d5c1-0a4e-4a4d-8c1e-5bc3a5e3b0c4"),
+				"result":     cty.StringVal("9fb4d5c1-0a4e-4a4d-8c1e-5bc3a5e3b0c4"),
+				"keepers":    keepers("1"),
+				"generation": cty.NumberIntVal(2),
+			}
+
+			planned, _ := planResourceChange(t, "random_uuid", prior, c.config)
+
+			if got := planned.GetAttr("generation"); !got.RawEquals(cty.NumberIntVal(c.expected)) {
+				t.Errorf("expected generation: %d, got: %#v", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestCreateGeneration(t *testing.T) {
+	r := New().ResourcesMap["random_pet"]
+	d := r.TestResourceData()
+
+	if diags := r.CreateContext(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if got := d.Get("generation").(int); got != 1 {
+		t.Errorf("expected generation: 1, got: %d", got)
+	}
+}
+
+func TestSetGeneration(t *testing.T) {
+	r := New().ResourcesMap["random_uuid"]
+	d := r.Data(&terraform.InstanceState{
+		ID:         "9fb4d5c1-0a4e-4a4d-8c1e-5bc3a5e3b0c4",
+		Attributes: map[string]string{"generation": "2"},
+	})
+
+	if err := setGeneration(d); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := d.Get("generation").(int); got != 3 {
+		t.Errorf("expected generation: 3, got: %d", got)
+	}
+}
+
+func TestGenerationSchemaVersion(t *testing.T) {
+	for name, r := range New().ResourcesMap {
+		t.Run(name, func(t *testing.T) {
+			if _, ok := r.Schema["generation"]; !ok {
+				t.Fatal("expected a generation entry")
+			}
+
+			switch name {
+			case "random_bytes", "random_choice", "random_color", "random_mac", "random_port":
+				if r.SchemaVersion != 0 || len(r.StateUpgraders) != 0 {
+					t.Errorf("expected no state upgraders, got schema version %d with %d state upgraders",
+						r.SchemaVersion, len(r.StateUpgraders))
+				}
+			}
+		})
+	}
+}
+
+func TestGenerationStateUpgrade(t *testing.T) {
+	cases := []struct {
+		name     string
+		rawState map[string]interface{}
+		expected map[string]interface{}
+		err      error
+	}{
+		{
+			name:     "state is nil",
+			rawState: nil,
+			err:      errors.New("generation state upgrade failed, state is nil"),
+		},
+		{
+			name:     "generation is added",
+			rawState: map[string]interface{}{"id": "1", "result": 1},
+			expected: map[string]interface{}{"id": "1", "result": 1, "generation": 1},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := generationStateUpgrade(context.Background(), c.rawState, nil)
+
+			if !reflect.DeepEqual(c.err, err) {
+				t.Fatalf("expected error: %v, got: %v", c.err, err)
+			}
+
+			if !reflect.DeepEqual(c.expected, actual) {
+				t.Errorf("expected: %+v, got: %+v", c.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// maxGrammarLength bounds the length in bytes of the longest possible expansion of a grammar, so that a grammar whose
+// rules each refer to the next more than once cannot expand to an unreasonably long result.
+const maxGrammarLength = 1 << 20
+
+// grammar is a parsed set of production rules. Each rule maps a name to a list of alternatives, each of which is a
+// sequence of symbols. The first rule defined is the start rule, and maxLength is the length in bytes of its longest
+// possible expansion.
+type grammar struct {
+	start     string
+	rules     map[string][][]grammarSymbol
+	maxLength int
+}
+
+// grammarSymbol is either a terminal, which is emitted as-is, or a reference to another rule.
+type grammarSymbol struct {
+	terminal string
+	rule     string
+}
+
+// parseGrammar parses a BNF-like grammar in which each non-blank line defines a rule of the form:
+//
+//	<name> ::= <other> "literal" | "alternative"
+//
+// Terminals are double-quoted and may use \" and \\ escapes. Every referenced rule must be defined and no rule may
+// refer to itself, directly or indirectly, so that every expansion is finite. The longest possible expansion must not
+// exceed maxGrammarLength bytes.
+func parseGrammar(spec string) (*grammar, error) {
+	g := &grammar{
+		rules: map[string][][]grammarSymbol{},
+	}
+
+	for i, line := range strings.Split(spec, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		sep := strings.Index(line, "::=")
+		if sep < 0 {
+			return nil, fmt.Errorf("line %d: expected a rule of the form <name> ::= ...", i+1)
+		}
+
+		name, err := parseGrammarRuleName(strings.TrimSpace(line[:sep]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if _, ok := g.rules[name]; ok {
+			return nil, fmt.Errorf("line %d: rule <%s> is defined more than once", i+1, name)
+		}
+
+		alternatives, err := parseGrammarAlternatives(line[sep+len("::="):])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		if g.start == "" {
+			g.start = name
+		}
+		g.rules[name] = alternatives
+	}
+
+	if g.start == "" {
+		return nil, fmt.Errorf("grammar must define at least one rule")
+	}
+
+	visiting := map[string]bool{}
+	maxLengths := map[string]int{}
+	for name := range g.rules {
+		if err := g.checkAcyclic(name, visiting, maxLengths); err != nil {
+			return nil, err
+		}
+	}
+
+	g.maxLength = maxLengths[g.start]
+	if g.maxLength > maxGrammarLength {
+		return nil, fmt.Errorf("rule <%s> can expand to more than %d bytes", g.start, maxGrammarLength)
+	}
+
+	return g, nil
+}
+
+func parseGrammarRuleName(s string) (string, error) {
+	if len(s) < 3 || s[0] != '<' || s[len(s)-1] != '>' || strings.ContainsAny(s[1:len(s)-1], "<> \t") {
+		return "", fmt.Errorf("invalid rule name %q, expected <name>", s)
+	}
+
+	return s[1 : len(s)-1], nil
+}
+
+func parseGrammarAlternatives(s string) ([][]grammarSymbol, error) {
+	var alternatives [][]grammarSymbol
+	var current []grammarSymbol
+
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '|':
+			if current == nil {
+				return nil, fmt.Errorf("empty alternative, use \"\" for an empty string")
+			}
+			alternatives = append(alternatives, current)
+			current = nil
+			i++
+		case c == '<':
+			end := strings.IndexByte(s[i:], '>')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated rule reference")
+			}
+			name, err := parseGrammarRuleName(s[i : i+end+1])
+			if err != nil {
+				return nil, err
+			}
+			current = append(current, grammarSymbol{rule: name})
+			i += end + 1
+		case c == '"':
+			var b strings.Builder
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				b.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, fmt.Errorf("unterminated terminal")
+			}
+			current = append(current, grammarSymbol{terminal: b.String()})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q, terminals must be double-quoted", c)
+		}
+	}
+
+	if current == nil {
+		return nil, fmt.Errorf("empty alternative, use \"\" for an empty string")
+	}
+
+	return append(alternatives, current), nil
+}
+
+// checkAcyclic returns an error if the named rule is undefined or can reach itself. Otherwise, it records the length
+// in bytes of the longest possible expansion of the rule in maxLengths, which also memoises the rules already checked.
+// Lengths above maxGrammarLength are recorded as maxGrammarLength + 1, so that they cannot overflow.
+func (g *grammar) checkAcyclic(name string, visiting map[string]bool, maxLengths map[string]int) error {
+	if _, ok := maxLengths[name]; ok {
+		return nil
+	}
+	if visiting[name] {
+		return fmt.Errorf("rule <%s> is recursive", name)
+	}
+
+	alternatives, ok := g.rules[name]
+	if !ok {
+		return fmt.Errorf("rule <%s> is not defined", name)
+	}
+
+	visiting[name] = true
+	maxLength := 0
+	for _, alternative := range alternatives {
+		length := 0
+		for _, symbol := range alternative {
+			if symbol.rule == "" {
+				length += len(symbol.terminal)
+			} else {
+				if err := g.checkAcyclic(symbol.rule, visiting, maxLengths); err != nil {
+					return err
+				}
+				length += maxLengths[symbol.rule]
+			}
+
+			if length > maxGrammarLength {
+				length = maxGrammarLength + 1
+			}
+		}
+
+		if length > maxLength {
+			maxLength = length
+		}
+	}
+	visiting[name] = false
+	maxLengths[name] = maxLength
+
+	return nil
+}
+
+// expand returns a random expansion of the start rule, choosing each alternative uniformly using reader.
+func (g *grammar) expand(reader io.Reader) ([]byte, error) {
+	var b []byte
+	if err := g.expandRule(reader, g.start, &b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (g *grammar) expandRule(reader io.Reader, name string, b *[]byte) error {
+	alternatives := g.rules[name]
+
+	idx, err := rand.Int(reader, big.NewInt(int64(len(alternatives))))
+	if err != nil {
+		return err
+	}
+
+	for _, symbol := range alternatives[idx.Int64()] {
+		if symbol.rule == "" {
+			*b = append(*b, symbol.terminal...)
+			continue
+		}
+		if err := g.expandRule(reader, symbol.rule, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"crypto/rand"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParseGrammar(t *testing.T) {
+	cases := []struct {
+		name     string
+		spec     string
+		expected string
+	}{
+		{"valid", "<a> ::= \"x\" <b> | \"\"\n<b> ::= \"y\"", ""},
+		{"escaped terminal", `<a> ::= "\"" | "\\"`, ""},
+		{"empty", "\n  \n", "grammar must define at least one rule"},
+		{"missing separator", `<a> "x"`, "line 1: expected a rule of the form <name> ::= ..."},
+		{"invalid name", `a ::= "x"`, `line 1: invalid rule name "a", expected <name>`},
+		{"duplicate rule", "<a> ::= \"x\"\n<a> ::= \"y\"", "line 2: rule <a> is defined more than once"},
+		{"empty alternative", `<a> ::= "x" |`, `line 1: empty alternative, use "" for an empty string`},
+		{"bare terminal", `<a> ::= x`, `line 1: unexpected character 'x', terminals must be double-quoted`},
+		{"unterminated terminal", `<a> ::= "x`, "line 1: unterminated terminal"},
+		{"undefined rule", `<a> ::= <b>`, "rule <b> is not defined"},
+		{"self recursive", `<a> ::= "x" | "x" <a>`, "rule <a> is recursive"},
+		{"too long", exponentialGrammar(30), "rule <r0> can expand to more than 1048576 bytes"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := parseGrammar(c.spec)
+			if c.expected == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != c.expected {
+				t.Errorf("expected error %q, got %v", c.expected, err)
+			}
+		})
+	}
+
+	_, err := parseGrammar("<a> ::= <b>\n<b> ::= <c>\n<c> ::= <a>")
+	if err == nil || !strings.HasSuffix(err.Error(), "is recursive") {
+		t.Errorf("expected indirect recursion to be rejected, got %v", err)
+	}
+}
+
+// exponentialGrammar returns a grammar of n + 1 rules, each of which but the last refers to the next twice, so that
+// it expands to 2^n bytes.
+func exponentialGrammar(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "<r%d> ::= <r%d> <r%d>\n", i, i+1, i+1)
+	}
+	fmt.Fprintf(&b, "<r%d> ::= \"x\"", n)
+
+	return b.String()
+}
+
+func TestGrammarMaxLength(t *testing.T) {
+	cases := []struct {
+		spec     string
+		expected int
+	}{
+		{`<a> ::= "x"`, 1},
+		{"<a> ::= \"xy\" <b> | <b> <b> <b>\n<b> ::= \"\" | \"abc\"", 9},
+		{exponentialGrammar(20), 1 << 20},
+	}
+
+	for _, c := range cases {
+		g, err := parseGrammar(c.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g.maxLength != c.expected {
+			t.Errorf("expected max length %d for %q, got %d", c.expected, c.spec, g.maxLength)
+		}
+	}
+}
+
+func TestGrammarExpand(t *testing.T) {
+	g, err := parseGrammar(`<sku> ::= <category> "-" <digit> <digit> <digit>
+<category> ::= "HW" | "SW" | "SVC"
+<digit> ::= "0" | "1" | "2" | "3" | "4" | "5" | "6" | "7" | "8" | "9"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pattern := regexp.MustCompile(`^(HW|SW|SVC)-[0-9]{3}$`)
+	for i := 0; i < 100; i++ {
+		result, err := g.expand(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pattern.Match(result) {
+			t.Errorf("result %q does not conform to the grammar", result)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	lengthUnitBytes     = "bytes"
+	lengthUnitRunes     = "runes"
+	lengthUnitGraphemes = "graphemes"
+
+	zeroWidthJoiner = '\u200d'
+)
+
+var lengthUnits = []string{lengthUnitBytes, lengthUnitRunes, lengthUnitGraphemes}
+
+// lengthUnitSplitter returns the function used to split strings into the units configured by the length_unit entry
+// in d, which is only present in the random_string schema. Bytes are used when length_unit is not set.
+func lengthUnitSplitter(d *schema.ResourceData) func(string) []string {
+	v, _ := d.GetOk("length_unit")
+
+	switch v {
+	case lengthUnitRunes:
+		return splitRunes
+	case lengthUnitGraphemes:
+		return splitGraphemes
+	default:
+		return splitBytes
+	}
+}
+
+// splitBytes returns each byte of s as a separate string.
+func splitBytes(s string) []string {
+	result := make([]string, len(s))
+	for i := range result {
+		result[i] = s[i : i+1]
+	}
+	return result
+}
+
+// splitRunes returns each rune of s as a separate string.
+func splitRunes(s string) []string {
+	result := make([]string, 0, utf8.RuneCountInString(s))
+	for _, r := range s {
+		result = append(result, string(r))
+	}
+	return result
+}
+
+// splitGraphemes splits s into extended grapheme clusters, i.e., user-perceived characters. It implements the subset
+// of the Unicode text segmentation rules (UAX #29) that matter for characters a user is likely to supply: CR LF is
+// kept together, other control characters stand alone, combining marks, emoji modifiers and zero width joiners extend
+// the preceding cluster, a character following a zero width joiner joins its cluster and regional indicators are
+// paired into flags. Hangul syllable sequences and the rarer prepend and spacing rules are not handled, so such text
+// may be split into more clusters than a full implementation would produce.
+func splitGraphemes(s string) []string {
+	var result []string
+
+	start := 0
+	prev := rune(-1)
+	regionalIndicators := 0
+	for i, r := range s {
+		if prev >= 0 && graphemeBreak(prev, r, regionalIndicators) {
+			result = append(result, s[start:i])
+			start = i
+			regionalIndicators = 0
+		}
+		if isRegionalIndicator(r) {
+			regionalIndicators++
+		} else {
+			regionalIndicators = 0
+		}
+		prev = r
+	}
+	if start < len(s) {
+		result = append(result, s[start:])
+	}
+
+	return result
+}
+
+// graphemeBreak returns true if there is a grapheme cluster boundary between prev and next. regionalIndicators is the
+// number of consecutive regional indicators in the current cluster ending at prev.
+func graphemeBreak(prev, next rune, regionalIndicators int) bool {
+	switch {
+	case prev == '\r' && next == '\n':
+		return false
+	case isGraphemeControl(prev) || isGraphemeControl(next):
+		return true
+	case isGraphemeExtend(next):
+		return false
+	case prev == zeroWidthJoiner:
+		return false
+	case isRegionalIndicator(prev) && isRegionalIndicator(next):
+		return regionalIndicators%2 == 0
+	}
+
+	return true
+}
+
+// isGraphemeControl returns true for the control, line and paragraph separator characters that always form a
+// cluster of their own.
+func isGraphemeControl(r rune) bool {
+	return unicode.IsControl(r) || unicode.In(r, unicode.Zl, unicode.Zp)
+}
+
+// isGraphemeExtend returns true for characters that extend the preceding cluster.
+func isGraphemeExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r == zeroWidthJoiner ||
+		(r >= 0x1f3fb && r <= 0x1f3ff) // emoji skin tone modifiers
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSplitGraphemes(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "ascii",
+			input:    "abc",
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "combining accent",
+			input:    "e\u0301a",
+			expected: []string{"e\u0301", "a"},
+		},
+		{
+			name:     "skin tone modifier",
+			input:    "\U0001f44d\U0001f3fd!",
+			expected: []string{"\U0001f44d\U0001f3fd", "!"},
+		},
+		{
+			name:     "zero width joiner sequence",
+			input:    "\U0001f468\u200d\U0001f469\u200d\U0001f467",
+			expected: []string{"\U0001f468\u200d\U0001f469\u200d\U0001f467"},
+		},
+		{
+			name:     "flags",
+			input:    "\U0001f1fa\U0001f1f8\U0001f1ec\U0001f1e7\U0001f1eb",
+			expected: []string{"\U0001f1fa\U0001f1f8", "\U0001f1ec\U0001f1e7", "\U0001f1eb"},
+		},
+		{
+			name:     "crlf",
+			input:    "a\r\nb",
+			expected: []string{"a", "\r\n", "b"},
+		},
+		{
+			name:     "control before mark",
+			input:    "\n\u0301",
+			expected: []string{"\n", "\u0301"},
+		},
+		{
+			name:  "empty",
+			input: "",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := splitGraphemes(c.input)
+
+			if !cmp.Equal(actual, c.expected) {
+				t.Errorf("expected: %q, got: %q", c.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+// hmacDRBG is an HMAC_DRBG deterministic random bit generator using SHA-256, as specified by NIST SP 800-90A. It
+// is instantiated from seed material only, without a nonce or personalization string, and is never reseeded, so the
+// same seed material always produces the same sequence. Unlike math/rand, its output does not depend on the Go
+// release.
+//
+// Each call to Read is a single generate request, so the sequence depends on the sizes of the reads as well as the
+// seed material.
+type hmacDRBG struct {
+	k []byte
+	v []byte
+}
+
+// newHMACDRBG returns an hmacDRBG instantiated from seedMaterial.
+func newHMACDRBG(seedMaterial []byte) *hmacDRBG {
+	d := &hmacDRBG{
+		k: make([]byte, sha256.Size),
+		v: make([]byte, sha256.Size),
+	}
+	for i := range d.v {
+		d.v[i] = 0x01
+	}
+
+	d.update(seedMaterial)
+
+	return d
+}
+
+// update is the HMAC_DRBG_Update function of NIST SP 800-90A, which updates the internal state using providedData.
+func (d *hmacDRBG) update(providedData []byte) {
+	d.k = d.mac(d.v, []byte{0x00}, providedData)
+	d.v = d.mac(d.v)
+	if len(providedData) == 0 {
+		return
+	}
+
+	d.k = d.mac(d.v, []byte{0x01}, providedData)
+	d.v = d.mac(d.v)
+}
+
+// mac returns the HMAC-SHA256, keyed by the current key, of the concatenation of data.
+func (d *hmacDRBG) mac(data ...[]byte) []byte {
+	h := hmac.New(sha256.New, d.k)
+	for _, b := range data {
+		h.Write(b)
+	}
+	return h.Sum(nil)
+}
+
+// Read fills p with the output of a single HMAC_DRBG generate request. It never returns an error.
+func (d *hmacDRBG) Read(p []byte) (int, error) {
+	for n := 0; n < len(p); {
+		d.v = d.mac(d.v)
+		n += copy(p[n:], d.v)
+	}
+
+	d.update(nil)
+
+	return len(p), nil
+}
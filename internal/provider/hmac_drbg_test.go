@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestHMACDRBG(t *testing.T) {
+	// The first SHA-256 test vector, without prediction resistance, personalization string or additional input,
+	// of the NIST CAVP HMAC_DRBG test vectors. The returned bits are those of the second of two generate requests.
+	entropy, _ := hex.DecodeString("ca851911349384bffe89de1cbdc46e6831e44d34a4fb935ee285dd14b71a7488")
+	nonce, _ := hex.DecodeString("659ba96c601dc69fc902940805ec0ca8")
+	expected, _ := hex.DecodeString("e528e9abf2dece54d47c7e75e5fe302149f817ea9fb4bee6f4199697d04d5b89" +
+		"d54fbb978a15b5c443c9ec21036d2460b6f73ebad0dc2aba6e624abf07745bc1" +
+		"07694bb7547bb0995f70de25d6b29e2d3011bb19d27676c07162c8b5ccde0668" +
+		"961df86803482cb37ed6d5c0bb8d50cf1f50d476aa0458bdaba806f48be9dcb8")
+
+	d := newHMACDRBG(append(entropy, nonce...))
+
+	actual := make([]byte, len(expected))
+	for i := 0; i < 2; i++ {
+		if _, err := d.Read(actual); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("expected %x, got: %x", expected, actual)
+	}
+}
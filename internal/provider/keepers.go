@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// replaceOnKeeperChangeSchema returns the replace_on_keeper_change entry for resources that can generate a new result
+// in place, rather than being replaced, when keepers change. The keepers entry of such resources must not be ForceNew,
+// as whether a change requires replacement is decided by planKeepersChange.
+func replaceOnKeeperChangeSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Whether a change to `keepers` replaces the resource. When `false`, a new result is generated " +
+			"in place and the resource is updated instead, so it is never destroyed and resources that depend on " +
+			"it are updated rather than replaced alongside it. Changes to any other argument still replace the " +
+			"resource. Default value is `true`.",
+		Type:     schema.TypeBool,
+		Optional: true,
+	}
+}
+
+// allowKeepersUpdate alters the keepers entry of resourceSchema, which is expected to be ForceNew, so that the
+// resource can generate a new result in place when keepers change, and adds the replace_on_keeper_change entry.
+func allowKeepersUpdate(resourceSchema map[string]*schema.Schema) {
+	resourceSchema["keepers"].Description = "Arbitrary map of values that, when changed, will trigger recreation of " +
+		"resource, or generation of a new result in place when `replace_on_keeper_change` is `false`. See " +
+		"[the main provider documentation](../index.html) for more information."
+	resourceSchema["keepers"].ForceNew = false
+
+	resourceSchema["replace_on_keeper_change"] = replaceOnKeeperChangeSchema()
+}
+
+// planKeepersChange returns a CustomizeDiffFunc that, when keepers change, either requires the resource to be
+// replaced or, when replace_on_keeper_change is false, plans new values for the given computed attributes, which are
+// generated by the function returned by updateOnKeepersChange.
+func planKeepersChange(computedKeys ...string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+		if d.Id() == "" || !d.HasChange("keepers") {
+			return nil
+		}
+
+		if replaceOnKeeperChange(d.GetRawConfig()) {
+			return d.ForceNew("keepers")
+		}
+
+		for _, k := range computedKeys {
+			if err := d.SetNewComputed(k); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// replaceOnKeeperChange returns the value of replace_on_keeper_change in config, which is true when it is not set.
+func replaceOnKeeperChange(config cty.Value) bool {
+	if config.IsNull() || !config.IsKnown() {
+		return true
+	}
+
+	v := config.GetAttr("replace_on_keeper_change")
+	if v.IsNull() || !v.IsKnown() {
+		return true
+	}
+
+	return v.True()
+}
+
+// updateOnKeepersChange returns an UpdateContextFunc that generates a new result using create when keepers have
+// changed. Other changes, such as to replace_on_keeper_change, only need to be stored in state.
+func updateOnKeepersChange(create schema.CreateContextFunc) schema.UpdateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		if !d.HasChange("keepers") {
+			return nil
+		}
+
+		return create(ctx, d, meta)
+	}
+}
+
+// logKeepersChange is a CustomizeDiffFunc that logs which keepers keys have been added, removed or changed when
+// keepers change, so that it is clear from the logs which key triggered a new result. SDKv2 does not allow a
+// CustomizeDiffFunc to return diagnostics, so the change is logged rather than reported as a warning. Only keys
+// are logged, as keepers values may be sensitive, and the ID is not, as it is the result of some resources. The SDK
+// already identifies the resource in each log line.
+func logKeepersChange(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChange("keepers") {
+		return nil
+	}
+
+	o, n := d.GetChange("keepers")
+	oldKeepers, _ := o.(map[string]interface{})
+	newKeepers, _ := n.(map[string]interface{})
+
+	added, removed, changed := keepersChanges(oldKeepers, newKeepers)
+	log.Printf("[INFO] keepers changed: added [%s], removed [%s], changed [%s]",
+		strings.Join(added, ", "), strings.Join(removed, ", "), strings.Join(changed, ", "))
+
+	return nil
+}
+
+// keepersChanges returns the sorted keys that are in newKeepers but not oldKeepers, those that are in oldKeepers but
+// not newKeepers, and those that are in both with different values.
+func keepersChanges(oldKeepers, newKeepers map[string]interface{}) (added, removed, changed []string) {
+	for k, v := range newKeepers {
+		ov, ok := oldKeepers[k]
+		switch {
+		case !ok:
+			added = append(added, k)
+		case !reflect.DeepEqual(ov, v):
+			changed = append(changed, k)
+		}
+	}
+
+	for k := range oldKeepers {
+		if _, ok := newKeepers[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+
+	return added, removed, changed
+}
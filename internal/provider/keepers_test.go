@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestPlanKeepersChange(t *testing.T) {
+	keepers := func(v string) cty.Value {
+		return cty.MapVal(map[string]cty.Value{"a": cty.StringVal(v)})
+	}
+
+	cases := []struct {
+		name            string
+		config          map[string]cty.Value
+		priorKeepers    cty.Value
+		expectedReplace bool
+		expectedUpdate  bool
+	}{
+		{
+			name:            "keepers unchanged",
+			config:          map[string]cty.Value{"keepers": keepers("1")},
+			priorKeepers:    keepers("1"),
+			expectedReplace: false,
+			expectedUpdate:  false,
+		},
+		{
+			name:            "keepers changed with replace_on_keeper_change unset",
+			config:          map[string]cty.Value{"keepers": keepers("2")},
+			priorKeepers:    keepers("1"),
+			expectedReplace: true,
+			expectedUpdate:  false,
+		},
+		{
+			name:            "keepers changed with replace_on_keeper_change true",
+			config:          map[string]cty.Value{"keepers": keepers("2"), "replace_on_keeper_change": cty.True},
+			priorKeepers:    keepers("1"),
+			expectedReplace: true,
+			expectedUpdate:  false,
+		},
+		{
+			name:            "keepers changed with replace_on_keeper_change false",
+			config:          map[string]cty.Value{"keepers": keepers("2"), "replace_on_keeper_change": cty.False},
+			priorKeepers:    keepers("1"),
+			expectedReplace: false,
+			expectedUpdate:  true,
+		},
+	}
+
+	resources := map[string]map[string]cty.Value{
+		"random_uuid":   {"result": cty.StringVal("9fb4d5c1-0a4e-4a4d-8c1e-5bc3a5e3b0c4")},
+		"random_string": {"length": cty.NumberIntVal(12), "result": cty.StringVal("abcdefghijkl")},
+		"random_password": {
+			"id":          cty.StringVal("none"),
+			"length":      cty.NumberIntVal(12),
+			"result":      cty.StringVal("abcdefghijkl"),
+			"bcrypt_hash": cty.StringVal("$2a$10$abcdefghijklmnopqrstuv"),
+		},
+	}
+
+	for typeName, attrs := range resources {
+		for _, c := range cases {
+			t.Run(typeName+" "+c.name, func(t *testing.T) {
+				config := map[string]cty.Value{}
+				for k, v := range c.config {
+					config[k] = v
+				}
+				if length, ok := attrs["length"]; ok {
+					config["length"] = length
+				}
+
+				prior := map[string]cty.Value{"id": attrs["result"], "keepers": c.priorKeepers}
+				for k, v := range attrs {
+					prior[k] = v
+				}
+
+				planned, resp := planResourceChange(t, typeName, prior, config)
+
+				replace := false
+				for _, p := range resp.RequiresReplace {
+					if p.Equal(tftypes.NewAttributePath().WithAttributeName("keepers")) {
+						replace = true
+					}
+				}
+				if replace != c.expectedReplace {
+					t.Errorf("expected replace: %t, got: %t", c.expectedReplace, replace)
+				}
+
+				// When the resource is replaced Terraform plans the new resource separately, with no prior state,
+				// so the result is only planned to be regenerated here when it is updated in place.
+				if c.expectedReplace {
+					return
+				}
+				for name := range attrs {
+					if name == "id" || name == "length" {
+						continue
+					}
+					if update := !planned.GetAttr(name).IsKnown(); update != c.expectedUpdate {
+						t.Errorf("expected %s to be regenerated: %t, got: %t", name, c.expectedUpdate, update)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestLogKeepersChange(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	prior := map[string]cty.Value{
+		"id":      cty.StringVal("pet-name"),
+		"keepers": cty.MapVal(map[string]cty.Value{"a": cty.StringVal("1"), "b": cty.StringVal("2")}),
+	}
+	config := map[string]cty.Value{
+		"keepers": cty.MapVal(map[string]cty.Value{"a": cty.StringVal("3"), "c": cty.StringVal("4")}),
+	}
+
+	planResourceChange(t, "random_pet", prior, config)
+
+	expected := "[INFO] keepers changed: added [c], removed [b], changed [a]"
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("expected log to contain %q, got: %s", expected, buf.String())
+	}
+	if strings.Contains(buf.String(), "pet-name") {
+		t.Errorf("expected log not to contain the ID, got: %s", buf.String())
+	}
+}
+
+func TestKeepersChanges(t *testing.T) {
+	oldKeepers := map[string]interface{}{"a": "1", "b": "2", "c": "3"}
+	newKeepers := map[string]interface{}{"a": "1", "c": "4", "d": "5", "e": "6"}
+
+	added, removed, changed := keepersChanges(oldKeepers, newKeepers)
+	if !reflect.DeepEqual(added, []string{"d", "e"}) {
+		t.Errorf("expected added keys [d e], got: %v", added)
+	}
+	if !reflect.DeepEqual(removed, []string{"b"}) {
+		t.Errorf("expected removed keys [b], got: %v", removed)
+	}
+	if !reflect.DeepEqual(changed, []string{"c"}) {
+		t.Errorf("expected changed keys [c], got: %v", changed)
+	}
+
+	added, removed, changed = keepersChanges(nil, newKeepers)
+	if len(added) != len(newKeepers) || len(removed) != 0 || len(changed) != 0 {
+		t.Errorf("expected all keys to be added, got: added %v, removed %v, changed %v", added, removed, changed)
+	}
+}
+
+// testAccKeepersUpdateSteps returns test steps that create the resource name using config, which must contain a
+// keepers argument set to the %s verb, with replace_on_keeper_change set to false, then change keepers and check that
+// a new value has been generated for each of keys.
+func testAccKeepersUpdateSteps(name, config string, keys ...string) []resource.TestStep {
+	values := make(map[string]string, len(keys))
+
+	var saveChecks, changedChecks []resource.TestCheckFunc
+	for _, key := range keys {
+		key := key
+		saveChecks = append(saveChecks, resource.TestCheckResourceAttrWith(name, key, func(value string) error {
+			values[key] = value
+			return nil
+		}))
+		changedChecks = append(changedChecks, resource.TestCheckResourceAttrWith(name, key, func(value string) error {
+			if value == values[key] {
+				return fmt.Errorf("expected a new %s after keepers changed, got: %s", key, value)
+			}
+			return nil
+		}))
+	}
+
+	return []resource.TestStep{
+		{
+			Config: fmt.Sprintf(config, `{ a = "1" }`),
+			Check: resource.ComposeTestCheckFunc(
+				append(saveChecks, resource.TestCheckResourceAttr(name, "replace_on_keeper_change", "false"))...,
+			),
+		},
+		{
+			Config: fmt.Sprintf(config, `{ a = "2" }`),
+			Check: resource.ComposeTestCheckFunc(
+				append(changedChecks, resource.TestCheckResourceAttr(name, "keepers.a", "2"))...,
+			),
+		},
+	}
+}
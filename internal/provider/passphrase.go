@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"math/big"
+	"strings"
+)
+
+// wordlist is the newline-delimited list of candidate words used when generating a passphrase. The words are
+// sourced from the word lists that accompany github.com/dustinkirkland/golang-petname.
+//
+//go:embed wordlist.txt
+var wordlist string
+
+// defaultWordlist returns the embedded list of candidate words used when generating a passphrase.
+func defaultWordlist() []string {
+	return strings.Fields(wordlist)
+}
+
+// generateRandomWords returns count words drawn uniformly, with replacement, from words.
+func generateRandomWords(words []string, count int) ([]string, error) {
+	result := make([]string, count)
+	setLen := big.NewInt(int64(len(words)))
+	for i := range result {
+		idx, err := rand.Int(rand.Reader, setLen)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = words[idx.Int64()]
+	}
+	return result, nil
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestDefaultWordlist(t *testing.T) {
+	words := defaultWordlist()
+
+	if len(words) < 2 {
+		t.Fatalf("expected at least 2 words, got: %d", len(words))
+	}
+
+	seen := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		if _, ok := seen[word]; ok {
+			t.Errorf("duplicate word: %q", word)
+		}
+		seen[word] = struct{}{}
+	}
+}
+
+func TestGenerateRandomWords(t *testing.T) {
+	words := []string{"alpha", "bravo", "charlie"}
+
+	actual, err := generateRandomWords(words, 5)
+	if err != nil {
+		t.Fatalf("err should be nil, actual: %v", err)
+	}
+
+	if len(actual) != 5 {
+		t.Errorf("expected: 5 words, got: %d", len(actual))
+	}
+
+	for _, word := range actual {
+		if word != "alpha" && word != "bravo" && word != "charlie" {
+			t.Errorf("unexpected word: %q", word)
+		}
+	}
+}
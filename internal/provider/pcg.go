@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"hash/crc64"
+	"math/bits"
+)
+
+const pcgMultiplier = 6364136223846793005
+
+// pcgRand is a PCG32 (XSH RR 64/32) random number generator, as described at https://www.pcg-random.org. Unlike
+// math/rand, whose output may change between Go releases, the generator and the methods built on it are implemented
+// here so that the same seed always produces the same sequence.
+type pcgRand struct {
+	state uint64
+	inc   uint64
+}
+
+// newPCGRand returns a pcgRand initialised as by pcg32_srandom in the reference implementation.
+func newPCGRand(initState, initSeq uint64) *pcgRand {
+	r := &pcgRand{inc: initSeq<<1 | 1}
+	r.Uint32()
+	r.state += initState
+	r.Uint32()
+	return r
+}
+
+// newStableRand returns a pcgRand whose state and sequence are derived from seed.
+func newStableRand(seed string) *pcgRand {
+	return newPCGRand(
+		crc64.Checksum([]byte(seed), crc64.MakeTable(crc64.ISO)),
+		crc64.Checksum([]byte(seed), crc64.MakeTable(crc64.ECMA)),
+	)
+}
+
+// Uint32 returns a pseudo-random 32-bit value.
+func (r *pcgRand) Uint32() uint32 {
+	old := r.state
+	r.state = old*pcgMultiplier + r.inc
+	xorShifted := uint32(((old >> 18) ^ old) >> 27)
+	return bits.RotateLeft32(xorShifted, -int(old>>59))
+}
+
+// Uint64 returns a pseudo-random 64-bit value made up of two successive 32-bit values, high bits first.
+func (r *pcgRand) Uint64() uint64 {
+	return uint64(r.Uint32())<<32 | uint64(r.Uint32())
+}
+
+// Int63n returns a uniformly distributed value in [0,n), rejecting values that would bias the result. It panics if
+// n <= 0.
+func (r *pcgRand) Int63n(n int64) int64 {
+	if n <= 0 {
+		panic("invalid argument to Int63n")
+	}
+
+	bound := uint64(n)
+	threshold := -bound % bound
+	for {
+		if v := r.Uint64(); v >= threshold {
+			return int64(v % bound)
+		}
+	}
+}
+
+// Intn returns a uniformly distributed value in [0,n). It panics if n <= 0.
+func (r *pcgRand) Intn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+	return int(r.Int63n(int64(n)))
+}
+
+// Perm returns a permutation of [0,n) produced by a Fisher-Yates shuffle.
+func (r *pcgRand) Perm(n int) []int {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	for i := n - 1; i > 0; i-- {
+		j := r.Intn(i + 1)
+		perm[i], perm[j] = perm[j], perm[i]
+	}
+	return perm
+}
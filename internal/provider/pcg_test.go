@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPCGRand(t *testing.T) {
+	// First outputs of pcg32-demo from the reference implementation, seeded with pcg32_srandom(42, 54).
+	r := newPCGRand(42, 54)
+	expected := []uint32{0xa15c02b7, 0x7b47f409, 0xba1d3330, 0x83d2f293, 0xbfa4784b, 0xcbed606e}
+
+	for i, e := range expected {
+		if actual := r.Uint32(); actual != e {
+			t.Fatalf("output %d: expected %#08x, got %#08x", i, e, actual)
+		}
+	}
+}
+
+func TestPCGRandPerm(t *testing.T) {
+	// These results are pinned and must not change, as the stable argument of random_shuffle guarantees that the
+	// same seed always produces the same permutation.
+	actual := newStableRand("-").Perm(10)
+	expected := []int{0, 4, 6, 8, 9, 1, 5, 2, 3, 7}
+
+	if !cmp.Equal(actual, expected) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
@@ -2,10 +2,22 @@ package provider
 
 import (
 	"context"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// defaultMaxStringLength is the largest length of random_string allowed when max_string_length is not configured.
+const defaultMaxStringLength = 1024
+
+// providerConfig holds the provider configuration, which is passed to resources as meta.
+type providerConfig struct {
+	maxStringLength        int
+	defaultSpecialOverride string
+	seed                   string
+}
+
 func init() {
 	schema.DescriptionKind = schema.StringMarkdown
 }
@@ -13,7 +25,40 @@ func init() {
 // New returns a *schema.Provider.
 func New() *schema.Provider {
 	return &schema.Provider{
-		Schema: map[string]*schema.Schema{},
+		Schema: map[string]*schema.Schema{
+			"max_string_length": {
+				Description: "The largest `length` allowed for a `random_string` resource, guarding against " +
+					"accidentally generating very large results. Default value is `1024`.",
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			},
+
+			"default_special_override": {
+				Description: "The special characters used by `random_string` and `random_password` resources " +
+					"that do not set `override_special`, in place of the built-in set. Changing this argument " +
+					"does not affect existing resources.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: warnDuplicateChars,
+			},
+
+			"seed": {
+				Description: "Arbitrary string from which a seed is derived for each `random_string`, `random_id`, " +
+					"`random_integer` and `random_shuffle` resource that does not set its own `seed`, so that " +
+					"their results are reproducible, e.g., in ephemeral preview environments. Resources of the " +
+					"same type with identical configuration produce the same result, so set `keepers` to tell them " +
+					"apart. Changing this argument does not affect existing resources.\n" +
+					"\n" +
+					"**Important:** When `seed` is set these resources use a non-cryptographic random number " +
+					"generator, and anyone who knows the seed and the configuration can reproduce their results. " +
+					"It is intended for non-production use only, and results must not be used as secrets.",
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+		},
+		ConfigureContextFunc: configureProvider,
 
 		ResourcesMap: map[string]*schema.Resource{
 			"random_id":       resourceId(),
@@ -23,10 +68,39 @@ func New() *schema.Provider {
 			"random_password": resourcePassword(),
 			"random_integer":  resourceInteger(),
 			"random_uuid":     resourceUuid(),
+			"random_bytes":    resourceBytes(),
+			"random_choice":   resourceChoice(),
+			"random_mac":      resourceMAC(),
+			"random_port":     resourcePort(),
+			"random_color":    resourceColor(),
+		},
+
+		DataSourcesMap: map[string]*schema.Resource{
+			"random_string": dataSourceString(),
 		},
 	}
 }
 
+func configureProvider(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	config := &providerConfig{
+		maxStringLength: defaultMaxStringLength,
+	}
+
+	if v, ok := d.GetOk("max_string_length"); ok {
+		config.maxStringLength = v.(int)
+	}
+
+	if v, ok := d.GetOk("default_special_override"); ok {
+		config.defaultSpecialOverride = v.(string)
+	}
+
+	if v, ok := d.GetOk("seed"); ok {
+		config.seed = v.(string)
+	}
+
+	return config, nil
+}
+
 func RemoveResourceFromState(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
 	d.SetId("")
 	return nil
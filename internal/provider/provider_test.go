@@ -1,9 +1,16 @@
 package provider
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 var testAccProvider *schema.Provider
@@ -26,5 +33,201 @@ func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = New()
 }
 
+func TestConfigureProvider(t *testing.T) {
+	cases := []struct {
+		name            string
+		config          map[string]interface{}
+		expected        int
+		expectedSpecial string
+		expectedSeed    string
+	}{
+		{
+			name:     "default",
+			config:   map[string]interface{}{},
+			expected: defaultMaxStringLength,
+		},
+		{
+			name:     "max_string_length",
+			config:   map[string]interface{}{"max_string_length": 4096},
+			expected: 4096,
+		},
+		{
+			name:            "default_special_override",
+			config:          map[string]interface{}{"default_special_override": "-_"},
+			expected:        defaultMaxStringLength,
+			expectedSpecial: "-_",
+		},
+		{
+			name:         "seed",
+			config:       map[string]interface{}{"seed": "preview"},
+			expected:     defaultMaxStringLength,
+			expectedSeed: "preview",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := New()
+			if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(c.config)); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			config, ok := p.Meta().(*providerConfig)
+			if !ok {
+				t.Fatalf("expected meta to be *providerConfig, got %T", p.Meta())
+			}
+			if config.maxStringLength != c.expected {
+				t.Errorf("expected max_string_length %d, got %d", c.expected, config.maxStringLength)
+			}
+			if config.defaultSpecialOverride != c.expectedSpecial {
+				t.Errorf("expected default_special_override %q, got %q", c.expectedSpecial, config.defaultSpecialOverride)
+			}
+			if config.seed != c.expectedSeed {
+				t.Errorf("expected seed %q, got %q", c.expectedSeed, config.seed)
+			}
+		})
+	}
+}
+
+func TestAccProviderSeed(t *testing.T) {
+	names := []string{"random_string.seeded", "random_id.seeded", "random_integer.seeded", "random_shuffle.seeded"}
+	keys := []string{"result", "hex", "result", "result_string"}
+	values := make(map[string]string, len(names))
+
+	var saveChecks, sameChecks []resource.TestCheckFunc
+	for i, name := range names {
+		name, key := name, keys[i]
+		saveChecks = append(saveChecks, resource.TestCheckResourceAttrWith(name, key, func(value string) error {
+			values[name] = value
+			return nil
+		}))
+		sameChecks = append(sameChecks, resource.TestCheckResourceAttrWith(name, key, func(value string) error {
+			if value != values[name] {
+				return fmt.Errorf("expected %s of %s to be reproduced as %s, got: %s", key, name, values[name], value)
+			}
+			return nil
+		}))
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderSeedConfig,
+				Check:  resource.ComposeTestCheckFunc(saveChecks...),
+			},
+			{
+				Config: testAccProviderSeedConfig,
+				Taint:  names,
+				Check:  resource.ComposeTestCheckFunc(sameChecks...),
+			},
+		},
+	})
+}
+
+const testAccProviderSeedConfig = `
+provider "random" {
+  seed = "preview"
+}
+
+resource "random_string" "seeded" {
+  length = 16
+}
+
+resource "random_id" "seeded" {
+  byte_length = 8
+}
+
+resource "random_integer" "seeded" {
+  min = 1
+  max = 1000000
+}
+
+resource "random_shuffle" "seeded" {
+  input = ["a", "b", "c", "d", "e"]
+}`
+
 func testAccPreCheck(t *testing.T) {
 }
+
+// planResourceChange plans the resource of type typeName with the given config using the gRPC provider server, as
+// Terraform does, returning the planned state and the response. When prior is nil the resource is planned to be
+// created, otherwise prior holds the values of the attributes in the prior state that differ from the config. The
+// proposed new state is built from the config and prior state in the same way as by Terraform, by using the prior
+// value of computed attributes that are not set.
+func planResourceChange(t *testing.T, typeName string, prior, config map[string]cty.Value) (cty.Value, *tfprotov5.PlanResourceChangeResponse) {
+	t.Helper()
+
+	resp := planResourceChangeResponse(t, typeName, prior, config)
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Fatalf("unexpected error: %s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	planned, err := msgpack.Unmarshal(resp.PlannedState.MsgPack, New().ResourcesMap[typeName].CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return planned, resp
+}
+
+// planResourceChangeResponse plans the resource as planResourceChange does, returning the response without checking
+// its diagnostics.
+func planResourceChangeResponse(t *testing.T, typeName string, prior, config map[string]cty.Value) *tfprotov5.PlanResourceChangeResponse {
+	t.Helper()
+
+	p := New()
+	r := p.ResourcesMap[typeName]
+	ty := r.CoreConfigSchema().ImpliedType()
+
+	configAttrs := map[string]cty.Value{}
+	for name, attrType := range ty.AttributeTypes() {
+		configAttrs[name] = cty.NullVal(attrType)
+	}
+	for k, v := range config {
+		configAttrs[k] = v
+	}
+	configVal := cty.ObjectVal(configAttrs)
+
+	priorVal := cty.NullVal(ty)
+	proposedVal := configVal
+	if prior != nil {
+		priorAttrs := map[string]cty.Value{}
+		proposedAttrs := map[string]cty.Value{}
+		for name, v := range configAttrs {
+			priorAttrs[name] = v
+			proposedAttrs[name] = v
+		}
+		for k, v := range prior {
+			priorAttrs[k] = v
+		}
+		for name, v := range priorAttrs {
+			if r.Schema[name].Computed && configAttrs[name].IsNull() {
+				proposedAttrs[name] = v
+			}
+		}
+		priorVal = cty.ObjectVal(priorAttrs)
+		proposedVal = cty.ObjectVal(proposedAttrs)
+	}
+
+	encode := func(v cty.Value) *tfprotov5.DynamicValue {
+		b, err := msgpack.Marshal(v, ty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &tfprotov5.DynamicValue{MsgPack: b}
+	}
+
+	resp, err := schema.NewGRPCProviderServer(p).PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       encode(priorVal),
+		ProposedNewState: encode(proposedVal),
+		Config:           encode(configVal),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
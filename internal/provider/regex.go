@@ -0,0 +1,242 @@
+package provider
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxRegexRepeat bounds the upper bound of a single quantifier, so that a regex cannot ask for an unreasonably long
+// result.
+const maxRegexRepeat = 1000
+
+// regexAtom is a set of characters that is repeated between min and max times, inclusive.
+type regexAtom struct {
+	chars []rune
+	min   int
+	max   int
+}
+
+// parseRegex parses a restricted regular expression made up of a sequence of literal characters, escaped characters,
+// \d, \w and character classes such as [A-Za-z0-9_-], each optionally followed by a bounded quantifier, i.e., ?, {n}
+// or {m,n}. Unbounded quantifiers, anchors, ., groups, alternation and negated character classes are rejected, as
+// they either cannot be generated from or are better expressed with grammar.
+func parseRegex(spec string) ([]regexAtom, error) {
+	runes := []rune(spec)
+	if len(runes) == 0 {
+		return nil, fmt.Errorf("regex must not be empty")
+	}
+
+	var atoms []regexAtom
+	for i := 0; i < len(runes); {
+		chars, next, err := parseRegexAtom(runes, i)
+		if err != nil {
+			return nil, err
+		}
+
+		atom := regexAtom{chars: chars, min: 1, max: 1}
+		if next < len(runes) {
+			next, err = parseRegexQuantifier(runes, next, &atom)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		atoms = append(atoms, atom)
+		i = next
+	}
+
+	return atoms, nil
+}
+
+// parseRegexAtom parses the atom starting at runes[i], returning its distinct characters in sorted order and the
+// position following it.
+func parseRegexAtom(runes []rune, i int) ([]rune, int, error) {
+	switch c := runes[i]; c {
+	case '[':
+		return parseRegexClass(runes, i+1)
+	case '\\':
+		if i+1 >= len(runes) {
+			return nil, 0, fmt.Errorf("regex must not end with \\")
+		}
+		chars, err := regexEscape(runes[i+1])
+		if err != nil {
+			return nil, 0, err
+		}
+		return chars, i + 2, nil
+	case '*', '+':
+		return nil, 0, fmt.Errorf("unbounded quantifier %c is not supported, use {m,n} instead", c)
+	case '?', '{':
+		return nil, 0, fmt.Errorf("quantifier %c at position %d must follow a character or character class", c, i)
+	case '^', '$':
+		return nil, 0, fmt.Errorf("anchor %c is not supported, the whole result always matches regex", c)
+	case '.':
+		return nil, 0, fmt.Errorf(". is not supported, use a character class such as [a-z] instead")
+	case '(', ')', '|':
+		return nil, 0, fmt.Errorf("groups and alternation (%c) are not supported, use grammar instead", c)
+	case ']', '}':
+		return nil, 0, fmt.Errorf("unexpected %c at position %d, use \\%c for a literal %c", c, i, c, c)
+	default:
+		return []rune{c}, i + 1, nil
+	}
+}
+
+// parseRegexClass parses the character class whose first character is at runes[i], returning its distinct
+// characters in sorted order and the position following the closing ].
+func parseRegexClass(runes []rune, i int) ([]rune, int, error) {
+	if i < len(runes) && runes[i] == '^' {
+		return nil, 0, fmt.Errorf("negated character classes are not supported, list the characters to include instead")
+	}
+
+	set := map[rune]bool{}
+	for ; i < len(runes) && runes[i] != ']'; i++ {
+		lo := runes[i]
+		if lo == '\\' {
+			if i+1 >= len(runes) {
+				return nil, 0, fmt.Errorf("unterminated character class, expected ]")
+			}
+			i++
+			chars, err := regexEscape(runes[i])
+			if err != nil {
+				return nil, 0, err
+			}
+			for _, c := range chars {
+				set[c] = true
+			}
+			continue
+		}
+
+		if i+2 < len(runes) && runes[i+1] == '-' && runes[i+2] != ']' {
+			hi := runes[i+2]
+			if hi == '\\' {
+				return nil, 0, fmt.Errorf("character class range %c-\\ must end with an unescaped character", lo)
+			}
+			if hi < lo {
+				return nil, 0, fmt.Errorf("character class range %c-%c is out of order", lo, hi)
+			}
+			for c := lo; c <= hi; c++ {
+				set[c] = true
+			}
+			i += 2
+			continue
+		}
+
+		set[lo] = true
+	}
+
+	if i >= len(runes) {
+		return nil, 0, fmt.Errorf("unterminated character class, expected ]")
+	}
+	if len(set) == 0 {
+		return nil, 0, fmt.Errorf("character class must not be empty")
+	}
+
+	chars := make([]rune, 0, len(set))
+	for c := range set {
+		chars = append(chars, c)
+	}
+	sort.Slice(chars, func(a, b int) bool { return chars[a] < chars[b] })
+
+	return chars, i + 1, nil
+}
+
+// regexEscape returns the characters matched by c when it is preceded by \.
+func regexEscape(c rune) ([]rune, error) {
+	switch {
+	case c == 'd':
+		return []rune(numChars), nil
+	case c == 'w':
+		return []rune(upperChars + "_" + lowerChars + numChars), nil
+	case c == 'b' || c == 'B' || c == 'A' || c == 'z' || c == 'Z':
+		return nil, fmt.Errorf("anchor \\%c is not supported, the whole result always matches regex", c)
+	case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9':
+		return nil, fmt.Errorf("escape \\%c is not supported, only \\d, \\w and escaped punctuation are", c)
+	default:
+		return []rune{c}, nil
+	}
+}
+
+// parseRegexQuantifier parses any quantifier at runes[i] into atom, returning the position following it.
+func parseRegexQuantifier(runes []rune, i int, atom *regexAtom) (int, error) {
+	switch c := runes[i]; c {
+	case '?':
+		atom.min, atom.max = 0, 1
+		i++
+	case '{':
+		end := i + 1
+		for end < len(runes) && runes[end] != '}' {
+			end++
+		}
+		if end >= len(runes) {
+			return 0, fmt.Errorf("unterminated quantifier, expected }")
+		}
+
+		bounds := strings.SplitN(string(runes[i+1:end]), ",", 2)
+		min, err := strconv.Atoi(bounds[0])
+		if err != nil || min < 0 {
+			return 0, fmt.Errorf("invalid quantifier {%s}, expected {n} or {m,n}", string(runes[i+1:end]))
+		}
+		max := min
+		if len(bounds) == 2 {
+			if bounds[1] == "" {
+				return 0, fmt.Errorf("unbounded quantifier {%d,} is not supported, use {m,n} instead", min)
+			}
+			max, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				return 0, fmt.Errorf("invalid quantifier {%s}, expected {n} or {m,n}", string(runes[i+1:end]))
+			}
+		}
+		if max < min {
+			return 0, fmt.Errorf("quantifier {%d,%d} is out of order", min, max)
+		}
+		if max > maxRegexRepeat {
+			return 0, fmt.Errorf("quantifier {%s} must not repeat more than %d times", string(runes[i+1:end]), maxRegexRepeat)
+		}
+
+		atom.min, atom.max = min, max
+		i = end + 1
+	case '*', '+':
+		return 0, fmt.Errorf("unbounded quantifier %c is not supported, use {m,n} instead", c)
+	default:
+		return i, nil
+	}
+
+	if i < len(runes) {
+		switch runes[i] {
+		case '?', '{', '*', '+':
+			return 0, fmt.Errorf("quantifier %c at position %d must follow a character or character class", runes[i], i)
+		}
+	}
+
+	return i, nil
+}
+
+// generateRegex returns a random string matching atoms, choosing the number of repetitions of each atom and each of
+// the characters uniformly using reader.
+func generateRegex(reader io.Reader, atoms []regexAtom) ([]byte, error) {
+	var b strings.Builder
+	for _, atom := range atoms {
+		count := atom.min
+		if atom.max > atom.min {
+			n, err := rand.Int(reader, big.NewInt(int64(atom.max-atom.min+1)))
+			if err != nil {
+				return nil, err
+			}
+			count += int(n.Int64())
+		}
+
+		indices, err := randomIndices(reader, len(atom.chars), count)
+		if err != nil {
+			return nil, err
+		}
+		for _, idx := range indices {
+			b.WriteRune(atom.chars[idx])
+		}
+	}
+
+	return []byte(b.String()), nil
+}
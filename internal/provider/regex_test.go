@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"crypto/rand"
+	"regexp"
+	"testing"
+)
+
+func TestParseRegex(t *testing.T) {
+	cases := []struct {
+		name     string
+		spec     string
+		expected string
+	}{
+		{"classes and quantifiers", `[A-Z]{3}-\d{2,4}[a-z_]?`, ""},
+		{"escaped punctuation", `\.\*\[\]\{\}`, ""},
+		{"literal dash in class", `[-a-c]{2}[a-c-]`, ""},
+		{"empty", ``, "regex must not be empty"},
+		{"star", `[a-z]*`, "unbounded quantifier * is not supported, use {m,n} instead"},
+		{"plus", `a+`, "unbounded quantifier + is not supported, use {m,n} instead"},
+		{"open range", `a{2,}`, "unbounded quantifier {2,} is not supported, use {m,n} instead"},
+		{"start anchor", `^abc`, "anchor ^ is not supported, the whole result always matches regex"},
+		{"end anchor", `abc$`, "anchor $ is not supported, the whole result always matches regex"},
+		{"word boundary", `\babc`, `anchor \b is not supported, the whole result always matches regex`},
+		{"dot", `a.c`, ". is not supported, use a character class such as [a-z] instead"},
+		{"group", `(ab){2}`, "groups and alternation (() are not supported, use grammar instead"},
+		{"alternation", `a|b`, "groups and alternation (|) are not supported, use grammar instead"},
+		{"negated class", `[^a]`, "negated character classes are not supported, list the characters to include instead"},
+		{"empty class", `[]`, "character class must not be empty"},
+		{"unterminated class", `[a-z`, "unterminated character class, expected ]"},
+		{"reversed range", `[z-a]`, "character class range z-a is out of order"},
+		{"leading quantifier", `{2}a`, "quantifier { at position 0 must follow a character or character class"},
+		{"repeated quantifier", `a{2}?`, "quantifier ? at position 4 must follow a character or character class"},
+		{"unterminated quantifier", `a{2`, "unterminated quantifier, expected }"},
+		{"invalid quantifier", `a{x}`, "invalid quantifier {x}, expected {n} or {m,n}"},
+		{"reversed quantifier", `a{3,2}`, "quantifier {3,2} is out of order"},
+		{"too many repeats", `a{1001}`, "quantifier {1001} must not repeat more than 1000 times"},
+		{"unsupported escape", `\s`, `escape \s is not supported, only \d, \w and escaped punctuation are`},
+		{"trailing backslash", `a\`, `regex must not end with \`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := parseRegex(c.spec)
+			if c.expected == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != c.expected {
+				t.Errorf("expected error %q, got %v", c.expected, err)
+			}
+		})
+	}
+}
+
+func TestGenerateRegex(t *testing.T) {
+	cases := []string{
+		`[A-Z]{3}[0-9]{4}`,
+		`\w{8,12}`,
+		`SKU-\d{3}[a-c-]?`,
+		`[é\-]{2}x{0,3}`,
+	}
+
+	for _, spec := range cases {
+		t.Run(spec, func(t *testing.T) {
+			atoms, err := parseRegex(spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			re := regexp.MustCompile(`^(?:` + spec + `)$`)
+			for i := 0; i < 100; i++ {
+				result, err := generateRegex(rand.Reader, atoms)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !re.Match(result) {
+					t.Fatalf("result %q does not match %s", result, spec)
+				}
+			}
+		})
+	}
+}
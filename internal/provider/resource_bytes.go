@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceBytes() *schema.Resource {
+	return &schema.Resource{
+		Description: "The resource `random_bytes` generates random bytes that are intended to be used as a " +
+			"secret, or key. Use this in preference to `random_id` when the output is considered sensitive, " +
+			"and should not be displayed in the CLI.\n" +
+			"\n" +
+			"This resource *does* use a cryptographic random number generator.",
+		CreateContext: CreateBytes,
+		ReadContext:   schema.NoopContext,
+		DeleteContext: RemoveResourceFromState,
+		CustomizeDiff: logKeepersChange,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportBytes,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"keepers": {
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+					"resource. See [the main provider documentation](../index.html) for more information.",
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"length": {
+				Description:      "The number of bytes requested. The minimum value for length is 1.",
+				Type:             schema.TypeInt,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			},
+
+			"shamir": shamirSchema(),
+
+			"base64": {
+				Description: "The generated bytes presented in base64 string format.",
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+			},
+
+			"b32_std": {
+				Description: "The generated bytes presented in standard RFC 4648 base32 string format, using the " +
+					"uppercase letters and the digits `2` to `7`, padded with `=`, e.g., for TOTP secrets.",
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"b32_nopad": {
+				Description: "The generated bytes presented in base32 string format like `b32_std`, but without " +
+					"`=` padding.",
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"hex": {
+				Description: "The generated bytes presented in lowercase hexadecimal string format. The length " +
+					"of the encoded string is exactly twice the `length` parameter.",
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"shamir_shares": shamirSharesSchema(),
+
+			"generation": generationSchema(false),
+
+			"id": {
+				Description: "A static value used internally by Terraform, this should not be referenced in configurations.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func CreateBytes(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	parts, threshold, err := shamirConfig(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	bytes := make([]byte, d.Get("length").(int))
+
+	if err := retryRandom(func() error {
+		_, err := rand.Read(bytes)
+		return err
+	}); err != nil {
+		return diag.Errorf("error generating random bytes: %s", err)
+	}
+
+	if err := setBytesEncodings(d, bytes); err != nil {
+		return diag.Errorf("error setting encodings: %s", err)
+	}
+
+	shares, err := shamirShares(rand.Reader, bytes, parts, threshold)
+	if err != nil {
+		return diag.Errorf("error splitting random bytes: %s", err)
+	}
+
+	if err := d.Set("shamir_shares", shares); err != nil {
+		return diag.Errorf("error setting shamir_shares: %s", err)
+	}
+
+	if err := setGeneration(d); err != nil {
+		return diag.Errorf("error setting generation: %s", err)
+	}
+
+	d.SetId("none")
+
+	return nil
+}
+
+// ImportBytes imports a random_bytes from its base64 value or, when prefixed with b32_std:, its b32_std or b32_nopad
+// value.
+func ImportBytes(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	var bytes []byte
+	var err error
+	if id := d.Id(); strings.HasPrefix(id, b32StdImportPrefix) {
+		bytes, err = decodeB32Std(strings.TrimPrefix(id, b32StdImportPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("error decoding b32_std ID: %w", err)
+		}
+	} else {
+		bytes, err = base64.StdEncoding.DecodeString(id)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding base64 ID: %w", err)
+		}
+	}
+	if len(bytes) == 0 {
+		return nil, fmt.Errorf("ID must decode to at least 1 byte")
+	}
+
+	if err := d.Set("length", len(bytes)); err != nil {
+		return nil, fmt.Errorf("error setting length: %w", err)
+	}
+
+	if err := setBytesEncodings(d, bytes); err != nil {
+		return nil, fmt.Errorf("error setting encodings: %w", err)
+	}
+
+	if err := d.Set("generation", 1); err != nil {
+		return nil, fmt.Errorf("error setting generation: %w", err)
+	}
+
+	d.SetId("none")
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// setBytesEncodings sets base64, b32_std, b32_nopad and hex to the encodings of bytes.
+func setBytesEncodings(d *schema.ResourceData, bytes []byte) error {
+	if err := d.Set("base64", base64.StdEncoding.EncodeToString(bytes)); err != nil {
+		return err
+	}
+
+	if err := d.Set("b32_std", base32.StdEncoding.EncodeToString(bytes)); err != nil {
+		return err
+	}
+
+	if err := d.Set("b32_nopad", b32Encoding.EncodeToString(bytes)); err != nil {
+		return err
+	}
+
+	return d.Set("hex", hex.EncodeToString(bytes))
+}
@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccResourceBytes(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceBytesConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith("random_bytes.basic", "base64", testCheckLen(44)),
+					resource.TestCheckResourceAttrWith("random_bytes.basic", "hex", testCheckLen(64)),
+					resource.TestMatchResourceAttr("random_bytes.basic", "b32_std", regexp.MustCompile(`^[A-Z2-7]{52}====$`)),
+					resource.TestMatchResourceAttr("random_bytes.basic", "b32_nopad", regexp.MustCompile(`^[A-Z2-7]{52}$`)),
+					resource.TestMatchResourceAttr("random_bytes.basic", "hex", regexp.MustCompile(`^[0-9a-f]+$`)),
+					resource.TestCheckResourceAttr("random_bytes.basic", "length", "32"),
+				),
+			},
+			{
+				ResourceName:      "random_bytes.basic",
+				ImportState:       true,
+				ImportStateIdFunc: testAccResourceBytesImportID("random_bytes.basic", "base64", ""),
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "random_bytes.basic",
+				ImportState:       true,
+				ImportStateIdFunc: testAccResourceBytesImportID("random_bytes.basic", "b32_std", b32StdImportPrefix),
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "random_bytes.basic",
+				ImportState:       true,
+				ImportStateIdFunc: testAccResourceBytesImportID("random_bytes.basic", "b32_nopad", b32StdImportPrefix),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceBytes_shamir(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceBytesConfigShamir,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("random_bytes.shamir", "shamir_shares.#", "5"),
+					testAccShamirCheck("random_bytes.shamir", 3),
+				),
+			},
+			{
+				Config:      testAccResourceBytesConfigShamirInvalid,
+				ExpectError: regexp.MustCompile(`shamir threshold \(4\) must not exceed parts \(3\)`),
+			},
+		},
+	})
+}
+
+func TestCreateBytesShamir(t *testing.T) {
+	r := resourceBytes()
+	d := r.TestResourceData()
+	if err := d.Set("length", 16); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Set("shamir", []interface{}{map[string]interface{}{"parts": 5, "threshold": 3}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if diags := CreateBytes(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	var shares [][]byte
+	for _, v := range d.Get("shamir_shares").([]interface{}) {
+		share, err := hex.DecodeString(v.(string))
+		if err != nil {
+			t.Fatal(err)
+		}
+		shares = append(shares, share)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("expected 5 shares, got %d", len(shares))
+	}
+
+	secret, err := shamirCombine(shares[1:4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := hex.EncodeToString(secret), d.Get("hex").(string); got != want {
+		t.Errorf("expected shares to combine to %s, got %s", want, got)
+	}
+
+	secret, err = shamirCombine(shares[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(secret) == d.Get("hex").(string) {
+		t.Error("expected 2 shares not to reconstruct the generated bytes")
+	}
+}
+
+func TestAccResourceBytes_importInvalid(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceBytesConfig,
+			},
+			{
+				ResourceName:  "random_bytes.basic",
+				ImportState:   true,
+				ImportStateId: "not base64!",
+				ExpectError:   regexp.MustCompile(`error decoding base64 ID`),
+			},
+		},
+	})
+}
+
+func TestAccResourceBytes_lengthInvalid(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceBytesConfigLengthZero,
+				ExpectError: regexp.MustCompile(`.*expected length to be at least \(1\), got 0`),
+			},
+		},
+	})
+}
+
+// testAccResourceBytesImportID returns the value of key of the named resource,
+// preceded by prefix, as an import ID.
+func testAccResourceBytesImportID(id, key, prefix string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", id)
+		}
+
+		return prefix + rs.Primary.Attributes[key], nil
+	}
+}
+
+func testCheckLen(expectedLen int) resource.CheckResourceAttrWithFunc {
+	return func(value string) error {
+		if len(value) != expectedLen {
+			return fmt.Errorf("expected length %d, actual length %d", expectedLen, len(value))
+		}
+
+		return nil
+	}
+}
+
+const (
+	testAccResourceBytesConfig = `
+resource "random_bytes" "basic" {
+  length = 32
+}`
+
+	testAccResourceBytesConfigShamir = `
+resource "random_bytes" "shamir" {
+  length = 16
+
+  shamir {
+    parts     = 5
+    threshold = 3
+  }
+}`
+
+	testAccResourceBytesConfigShamirInvalid = `
+resource "random_bytes" "shamir" {
+  length = 16
+
+  shamir {
+    parts     = 3
+    threshold = 4
+  }
+}`
+
+	testAccResourceBytesConfigLengthZero = `
+resource "random_bytes" "basic" {
+  length = 0
+}`
+)
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceChoice() *schema.Resource {
+	return &schema.Resource{
+		Description: "The resource `random_choice` chooses a single random item from a list of strings given as " +
+			"an argument. Use this in preference to `random_shuffle` with a `result_count` of `1` when only one " +
+			"item is required.",
+		CreateContext: CreateChoice,
+		ReadContext:   schema.NoopContext,
+		DeleteContext: RemoveResourceFromState,
+		CustomizeDiff: logKeepersChange,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportChoice,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"keepers": {
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+					"resource. See [the main provider documentation](../index.html) for more information.",
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"seed": {
+				Description: "Arbitrary string with which to seed the random number generator, in order to " +
+					"produce less-volatile choices.\n" +
+					"\n" +
+					"**Important:** Even with an identical seed, it is not guaranteed that the same choice " +
+					"will be produced across different versions of Terraform. This argument causes the " +
+					"result to be *less volatile*, but not fixed for all time.",
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"input": {
+				Description: "The list of strings to choose from. Must contain at least one item.",
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"weights": {
+				Description: "A list of positive integers, one for each item in `input`, giving the relative " +
+					"likelihood of each item being chosen, e.g., `[70, 30]` to choose the first of two items 70% " +
+					"of the time. Defaults to choosing every item with equal probability.",
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+
+			"result": {
+				Description: "The item chosen from the list of strings given in `input`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+
+			"generation": generationSchema(false),
+
+			"id": {
+				Description: "A static value used internally by Terraform, this should not be referenced in configurations.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func CreateChoice(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	input := d.Get("input").([]interface{})
+	if len(input) == 0 {
+		return diag.Errorf("unable to choose an item from an empty input list")
+	}
+
+	weights, diags := getWeights(d, len(input))
+	if diags.HasError() {
+		return diags
+	}
+
+	rand := NewRand(d.Get("seed").(string))
+
+	// A single item is chosen directly rather than by generating a permutation of every item in input.
+	var chosen int
+	if weights != nil {
+		var total int64
+		for _, w := range weights {
+			total += int64(w)
+		}
+		chosen = weightedIndex(rand, weights, total)
+	} else {
+		chosen = rand.Intn(len(input))
+	}
+
+	d.SetId("-")
+
+	if err := d.Set("result", input[chosen]); err != nil {
+		return diag.Errorf("error setting result: %s", err)
+	}
+
+	if err := setGeneration(d); err != nil {
+		return diag.Errorf("error setting generation: %s", err)
+	}
+
+	return nil
+}
+
+func ImportChoice(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	if err := d.Set("result", d.Id()); err != nil {
+		return nil, fmt.Errorf("error setting result: %w", err)
+	}
+
+	if err := d.Set("generation", 1); err != nil {
+		return nil, fmt.Errorf("error setting generation: %w", err)
+	}
+
+	d.SetId("-")
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// As with random_shuffle, these results depend on the Go "rand" package, which does not guarantee that the same
+// seed produces the same results forever.
+func TestAccResourceChoice(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceChoiceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("random_choice.basic", "result", "d"),
+					resource.TestCheckResourceAttr("random_choice.weighted", "result", "e"),
+				),
+			},
+			{
+				ResourceName:            "random_choice.basic",
+				ImportState:             true,
+				ImportStateId:           "d",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"input", "seed"},
+			},
+		},
+	})
+}
+
+func TestAccResourceChoiceErrors(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceChoiceConfigEmpty,
+				ExpectError: regexp.MustCompile(`.*unable to choose an item from an empty input list`),
+			},
+			{
+				Config:      testAccResourceChoiceConfigWeightsWrongLength,
+				ExpectError: regexp.MustCompile(`.*weights must contain one item for each item in input, got 2 weights for 3 items`),
+			},
+		},
+	})
+}
+
+const (
+	testAccResourceChoiceConfig = `
+resource "random_choice" "basic" {
+    input = ["a", "b", "c", "d", "e"]
+    seed = "-"
+}
+
+resource "random_choice" "weighted" {
+    input = ["a", "b", "c", "d", "e"]
+    seed = "-"
+    weights = [1, 1, 1, 1, 96]
+}
+`
+
+	testAccResourceChoiceConfigEmpty = `
+resource "random_choice" "empty" {
+    input = []
+}
+`
+
+	testAccResourceChoiceConfigWeightsWrongLength = `
+resource "random_choice" "weighted" {
+    input = ["a", "b", "c"]
+    weights = [1, 2]
+}
+`
+)
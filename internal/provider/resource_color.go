@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// maxColorAttempts is the number of random colors generated before giving up on finding one within the configured
+// brightness. It is larger than maxGenerateAttempts as generating a color is cheap and a narrow brightness range
+// rejects most colors.
+const maxColorAttempts = 10000
+
+func resourceColor() *schema.Resource {
+	return &schema.Resource{
+		Description: "The resource `random_color` generates a random RGB color, e.g., to give labels in a " +
+			"dashboard distinguishable colors, optionally constrained to a range of brightness so that the " +
+			"color remains readable.\n" +
+			"\n" +
+			"This resource *does* use a cryptographic random number generator.",
+		CreateContext: CreateColor,
+		ReadContext:   schema.NoopContext,
+		DeleteContext: RemoveResourceFromState,
+		CustomizeDiff: logKeepersChange,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportColor,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"keepers": {
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+					"resource. See [the main provider documentation](../index.html) for more information.",
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"min_brightness": {
+				Description: "The minimum perceived brightness of the color, from `0` (black) to `255` " +
+					"(white), computed as `0.299 * r + 0.587 * g + 0.114 * b`. Default value is `0`.",
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(0, 255)),
+			},
+
+			"max_brightness": {
+				Description: "The maximum perceived brightness of the color, from `0` (black) to `255` " +
+					"(white), computed in the same way as for `min_brightness`. Default value is `255`.",
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(0, 255)),
+			},
+
+			"hex": {
+				Description: "The generated color as a lowercase hex triplet, e.g., `#1a2b3c`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+
+			"r": {
+				Description: "The red component of the generated color, from `0` to `255`.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+
+			"g": {
+				Description: "The green component of the generated color, from `0` to `255`.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+
+			"b": {
+				Description: "The blue component of the generated color, from `0` to `255`.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+
+			"generation": generationSchema(false),
+
+			"id": {
+				Description: "The generated color as a lowercase hex triplet.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func CreateColor(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	minBrightness := 0
+	if v, ok := d.GetOk("min_brightness"); ok {
+		minBrightness = v.(int)
+	}
+
+	maxBrightness := 255
+	if v, ok := d.GetOk("max_brightness"); ok {
+		maxBrightness = v.(int)
+	}
+
+	if maxBrightness < minBrightness {
+		return diag.Errorf("min_brightness (%d) must be less than or equal to max_brightness (%d)", minBrightness, maxBrightness)
+	}
+
+	color, err := generateColor(rand.Reader, minBrightness, maxBrightness)
+	if err != nil {
+		return diag.Errorf("error generating color: %s", err)
+	}
+
+	if err := setColor(d, color); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setGeneration(d); err != nil {
+		return diag.Errorf("error setting generation: %s", err)
+	}
+
+	return nil
+}
+
+func ImportColor(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	id := d.Id()
+
+	color, err := hex.DecodeString(strings.TrimPrefix(id, "#"))
+	if err != nil || len(color) != 3 || !strings.HasPrefix(id, "#") {
+		return nil, fmt.Errorf("Invalid import usage: expecting a color of the form #rrggbb, got %q", id)
+	}
+
+	if err := setColor(d, color); err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("generation", 1); err != nil {
+		return nil, fmt.Errorf("error setting generation: %w", err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// generateColor reads random colors from reader until one has a brightness between min and max inclusive, returning
+// its red, green and blue components.
+func generateColor(reader io.Reader, min, max int) ([]byte, error) {
+	color := make([]byte, 3)
+	for attempt := 0; attempt < maxColorAttempts; attempt++ {
+		if _, err := io.ReadFull(reader, color); err != nil {
+			return nil, err
+		}
+
+		if brightness := colorBrightness(color); brightness >= min && brightness <= max {
+			return color, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unable to generate a color with a brightness between %d and %d after %d attempts, "+
+		"consider widening the range given by min_brightness and max_brightness", min, max, maxColorAttempts)
+}
+
+// colorBrightness returns the perceived brightness of color, from 0 to 255, weighting its red, green and blue
+// components as for the luma of ITU-R BT.601.
+func colorBrightness(color []byte) int {
+	return (299*int(color[0]) + 587*int(color[1]) + 114*int(color[2]) + 500) / 1000
+}
+
+// setColor sets id, hex and the r, g and b components of color in d.
+func setColor(d *schema.ResourceData, color []byte) error {
+	hexStr := "#" + hex.EncodeToString(color)
+
+	d.SetId(hexStr)
+
+	if err := d.Set("hex", hexStr); err != nil {
+		return fmt.Errorf("error setting hex: %w", err)
+	}
+
+	for i, k := range []string{"r", "g", "b"} {
+		if err := d.Set(k, int(color[i])); err != nil {
+			return fmt.Errorf("error setting %s: %w", k, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccResourceColor(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceColorConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_color.basic", "hex", regexp.MustCompile(`^#[0-9a-f]{6}$`)),
+					resource.TestCheckResourceAttrPair("random_color.basic", "id", "random_color.basic", "hex"),
+					testAccResourceColorCheck("random_color.basic", 0, 255),
+					testAccResourceColorCheck("random_color.dark", 0, 64),
+				),
+			},
+			{
+				ResourceName:      "random_color.basic",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:            "random_color.dark",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"max_brightness"},
+			},
+		},
+	})
+}
+
+func TestAccResourceColorErrors(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceColorConfigMinGreaterThanMax,
+				ExpectError: regexp.MustCompile(`min_brightness \(200\) must be less than or equal to max_brightness \(100\)`),
+			},
+		},
+	})
+}
+
+func TestAccResourceColor_importInvalid(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceColorConfig,
+			},
+			{
+				ResourceName:  "random_color.basic",
+				ImportState:   true,
+				ImportStateId: "1a2b3c",
+				ExpectError:   regexp.MustCompile(`expecting a color of the form #rrggbb, got "1a2b3c"`),
+			},
+		},
+	})
+}
+
+func TestGenerateColor(t *testing.T) {
+	// The first color has a brightness of 255 and the second of 0, so only the third is between 100 and 200.
+	random := []byte{0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x80, 0x80, 0x80}
+
+	color, err := generateColor(bytes.NewReader(random), 100, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(color, []byte{0x80, 0x80, 0x80}) {
+		t.Errorf("got %x; want 808080", color)
+	}
+
+	if _, err := generateColor(bytes.NewReader(random), 100, 120); err == nil {
+		t.Error("expected error generating a color from insufficient random bytes")
+	}
+}
+
+func TestColorBrightness(t *testing.T) {
+	cases := []struct {
+		color    []byte
+		expected int
+	}{
+		{[]byte{0x00, 0x00, 0x00}, 0},
+		{[]byte{0xff, 0xff, 0xff}, 255},
+		{[]byte{0xff, 0x00, 0x00}, 76},
+		{[]byte{0x00, 0xff, 0x00}, 150},
+		{[]byte{0x00, 0x00, 0xff}, 29},
+	}
+
+	for _, c := range cases {
+		if got := colorBrightness(c.color); got != c.expected {
+			t.Errorf("color %x: got %d; want %d", c.color, got, c.expected)
+		}
+	}
+}
+
+// testAccResourceColorCheck verifies that the r, g and b values of the named resource match its hex value and that
+// its brightness is between min and max.
+func testAccResourceColorCheck(name string, min, max int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("not found: %s", name)
+		}
+
+		var color []byte
+		for _, k := range []string{"r", "g", "b"} {
+			v, err := strconv.Atoi(rs.Primary.Attributes[k])
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %w", k, err)
+			}
+			color = append(color, byte(v))
+		}
+
+		if got, want := rs.Primary.Attributes["hex"], fmt.Sprintf("#%x", color); got != want {
+			return fmt.Errorf("hex is %q; want %q", got, want)
+		}
+
+		if brightness := colorBrightness(color); brightness < min || brightness > max {
+			return fmt.Errorf("brightness is %d; want between %d and %d", brightness, min, max)
+		}
+
+		return nil
+	}
+}
+
+const (
+	testAccResourceColorConfig = `
+resource "random_color" "basic" {
+}
+
+resource "random_color" "dark" {
+  max_brightness = 64
+}
+`
+
+	testAccResourceColorConfigMinGreaterThanMax = `
+resource "random_color" "invalid" {
+  min_brightness = 200
+  max_brightness = 100
+}
+`
+)
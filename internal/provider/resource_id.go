@@ -2,15 +2,23 @@ package provider
 
 import (
 	"context"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"math/big"
+	"net"
 	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceId() *schema.Resource {
@@ -30,68 +38,237 @@ unique names during the brief period where both the old and new resources
 exist concurrently.
 `,
 		CreateContext: CreateID,
-		ReadContext:   RepopulateEncodings,
+		ReadContext:   ReadID,
+		UpdateContext: RepopulateEncodings,
 		DeleteContext: RemoveResourceFromState,
 		Importer: &schema.ResourceImporter{
 			StateContext: ImportID,
 		},
+		CustomizeDiff: customdiff.All(
+			logKeepersChange,
+			planEncodings,
+		),
 
-		Schema: map[string]*schema.Schema{
-			"keepers": {
-				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
-					"resource. See [the main provider documentation](../index.html) for more information.",
-				Type:     schema.TypeMap,
-				Optional: true,
-				ForceNew: true,
+		Schema:        idSchemaV1(),
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceIdV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: generationStateUpgrade,
 			},
+		},
+	}
+}
 
-			"byte_length": {
-				Description: "The number of random bytes to produce. The minimum value is 1, which produces " +
-					"eight bits of randomness.",
-				Type:     schema.TypeInt,
-				Required: true,
-				ForceNew: true,
-			},
+func resourceIdV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: idSchemaV0(),
+	}
+}
 
-			"prefix": {
-				Description: "Arbitrary string to prefix the output value with. This string is supplied as-is, " +
-					"meaning it is not guaranteed to be URL-safe or base64 encoded.",
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-			},
+// idSchemaV1 uses idSchemaV0 to obtain the V0 version of the Schema key-value entries but requires that the
+// generation entry be configured.
+func idSchemaV1() map[string]*schema.Schema {
+	idSchema := idSchemaV0()
+	idSchema["generation"] = generationSchema(false)
 
-			"b64_url": {
-				Description: "The generated id presented in base64, using the URL-friendly character set: " +
-					"case-sensitive letters, digits and the characters `_` and `-`.",
-				Type:     schema.TypeString,
-				Computed: true,
-			},
+	return idSchema
+}
 
-			"b64_std": {
-				Description: "The generated id presented in base64 without additional transformations.",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
+// idSchemaV0 returns the Schema key-value entries of random_id before the generation entry was added.
+func idSchemaV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"keepers": {
+			Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+				"resource. See [the main provider documentation](../index.html) for more information.",
+			Type:     schema.TypeMap,
+			Optional: true,
+			ForceNew: true,
+		},
 
-			"hex": {
-				Description: "The generated id presented in padded hexadecimal digits. This result will " +
-					"always be twice as long as the requested byte length.",
-				Type:     schema.TypeString,
-				Computed: true,
-			},
+		"byte_length": {
+			Description: "The number of random bytes to produce. The minimum value is 1, which produces " +
+				"eight bits of randomness. Exactly one of `byte_length` or `length` must be set.",
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ForceNew:     true,
+			ExactlyOneOf: []string{"byte_length", "length"},
+		},
 
-			"dec": {
-				Description: "The generated id presented in non-padded decimal digits.",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
+		"length": {
+			Description: "The number of characters of `hex`, not counting `prefix`, e.g., `12` for a " +
+				"12-character hexadecimal id. When set, `byte_length` is half of `length` rounded up and, " +
+				"when `length` is odd, the last hexadecimal digit is omitted from `hex`. The other outputs " +
+				"encode all of the generated bytes. Exactly one of `byte_length` or `length` must be set.",
+			Type:             schema.TypeInt,
+			Optional:         true,
+			ForceNew:         true,
+			ExactlyOneOf:     []string{"byte_length", "length"},
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+		},
 
-			"id": {
-				Description: "The generated id presented in base64 without additional transformations or prefix.",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
+		"detect_drift": detectDriftSchema(),
+
+		"prefix": {
+			Description: "Arbitrary string to prefix the output value with. This string is supplied as-is, " +
+				"meaning it is not guaranteed to be URL-safe or base64 encoded.",
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+		},
+
+		"ipv6_ula": {
+			Description: "Use the generated id as the 40-bit Global ID of an IPv6 Unique Local Address prefix, as " +
+				"recommended by RFC 4193, and expose the prefix in `ipv6_ula_prefix`. When `true`, " +
+				"`byte_length` must be 5. Default value is `false`.",
+			Type:     schema.TypeBool,
+			Optional: true,
+			ForceNew: true,
+		},
+
+		"prefix_separator": {
+			Description: "A string inserted between `prefix` and the encoded id in each output, e.g., `-` to " +
+				"produce `cloud-AB12` from a `prefix` of `cloud`. Ignored when `prefix` is not set. Changing " +
+				"this argument updates the outputs in place rather than generating a new id. An imported id " +
+				"has no separator until this argument is set.",
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+
+		"hex_uppercase": {
+			Description: "Present `hex` using uppercase hexadecimal digits. The other outputs are not " +
+				"affected, and changing this argument updates `hex` in place rather than generating a new " +
+				"id. An imported id uses lowercase digits until this argument is set. Default value is `false`.",
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+
+		"alphabet": {
+			Description: "The characters with which to encode the generated id in `custom`, as a base-N number " +
+				"where N is the number of characters, e.g., `23456789ABCDEFGHJKLMNPQRSTUVWXYZ` for codes " +
+				"without easily confused characters. Must contain at least 2 characters, none of which may " +
+				"appear more than once. Changing this argument updates `custom` in place rather than " +
+				"generating a new id.",
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateDiagFunc: validateAlphabet,
+		},
+
+		"hmac_key": {
+			Description: "A key, e.g., of a tenant, with which to compute `hmac` from the generated bytes, so " +
+				"that the same bytes produce a different `hmac` for each key. Changing this argument updates " +
+				"`hmac` in place rather than generating a new id.",
+			Type:      schema.TypeString,
+			Optional:  true,
+			Sensitive: true,
+		},
+
+		"ipv6_ula_prefix": {
+			Description: "The `fd00::/8` Unique Local Address `/48` prefix formed from the generated id, e.g. " +
+				"`fd12:3456:789a::/48`, when `ipv6_ula` is `true`.",
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+
+		"b64_url": {
+			Description: "The generated id presented in base64, using the URL-friendly character set: " +
+				"case-sensitive letters, digits and the characters `_` and `-`. The result is not padded " +
+				"with `=`.",
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+
+		"b64_std": {
+			Description: "The generated id presented in base64 without additional transformations.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+
+		"b64_std_nopad": {
+			Description: "The generated id presented in base64 like `b64_std`, but without `=` padding.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+
+		"hex": {
+			Description: "The generated id presented in padded hexadecimal digits. This result will " +
+				"always be twice as long as the requested byte length, or as long as `length` when set.",
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+
+		"b32_checksummed": {
+			Description: "The generated id presented in unpadded RFC 4648 base32, followed by a single " +
+				"Luhn mod 32 check character. The id can be imported in this form by prefixing it " +
+				"with `b32_checksummed:`, in which case the check character is verified.",
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+
+		"b32_std": {
+			Description: "The generated id presented in standard RFC 4648 base32, using the uppercase letters " +
+				"and the digits `2` to `7`, padded with `=`, e.g., for TOTP secrets. The id can be imported in " +
+				"this form, with or without padding, by prefixing it with `b32_std:`.",
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+
+		"b32_nopad": {
+			Description: "The generated id presented in base32 like `b32_std`, but without `=` padding.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+
+		"b32_crockford": {
+			Description: "The generated id presented in unpadded Crockford base32, using the digits and " +
+				"the uppercase letters other than `I`, `L`, `O` and `U`, which avoids characters that are " +
+				"easily confused when read or typed by a person.",
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+
+		"b58": {
+			Description: "The generated id presented in unpadded base58, using the Bitcoin alphabet of digits " +
+				"and letters other than `0`, `O`, `I` and `l`, which avoids characters that are easily confused " +
+				"and is URL-safe. Each leading zero byte is presented as a `1`, so the length may vary for a " +
+				"given `byte_length`.",
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+
+		"custom": {
+			Description: "The generated id encoded using the characters of `alphabet`, when set. The result " +
+				"is padded with the first character of `alphabet` so that it always has the same length for a " +
+				"given `byte_length` and `alphabet`.",
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+
+		"dec": {
+			Description: "The generated id presented in non-padded decimal digits.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+
+		"hmac": {
+			Description: "The HMAC-SHA256 of the generated bytes keyed with `hmac_key`, when set, presented in " +
+				"lowercase hexadecimal digits without `prefix`.",
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+
+		"byte_length_effective": {
+			Description: "The number of random bytes encoded in the generated id.",
+			Type:        schema.TypeInt,
+			Computed:    true,
+		},
+
+		"id": {
+			Description: "The generated id presented in base64 without additional transformations or prefix.",
+			Type:        schema.TypeString,
+			Computed:    true,
 		},
 	}
 }
@@ -99,9 +276,24 @@ exist concurrently.
 func CreateID(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 	byteLength := d.Get("byte_length").(int)
+	if v, ok := d.GetOk("length"); ok {
+		byteLength = (v.(int) + 1) / 2
+		if err := d.Set("byte_length", byteLength); err != nil {
+			return append(diags, diag.Errorf("error setting byte_length: %s", err)...)
+		}
+	}
 	bytes := make([]byte, byteLength)
 
-	n, err := rand.Reader.Read(bytes)
+	if d.Get("ipv6_ula").(bool) && byteLength != ipv6ULAGlobalIDLength {
+		return append(diags, diag.Errorf("byte_length (%d) must be %d when ipv6_ula is true", byteLength, ipv6ULAGlobalIDLength)...)
+	}
+
+	var reader io.Reader = rand.Reader
+	if seed := resourceSeed(meta, "random_id", resourceId().Schema, d); seed != "" {
+		reader = NewRand(seed)
+	}
+
+	n, err := reader.Read(bytes)
 	if n != byteLength {
 		return append(diags, diag.Errorf("generated insufficient random bytes: %s", err)...)
 	}
@@ -112,6 +304,11 @@ func CreateID(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 	b64Str := base64.RawURLEncoding.EncodeToString(bytes)
 	d.SetId(b64Str)
 
+	if err := setGeneration(d); err != nil {
+		d.SetId("")
+		return append(diags, diag.Errorf("error setting generation: %s", err)...)
+	}
+
 	repopEncsDiags := RepopulateEncodings(ctx, d, meta)
 	if repopEncsDiags != nil {
 		return append(diags, repopEncsDiags...)
@@ -120,9 +317,24 @@ func CreateID(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 	return diags
 }
 
+// ReadID repopulates the encodings of the id held in state. When detect_drift is true, the id must first decode to
+// byte_length bytes, otherwise the resource is removed from state so that it is recreated.
+func ReadID(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Get("detect_drift").(bool) {
+		bytes, err := base64.RawURLEncoding.DecodeString(d.Id())
+		if err != nil {
+			return removeOnDrift(d, fmt.Errorf("error decoding ID: %w", err))
+		}
+		if byteLength := d.Get("byte_length").(int); len(bytes) != byteLength {
+			return removeOnDrift(d, fmt.Errorf("ID decodes to %d bytes, expected byte_length (%d)", len(bytes), byteLength))
+		}
+	}
+
+	return RepopulateEncodings(ctx, d, meta)
+}
+
 func RepopulateEncodings(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
-	prefix := d.Get("prefix").(string)
 	base64Str := d.Id()
 
 	bytes, err := base64.RawURLEncoding.DecodeString(base64Str)
@@ -130,29 +342,189 @@ func RepopulateEncodings(_ context.Context, d *schema.ResourceData, _ interface{
 		return append(diags, diag.Errorf("error decoding ID: %s", err)...)
 	}
 
-	b64StdStr := base64.StdEncoding.EncodeToString(bytes)
-	hexStr := hex.EncodeToString(bytes)
+	encodings, err := encodeID(d, bytes)
+	if err != nil {
+		return append(diags, diag.Errorf("error encoding ID: %s", err)...)
+	}
+
+	for _, k := range idEncodingKeys {
+		if err := d.Set(k, encodings[k]); err != nil {
+			return append(diags, diag.Errorf("error setting %s: %s", k, err)...)
+		}
+	}
+
+	if err := d.Set("byte_length_effective", len(bytes)); err != nil {
+		return append(diags, diag.Errorf("error setting byte_length_effective: %s", err)...)
+	}
+
+	if d.Get("ipv6_ula").(bool) {
+		ulaPrefix, err := ipv6ULAPrefix(bytes)
+		if err != nil {
+			return append(diags, diag.Errorf("error setting ipv6_ula_prefix: %s", err)...)
+		}
+		if err := d.Set("ipv6_ula_prefix", ulaPrefix); err != nil {
+			return append(diags, diag.Errorf("error setting ipv6_ula_prefix: %s", err)...)
+		}
+	}
+
+	return nil
+}
+
+// idEncodingKeys are the attributes holding the encodings of the generated id returned by encodeID.
+var idEncodingKeys = []string{"b64_url", "b64_std", "b64_std_nopad", "hex", "b32_checksummed", "b32_std", "b32_nopad",
+	"b32_crockford", "b58", "custom", "dec", "hmac"}
+
+// encodeID returns the value of each of idEncodingKeys for bytes, given the prefix, prefix_separator, hex_uppercase,
+// alphabet and hmac_key configured in d. custom is empty when alphabet is not set, and hmac when hmac_key is not set.
+func encodeID(d interface{ Get(string) interface{} }, bytes []byte) (map[string]string, error) {
+	prefix := d.Get("prefix").(string)
+	if prefix != "" {
+		prefix += d.Get("prefix_separator").(string)
+	}
+
+	b32Str, err := encodeB32Checksummed(bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding b32_checksummed: %w", err)
+	}
 
 	bigInt := big.Int{}
 	bigInt.SetBytes(bytes)
-	decStr := bigInt.String()
 
-	if err := d.Set("b64_url", prefix+base64Str); err != nil {
-		return append(diags, diag.Errorf("error setting b64_url: %s", err)...)
+	encodings := map[string]string{
+		"b64_url":         prefix + base64.RawURLEncoding.EncodeToString(bytes),
+		"b64_std":         prefix + base64.StdEncoding.EncodeToString(bytes),
+		"b64_std_nopad":   prefix + base64.RawStdEncoding.EncodeToString(bytes),
+		"hex":             prefix + formatHex(bytes, d.Get("hex_uppercase").(bool), d.Get("length").(int)),
+		"b32_checksummed": prefix + b32Str,
+		"b32_std":         prefix + base32.StdEncoding.EncodeToString(bytes),
+		"b32_nopad":       prefix + b32Encoding.EncodeToString(bytes),
+		"b32_crockford":   prefix + b32CrockfordEncoding.EncodeToString(bytes),
+		"b58":             prefix + encodeB58(bytes),
+		"dec":             prefix + bigInt.String(),
+	}
+
+	if alphabet := d.Get("alphabet").(string); alphabet != "" {
+		encodings["custom"] = prefix + encodeAlphabet(bytes, alphabet)
 	}
-	if err := d.Set("b64_std", prefix+b64StdStr); err != nil {
-		return append(diags, diag.Errorf("error setting b64_std: %s", err)...)
+
+	if key := d.Get("hmac_key").(string); key != "" {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write(bytes)
+		encodings["hmac"] = hex.EncodeToString(mac.Sum(nil))
 	}
-	if err := d.Set("hex", prefix+hexStr); err != nil {
-		return append(diags, diag.Errorf("error setting hex: %s", err)...)
+
+	return encodings, nil
+}
+
+// planEncodings plans the new encodings of an existing id when prefix_separator, hex_uppercase, alphabet or hmac_key
+// are changed, which are updated in place using the bytes of the id.
+func planEncodings(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChanges("prefix_separator", "hex_uppercase", "alphabet", "hmac_key") {
+		return nil
 	}
-	if err := d.Set("dec", prefix+decStr); err != nil {
-		return append(diags, diag.Errorf("error setting dec: %s", err)...)
+
+	bytes, err := base64.RawURLEncoding.DecodeString(d.Id())
+	if err != nil {
+		return fmt.Errorf("error decoding ID: %w", err)
+	}
+
+	encodings, err := encodeID(d, bytes)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range idEncodingKeys {
+		if err := d.SetNew(k, encodings[k]); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// formatHex encodes bytes as hexadecimal digits, which are uppercase when uppercase is true, truncated to length
+// digits when length is positive.
+func formatHex(bytes []byte, uppercase bool, length int) string {
+	s := hex.EncodeToString(bytes)
+	if length > 0 && length < len(s) {
+		s = s[:length]
+	}
+
+	if uppercase {
+		return strings.ToUpper(s)
+	}
+
+	return s
+}
+
+// encodeAlphabet encodes bytes as a big-endian base-N number, where N is the number of characters in alphabet, padded
+// with the first character of alphabet to the number of digits required to encode any value of the same length.
+func encodeAlphabet(bytes []byte, alphabet string) string {
+	digits := []rune(alphabet)
+	base := big.NewInt(int64(len(digits)))
+
+	// Determine the number of digits required to encode the largest value of len(bytes) bytes.
+	length := 0
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(8*len(bytes)))
+	for capacity := big.NewInt(1); capacity.Cmp(limit) < 0; capacity.Mul(capacity, base) {
+		length++
+	}
+
+	result := make([]rune, length)
+	value := new(big.Int).SetBytes(bytes)
+	remainder := new(big.Int)
+	for i := length - 1; i >= 0; i-- {
+		value.DivMod(value, base, remainder)
+		result[i] = digits[remainder.Int64()]
+	}
+
+	return string(result)
+}
+
+// validateAlphabet returns an error diagnostic if the alphabet i contains fewer than 2 characters or any character
+// more than once.
+func validateAlphabet(i interface{}, path cty.Path) diag.Diagnostics {
+	v, ok := i.(string)
+	if !ok {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Expected a string",
+			AttributePath: path,
+		}}
+	}
+
+	seen := map[rune]int{}
+	var duplicates []string
+	for _, r := range v {
+		seen[r]++
+		if seen[r] == 2 {
+			duplicates = append(duplicates, string(r))
+		}
+	}
+
+	if len(duplicates) > 0 {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Duplicate characters",
+			Detail:        fmt.Sprintf("The characters %q appear more than once in alphabet.", strings.Join(duplicates, "")),
+			AttributePath: path,
+		}}
+	}
+
+	if len(seen) < 2 {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Alphabet too short",
+			Detail:        fmt.Sprintf("alphabet must contain at least 2 characters, got %d.", len(seen)),
+			AttributePath: path,
+		}}
+	}
+
+	return nil
+}
+
+// ImportID imports a random_id from its b64_url value, optionally preceded by a prefix and a comma. The ID is split at
+// the last comma, which cannot appear in the b64_url value, so the prefix may itself contain commas.
 func ImportID(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
 	id := d.Id()
 
@@ -165,16 +537,145 @@ func ImportID(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*sche
 		id = id[sep+1:]
 	}
 
-	bytes, err := base64.RawURLEncoding.DecodeString(id)
-	if err != nil {
-		return nil, fmt.Errorf("error decoding ID: %w", err)
+	var bytes []byte
+	var err error
+	if strings.HasPrefix(id, b32ChecksummedImportPrefix) {
+		bytes, err = decodeB32Checksummed(strings.TrimPrefix(id, b32ChecksummedImportPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("error decoding b32_checksummed ID: %w", err)
+		}
+
+		id = base64.RawURLEncoding.EncodeToString(bytes)
+	} else if strings.HasPrefix(id, b32StdImportPrefix) {
+		bytes, err = decodeB32Std(strings.TrimPrefix(id, b32StdImportPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("error decoding b32_std ID: %w", err)
+		}
+
+		id = base64.RawURLEncoding.EncodeToString(bytes)
+	} else {
+		bytes, err = decodeB64Any(id)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding ID: %w", err)
+		}
+
+		id = base64.RawURLEncoding.EncodeToString(bytes)
 	}
 
 	if err := d.Set("byte_length", len(bytes)); err != nil {
 		return nil, fmt.Errorf("error setting byte_length: %w", err)
 	}
 
+	if err := d.Set("generation", 1); err != nil {
+		return nil, fmt.Errorf("error setting generation: %w", err)
+	}
+
 	d.SetId(id)
 
 	return []*schema.ResourceData{d}, nil
 }
+
+// b32ChecksummedImportPrefix marks an import ID given in the b32_checksummed
+// encoding rather than the default b64_url encoding.
+const b32ChecksummedImportPrefix = "b32_checksummed:"
+
+// b32StdImportPrefix marks an import ID given in the b32_std or b32_nopad
+// encoding rather than the default b64_url encoding.
+const b32StdImportPrefix = "b32_std:"
+
+const b32Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+
+var b32Encoding = base32.NewEncoding(b32Alphabet).WithPadding(base32.NoPadding)
+
+// b32CrockfordEncoding is Douglas Crockford's base32 encoding, which excludes
+// the letters I, L, O and U.
+var b32CrockfordEncoding = base32.NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ").WithPadding(base32.NoPadding)
+
+// decodeB32Std decodes value given in standard RFC 4648 base32, with or without
+// = padding.
+func decodeB32Std(value string) ([]byte, error) {
+	return b32Encoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+// decodeB64Any decodes value given in either the URL-friendly or the standard
+// base64 character set, with or without = padding.
+func decodeB64Any(value string) ([]byte, error) {
+	value = strings.TrimRight(value, "=")
+	value = strings.NewReplacer("+", "-", "/", "_").Replace(value)
+
+	return base64.RawURLEncoding.DecodeString(value)
+}
+
+// b58Alphabet is the Bitcoin base58 alphabet, which excludes the digit 0
+// and the letters O, I and l.
+const b58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// encodeB58 encodes bytes as an unpadded big-endian base58 number, preceded
+// by a 1 for each leading zero byte, as in Bitcoin addresses.
+func encodeB58(bytes []byte) string {
+	var result []byte
+
+	value := new(big.Int).SetBytes(bytes)
+	base := big.NewInt(int64(len(b58Alphabet)))
+	remainder := new(big.Int)
+	for value.Sign() > 0 {
+		value.DivMod(value, base, remainder)
+		result = append(result, b58Alphabet[remainder.Int64()])
+	}
+
+	for _, b := range bytes {
+		if b != 0 {
+			break
+		}
+		result = append(result, b58Alphabet[0])
+	}
+
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return string(result)
+}
+
+// encodeB32Checksummed encodes bytes as unpadded base32 followed by a Luhn
+// mod 32 check character.
+func encodeB32Checksummed(bytes []byte) (string, error) {
+	body := b32Encoding.EncodeToString(bytes)
+
+	check, err := luhnCheckCharacter(body, b32Alphabet)
+	if err != nil {
+		return "", err
+	}
+
+	return body + string(check), nil
+}
+
+// decodeB32Checksummed verifies and strips the check character of a value
+// produced by encodeB32Checksummed and returns the decoded bytes.
+func decodeB32Checksummed(value string) ([]byte, error) {
+	value = strings.ToUpper(value)
+
+	if !luhnValid(value, b32Alphabet) {
+		return nil, fmt.Errorf("check character mismatch in %q", value)
+	}
+
+	return b32Encoding.DecodeString(value[:len(value)-1])
+}
+
+// ipv6ULAGlobalIDLength is the length in bytes of the Global ID of an RFC 4193
+// Unique Local Address.
+const ipv6ULAGlobalIDLength = 5
+
+// ipv6ULAPrefix returns the locally assigned fd00::/8 /48 prefix with the given
+// Global ID, in the canonical text form of RFC 5952.
+func ipv6ULAPrefix(globalID []byte) (string, error) {
+	if len(globalID) != ipv6ULAGlobalIDLength {
+		return "", fmt.Errorf("global ID must be %d bytes, got %d", ipv6ULAGlobalIDLength, len(globalID))
+	}
+
+	ip := make(net.IP, net.IPv6len)
+	ip[0] = 0xfd
+	copy(ip[1:], globalID)
+
+	return (&net.IPNet{IP: ip, Mask: net.CIDRMask(48, 128)}).String(), nil
+}
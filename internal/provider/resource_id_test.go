@@ -1,17 +1,28 @@
 package provider
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"net"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 type idLens struct {
-	b64UrlLen int
-	b64StdLen int
-	hexLen    int
+	b64UrlLen       int
+	b64StdLen       int
+	hexLen          int
+	b32CrockfordLen int
+	b58MaxLen       int
 }
 
 func TestAccResourceID(t *testing.T) {
@@ -23,9 +34,11 @@ func TestAccResourceID(t *testing.T) {
 				Config: testAccResourceIDConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccResourceIDCheck("random_id.foo", &idLens{
-						b64UrlLen: 6,
-						b64StdLen: 8,
-						hexLen:    8,
+						b64UrlLen:       6,
+						b64StdLen:       8,
+						hexLen:          8,
+						b32CrockfordLen: 7,
+						b58MaxLen:       6,
 					}),
 				),
 			},
@@ -38,6 +51,30 @@ func TestAccResourceID(t *testing.T) {
 	})
 }
 
+func TestAccResourceIDLength(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceIDConfigLength,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_id.length", "hex", regexp.MustCompile(`^[0-9a-f]{11}$`)),
+					resource.TestCheckResourceAttr("random_id.length", "byte_length", "6"),
+					resource.TestCheckResourceAttr("random_id.length", "byte_length_effective", "6"),
+				),
+			},
+			{
+				Config: `resource "random_id" "invalid" {
+							byte_length = 6
+							length      = 12
+						}`,
+				ExpectError: regexp.MustCompile(`"byte_length": only one of\s+` + "`byte_length,length`" + ` can be specified`),
+			},
+		},
+	})
+}
+
 func TestAccResourceID_importWithPrefix(t *testing.T) {
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -47,9 +84,11 @@ func TestAccResourceID_importWithPrefix(t *testing.T) {
 				Config: testAccResourceIDConfigWithPrefix,
 				Check: resource.ComposeTestCheckFunc(
 					testAccResourceIDCheck("random_id.bar", &idLens{
-						b64UrlLen: 12,
-						b64StdLen: 14,
-						hexLen:    14,
+						b64UrlLen:       12,
+						b64StdLen:       14,
+						hexLen:          14,
+						b32CrockfordLen: 13,
+						b58MaxLen:       12,
 					}),
 				),
 			},
@@ -63,6 +102,628 @@ func TestAccResourceID_importWithPrefix(t *testing.T) {
 	})
 }
 
+func TestAccResourceID_importB32Checksummed(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceIDConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_id.foo", "b32_checksummed", regexp.MustCompile(`^[A-Z2-7]{8}$`)),
+				),
+			},
+			{
+				ResourceName:      "random_id.foo",
+				ImportState:       true,
+				ImportStateIdFunc: testAccResourceIDB32ChecksummedImportID("random_id.foo", false),
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "random_id.foo",
+				ImportState:       true,
+				ImportStateIdFunc: testAccResourceIDB32ChecksummedImportID("random_id.foo", true),
+				ExpectError:       regexp.MustCompile(`check character mismatch`),
+			},
+		},
+	})
+}
+
+func TestAccResourceID_ipv6ULA(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceIDConfigIPv6ULA,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith("random_id.ula", "ipv6_ula_prefix", func(value string) error {
+						if !strings.HasPrefix(value, "fd") {
+							return fmt.Errorf("prefix %q does not start with fd", value)
+						}
+
+						ip, network, err := net.ParseCIDR(value)
+						if err != nil {
+							return err
+						}
+						if ones, bits := network.Mask.Size(); ones != 48 || bits != 128 {
+							return fmt.Errorf("prefix %q has length /%d of %d bits; want /48 of 128 bits", value, ones, bits)
+						}
+						if !ip.Equal(network.IP) {
+							return fmt.Errorf("prefix %q has host bits set", value)
+						}
+
+						return nil
+					}),
+				),
+			},
+			{
+				Config:      testAccResourceIDConfigIPv6ULAInvalidLength,
+				ExpectError: regexp.MustCompile(`byte_length \(4\) must be 5 when ipv6_ula is true`),
+			},
+		},
+	})
+}
+
+func TestAccResourceID_importWithCommaInPrefix(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceIDConfigWithCommaInPrefix,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceIDCheck("random_id.comma", &idLens{
+						b64UrlLen:       16,
+						b64StdLen:       18,
+						hexLen:          18,
+						b32CrockfordLen: 17,
+					}),
+				),
+			},
+			{
+				ResourceName:        "random_id.comma",
+				ImportState:         true,
+				ImportStateIdPrefix: "cloud,web-,",
+				ImportStateVerify:   true,
+			},
+		},
+	})
+}
+
+func TestImportID(t *testing.T) {
+	cases := []struct {
+		id             string
+		expectedPrefix string
+		expectedID     string
+	}{
+		{"3q2-7w", "", "3q2-7w"},
+		{"cloud-,3q2-7w", "cloud-", "3q2-7w"},
+		{"cloud,web-,3q2-7w", "cloud,web-", "3q2-7w"},
+		{",,3q2-7w", ",", "3q2-7w"},
+		{"3q2-7w==", "", "3q2-7w"},
+		{"cloud-,3q2+7w==", "cloud-", "3q2-7w"},
+		{"3q2+7w", "", "3q2-7w"},
+		{"b32_std:32W353Y=", "", "3q2-7w"},
+		{"cloud-,b32_std:32W353Y", "cloud-", "3q2-7w"},
+	}
+
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, resourceId().Schema, nil)
+		d.SetId(c.id)
+
+		if _, err := ImportID(context.Background(), d, nil); err != nil {
+			t.Fatalf("import ID %q: %s", c.id, err)
+		}
+
+		if got := d.Get("prefix"); got != c.expectedPrefix {
+			t.Errorf("import ID %q: got prefix %q; want %q", c.id, got, c.expectedPrefix)
+		}
+		if got := d.Id(); got != c.expectedID {
+			t.Errorf("import ID %q: got id %q; want %q", c.id, got, c.expectedID)
+		}
+		if got := d.Get("byte_length"); got != 4 {
+			t.Errorf("import ID %q: got byte_length %v; want 4", c.id, got)
+		}
+	}
+}
+
+func TestAccResourceIDPrefixSeparator(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceIDConfigPrefixSeparator,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_id.sep", "hex", regexp.MustCompile(`^cloud-[0-9a-f]{8}$`)),
+					resource.TestMatchResourceAttr("random_id.sep", "b64_url", regexp.MustCompile(`^cloud-[\w-]{6}$`)),
+					resource.TestMatchResourceAttr("random_id.sep", "b64_std", regexp.MustCompile(`^cloud-[\w+/=]{8}$`)),
+					resource.TestCheckResourceAttr("random_id.sep", "byte_length_effective", "4"),
+				),
+			},
+			{
+				// The import ID gives the prefix without the separator, which is applied once prefix_separator
+				// is set in the configuration.
+				ResourceName:        "random_id.sep",
+				ImportState:         true,
+				ImportStateIdPrefix: "cloud,",
+				ImportStateVerify:   true,
+				ImportStateVerifyIgnore: []string{
+					"prefix_separator", "b64_url", "b64_std", "hex", "b32_checksummed", "b32_crockford", "b58", "dec", "b64_std_nopad",
+					"b32_std", "b32_nopad",
+				},
+			},
+		},
+	})
+}
+
+func TestReadIDDetectDrift(t *testing.T) {
+	cases := []struct {
+		name        string
+		id          string
+		detectDrift bool
+		removed     bool
+	}{
+		{name: "valid", id: "3q2-7w", detectDrift: true},
+		{name: "invalid base64", id: "3q2-7w!", detectDrift: true, removed: true},
+		{name: "wrong byte length", id: "3q2-7-8", detectDrift: true, removed: true},
+		{name: "not detected", id: "3q2-7-8"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := resourceId().TestResourceData()
+			d.SetId(c.id)
+			for k, v := range map[string]interface{}{"byte_length": 4, "detect_drift": c.detectDrift} {
+				if err := d.Set(k, v); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			diags := ReadID(context.Background(), d, nil)
+			if !c.removed {
+				if len(diags) != 0 {
+					t.Fatalf("expected no diagnostics, got: %v", diags)
+				}
+				if d.Id() != c.id {
+					t.Errorf("got id %q; want %q", d.Id(), c.id)
+				}
+				return
+			}
+
+			if len(diags) != 1 || diags[0].Severity != diag.Warning {
+				t.Errorf("expected a warning, got: %v", diags)
+			}
+			if d.Id() != "" {
+				t.Errorf("expected the resource to be removed from state, got id %q", d.Id())
+			}
+		})
+	}
+}
+
+func TestEncodeID(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   map[string]interface{}
+		expected map[string]string
+	}{
+		{
+			name:   "no prefix",
+			config: map[string]interface{}{"prefix_separator": "-"},
+			expected: map[string]string{
+				"b64_url": "3q2-7w", "b64_std": "3q2+7w==", "b64_std_nopad": "3q2+7w", "hex": "deadbeef", "dec": "3735928559",
+				"b32_std": "32W353Y=", "b32_nopad": "32W353Y", "custom": "", "hmac": "",
+			},
+		},
+		{
+			name:   "prefix without separator",
+			config: map[string]interface{}{"prefix": "cloud"},
+			expected: map[string]string{
+				"b64_url": "cloud3q2-7w", "b64_std": "cloud3q2+7w==", "hex": "clouddeadbeef", "dec": "cloud3735928559",
+			},
+		},
+		{
+			name:   "prefix with separator",
+			config: map[string]interface{}{"prefix": "cloud", "prefix_separator": "-", "hex_uppercase": true},
+			expected: map[string]string{
+				"b64_url": "cloud-3q2-7w", "b64_std": "cloud-3q2+7w==", "hex": "cloud-DEADBEEF", "dec": "cloud-3735928559",
+				"b32_crockford": "cloud-VTPVXVR", "b58": "cloud-6h8cQN",
+			},
+		},
+		{
+			name:   "length",
+			config: map[string]interface{}{"prefix": "cloud-", "length": 7, "hex_uppercase": true},
+			expected: map[string]string{
+				"hex": "cloud-DEADBEE", "b64_url": "cloud-3q2-7w",
+			},
+		},
+		{
+			name:   "alphabet",
+			config: map[string]interface{}{"prefix": "cloud", "prefix_separator": "_", "alphabet": "0123456789abcdef"},
+			expected: map[string]string{
+				"custom": "cloud_deadbeef",
+			},
+		},
+		{
+			name:   "hmac_key",
+			config: map[string]interface{}{"prefix": "cloud", "hmac_key": "tenant-a"},
+			expected: map[string]string{
+				"hex":  "clouddeadbeef",
+				"hmac": "a05d602b2288829b33260f2f09ce4d945fe9609d03cada0a5b9aa60a540a526a",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceId().Schema, c.config)
+
+			encodings, err := encodeID(d, []byte{0xde, 0xad, 0xbe, 0xef})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for k, want := range c.expected {
+				if got := encodings[k]; got != want {
+					t.Errorf("got %s %q; want %q", k, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAccResourceIDHexUppercase(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceIDConfigHexUppercase(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_id.upper", "hex", regexp.MustCompile(`^[0-9A-F]{8}$`)),
+					resource.TestMatchResourceAttr("random_id.upper", "b64_url", regexp.MustCompile(`^[\w-]{6}$`)),
+				),
+			},
+			{
+				ResourceName:            "random_id.upper",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"hex", "hex_uppercase"},
+			},
+			{
+				Config: testAccResourceIDConfigHexUppercase(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_id.upper", "hex", regexp.MustCompile(`^[0-9a-f]{8}$`)),
+					testAccResourceIDHexMatchesB64URL("random_id.upper"),
+				),
+			},
+		},
+	})
+}
+
+func TestPlanHexUppercase(t *testing.T) {
+	prior := map[string]cty.Value{
+		"id":            cty.StringVal("3q2-7w"),
+		"byte_length":   cty.NumberIntVal(4),
+		"prefix":        cty.StringVal("ab-"),
+		"hex":           cty.StringVal("ab-deadbeef"),
+		"hex_uppercase": cty.False,
+	}
+	config := map[string]cty.Value{
+		"byte_length":   cty.NumberIntVal(4),
+		"prefix":        cty.StringVal("ab-"),
+		"hex_uppercase": cty.True,
+	}
+
+	planned, resp := planResourceChange(t, "random_id", prior, config)
+
+	if len(resp.RequiresReplace) != 0 {
+		t.Errorf("expected no replacement, got: %v", resp.RequiresReplace)
+	}
+	if got := planned.GetAttr("hex"); !got.RawEquals(cty.StringVal("ab-DEADBEEF")) {
+		t.Errorf("expected hex: ab-DEADBEEF, got: %#v", got)
+	}
+}
+
+func TestPlanHMACKey(t *testing.T) {
+	prior := map[string]cty.Value{
+		"id":          cty.StringVal("3q2-7w"),
+		"byte_length": cty.NumberIntVal(4),
+		"hex":         cty.StringVal("deadbeef"),
+		"hmac_key":    cty.StringVal("tenant-a"),
+		"hmac":        cty.StringVal("a05d602b2288829b33260f2f09ce4d945fe9609d03cada0a5b9aa60a540a526a"),
+	}
+	config := map[string]cty.Value{
+		"byte_length": cty.NumberIntVal(4),
+		"hmac_key":    cty.StringVal("tenant-b"),
+	}
+
+	planned, resp := planResourceChange(t, "random_id", prior, config)
+
+	if len(resp.RequiresReplace) != 0 {
+		t.Errorf("expected no replacement, got: %v", resp.RequiresReplace)
+	}
+	expected := cty.StringVal("9566772ca90ddb6a2979613e01d2ccd203e0a16a7b6c7899fc079472e13c9650")
+	if got := planned.GetAttr("hmac"); !got.RawEquals(expected) {
+		t.Errorf("expected hmac: %#v, got: %#v", expected, got)
+	}
+	if got := planned.GetAttr("hex"); !got.RawEquals(cty.StringVal("deadbeef")) {
+		t.Errorf("expected hex: deadbeef, got: %#v", got)
+	}
+}
+
+func TestAccResourceIDHMAC(t *testing.T) {
+	var hexValue, hmacValue string
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceIDConfigHMAC("tenant-a"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_id.tenant", "hmac", regexp.MustCompile(`^[0-9a-f]{64}$`)),
+					resource.TestCheckResourceAttrWith("random_id.tenant", "hex", func(value string) error {
+						hexValue = value
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith("random_id.tenant", "hmac", func(value string) error {
+						hmacValue = value
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccResourceIDConfigHMAC("tenant-b"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith("random_id.tenant", "hex", func(value string) error {
+						if value != hexValue {
+							return fmt.Errorf("expected hex to be unchanged as %s, got: %s", hexValue, value)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith("random_id.tenant", "hmac", func(value string) error {
+						if value == hmacValue {
+							return fmt.Errorf("expected a new hmac after hmac_key changed, got: %s", value)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceIDAlphabet(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceIDConfigAlphabet,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_id.code", "custom", regexp.MustCompile(`^code-[23456789A-HJ-NP-Z]{8}$`)),
+					resource.TestMatchResourceAttr("random_id.code", "hex", regexp.MustCompile(`^code-[0-9a-f]{10}$`)),
+				),
+			},
+			{
+				ResourceName:            "random_id.code",
+				ImportState:             true,
+				ImportStateIdPrefix:     "code-,",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"alphabet", "custom"},
+			},
+		},
+	})
+}
+
+func TestAccResourceIDAlphabetErrors(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "random_id" "code" {
+							byte_length = 5
+							alphabet    = "ABCA"
+						}`,
+				ExpectError: regexp.MustCompile(`The characters "A" appear more than once in alphabet`),
+			},
+			{
+				Config: `resource "random_id" "code" {
+							byte_length = 5
+							alphabet    = "A"
+						}`,
+				ExpectError: regexp.MustCompile(`alphabet must contain at least 2 characters, got 1`),
+			},
+		},
+	})
+}
+
+func TestEncodeAlphabet(t *testing.T) {
+	cases := []struct {
+		bytes    []byte
+		alphabet string
+		expected string
+	}{
+		{[]byte{0xff}, "01", "11111111"},
+		{[]byte{0x05}, "01", "00000101"},
+		{[]byte{0x00, 0x00}, "0123456789", "00000"},
+		{[]byte{0xff, 0xff}, "0123456789", "65535"},
+		{[]byte{0xde, 0xad, 0xbe, 0xef}, "0123456789abcdef", "deadbeef"},
+		{[]byte{0x01, 0x00}, "ab", "aaaaaaabaaaaaaaa"},
+		{[]byte{0x0a}, "αβγ", "αααβαβ"},
+	}
+
+	for _, c := range cases {
+		if got := encodeAlphabet(c.bytes, c.alphabet); got != c.expected {
+			t.Errorf("bytes %x in %q: got %q; want %q", c.bytes, c.alphabet, got, c.expected)
+		}
+	}
+}
+
+func TestValidateAlphabet(t *testing.T) {
+	cases := []struct {
+		alphabet string
+		expected string
+	}{
+		{"ab", ""},
+		{"23456789ABCDEFGHJKLMNPQRSTUVWXYZ", ""},
+		{"abcab", `The characters "ab" appear more than once in alphabet.`},
+		{"a", "alphabet must contain at least 2 characters, got 1."},
+		{"", "alphabet must contain at least 2 characters, got 0."},
+	}
+
+	for _, c := range cases {
+		diags := validateAlphabet(c.alphabet, cty.GetAttrPath("alphabet"))
+		if c.expected == "" {
+			if len(diags) != 0 {
+				t.Errorf("alphabet %q: unexpected diagnostics: %v", c.alphabet, diags)
+			}
+			continue
+		}
+
+		if len(diags) != 1 || diags[0].Detail != c.expected {
+			t.Errorf("alphabet %q: got %v; want a single error %q", c.alphabet, diags, c.expected)
+		}
+	}
+}
+
+func TestIPv6ULAPrefix(t *testing.T) {
+	cases := []struct {
+		globalID []byte
+		expected string
+	}{
+		{[]byte{0x12, 0x34, 0x56, 0x78, 0x9a}, "fd12:3456:789a::/48"},
+		{[]byte{0x00, 0x00, 0x01, 0x00, 0x00}, "fd00:1::/48"},
+		{[]byte{0x00, 0x00, 0x00, 0x00, 0x00}, "fd00::/48"},
+	}
+
+	for _, c := range cases {
+		got, err := ipv6ULAPrefix(c.globalID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.expected {
+			t.Errorf("global ID %x: got %q; want %q", c.globalID, got, c.expected)
+		}
+	}
+
+	if _, err := ipv6ULAPrefix([]byte{0x12, 0x34}); err == nil {
+		t.Error("expected error for a global ID that is not 5 bytes")
+	}
+}
+
+func TestEncodeB58(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"00", "1"},
+		{"0000287fb4cd", "11233QC4"},
+		{"48656c6c6f20576f726c6421", "2NEpo7TZRRrLZSi2U"},
+		{"00eb15231dfceb60925886b67d065299925915aeb172c06647", "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+	}
+
+	for _, c := range cases {
+		bytes, err := hex.DecodeString(c.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := encodeB58(bytes); got != c.expected {
+			t.Errorf("encodeB58(%s): got %q; want %q", c.input, got, c.expected)
+		}
+	}
+}
+
+func TestB32CrockfordEncoding(t *testing.T) {
+	cases := []struct {
+		bytes    []byte
+		expected string
+	}{
+		{[]byte{0x00, 0x44, 0x32, 0x14, 0xc7}, "01234567"},
+		{[]byte{0x42, 0x54, 0xb6, 0x35, 0xcf}, "89ABCDEF"},
+		{[]byte{0x84, 0x65, 0x3a, 0x56, 0xd7}, "GHJKMNPQ"},
+		{[]byte{0xc6, 0x75, 0xbe, 0x77, 0xdf}, "RSTVWXYZ"},
+		{[]byte{0xde, 0xad, 0xbe, 0xef}, "VTPVXVR"},
+	}
+
+	for _, c := range cases {
+		if got := b32CrockfordEncoding.EncodeToString(c.bytes); got != c.expected {
+			t.Errorf("bytes %x: got %q; want %q", c.bytes, got, c.expected)
+		}
+	}
+}
+
+func TestDecodeB32Checksummed(t *testing.T) {
+	bytes := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	encoded, err := encodeB32Checksummed(bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := decodeB32Checksummed(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(decoded) != string(bytes) {
+		t.Errorf("decoded %x; want %x", decoded, bytes)
+	}
+
+	for i := range encoded {
+		corrupted := []byte(encoded)
+		corrupted[i] = b32Alphabet[(strings.IndexByte(b32Alphabet, corrupted[i])+1)%len(b32Alphabet)]
+
+		if _, err := decodeB32Checksummed(string(corrupted)); err == nil {
+			t.Errorf("expected error decoding %q corrupted at position %d", corrupted, i)
+		}
+	}
+}
+
+// testAccResourceIDB32ChecksummedImportID returns the b32_checksummed value of
+// the named resource as an import ID, optionally with one character corrupted.
+func testAccResourceIDB32ChecksummedImportID(id string, corrupt bool) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", id)
+		}
+
+		value := []byte(rs.Primary.Attributes["b32_checksummed"])
+		if corrupt {
+			value[0] = b32Alphabet[(strings.IndexByte(b32Alphabet, value[0])+1)%len(b32Alphabet)]
+		}
+
+		return b32ChecksummedImportPrefix + string(value), nil
+	}
+}
+
+// testAccResourceIDHexMatchesB64URL verifies that the hex value of the named
+// resource encodes the same bytes as its b64_url value.
+func testAccResourceIDHexMatchesB64URL(id string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("Not found: %s", id)
+		}
+
+		bytes, err := base64.RawURLEncoding.DecodeString(rs.Primary.Attributes["b64_url"])
+		if err != nil {
+			return err
+		}
+
+		if got, want := rs.Primary.Attributes["hex"], hex.EncodeToString(bytes); got != want {
+			return fmt.Errorf("hex is %q; want %q", got, want)
+		}
+
+		return nil
+	}
+}
+
 func testAccResourceIDCheck(id string, want *idLens) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[id]
@@ -76,6 +737,8 @@ func testAccResourceIDCheck(id string, want *idLens) resource.TestCheckFunc {
 		b64UrlStr := rs.Primary.Attributes["b64_url"]
 		b64StdStr := rs.Primary.Attributes["b64_std"]
 		hexStr := rs.Primary.Attributes["hex"]
+		b32CrockfordStr := rs.Primary.Attributes["b32_crockford"]
+		b58Str := rs.Primary.Attributes["b58"]
 		decStr := rs.Primary.Attributes["dec"]
 
 		if got, want := len(b64UrlStr), want.b64UrlLen; got != want {
@@ -87,6 +750,12 @@ func testAccResourceIDCheck(id string, want *idLens) resource.TestCheckFunc {
 		if got, want := len(hexStr), want.hexLen; got != want {
 			return fmt.Errorf("hex string length is %d; want %d", got, want)
 		}
+		if got, want := len(b32CrockfordStr), want.b32CrockfordLen; got != want {
+			return fmt.Errorf("base32 Crockford string length is %d; want %d", got, want)
+		}
+		if got, max := len(b58Str), want.b58MaxLen; got < 1 || got > max {
+			return fmt.Errorf("base58 string length is %d; want between 1 and %d", got, max)
+		}
 		if len(decStr) < 1 {
 			return fmt.Errorf("decimal string is empty; want at least one digit")
 		}
@@ -95,16 +764,69 @@ func testAccResourceIDCheck(id string, want *idLens) resource.TestCheckFunc {
 	}
 }
 
+func testAccResourceIDConfigHMAC(key string) string {
+	return fmt.Sprintf(`
+resource "random_id" "tenant" {
+  byte_length = 8
+  hmac_key    = %q
+}`, key)
+}
+
+func testAccResourceIDConfigHexUppercase(uppercase bool) string {
+	return fmt.Sprintf(`
+resource "random_id" "upper" {
+  byte_length   = 4
+  hex_uppercase = %t
+}`, uppercase)
+}
+
 const (
+	testAccResourceIDConfigPrefixSeparator = `
+resource "random_id" "sep" {
+  byte_length      = 4
+  prefix           = "cloud"
+  prefix_separator = "-"
+}`
+
+	testAccResourceIDConfigWithCommaInPrefix = `
+resource "random_id" "comma" {
+  byte_length = 4
+  prefix      = "cloud,web-"
+}`
+
+	testAccResourceIDConfigAlphabet = `
+resource "random_id" "code" {
+  byte_length = 5
+  prefix      = "code-"
+  alphabet    = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+}`
+
 	testAccResourceIDConfig = `
 resource "random_id" "foo" {
   byte_length = 4
 }`
 
+	testAccResourceIDConfigLength = `
+resource "random_id" "length" {
+  length = 11
+}`
+
 	testAccResourceIDConfigWithPrefix = `
 resource "random_id" "bar" {
   byte_length = 4
   prefix      = "cloud-"
 }
 `
+
+	testAccResourceIDConfigIPv6ULA = `
+resource "random_id" "ula" {
+  byte_length = 5
+  ipv6_ula    = true
+}`
+
+	testAccResourceIDConfigIPv6ULAInvalidLength = `
+resource "random_id" "ula" {
+  byte_length = 4
+  ipv6_ula    = true
+}`
 )
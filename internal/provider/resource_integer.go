@@ -2,12 +2,17 @@ package provider
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
+	"math"
+	"math/big"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceInteger() *schema.Resource {
@@ -21,53 +26,168 @@ func resourceInteger() *schema.Resource {
 		CreateContext: CreateInteger,
 		ReadContext:   schema.NoopContext,
 		DeleteContext: RemoveResourceFromState,
+		CustomizeDiff: logKeepersChange,
 		Importer: &schema.ResourceImporter{
 			StateContext: ImportInteger,
 		},
 
-		Schema: map[string]*schema.Schema{
-			"keepers": {
-				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
-					"resource. See [the main provider documentation](../index.html) for more information.",
-				Type:     schema.TypeMap,
-				Optional: true,
-				ForceNew: true,
+		Schema:        integerSchemaV1(),
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceIntegerV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: generationStateUpgrade,
 			},
+		},
+		UseJSONNumber: true,
+	}
+}
 
-			"min": {
-				Description: "The minimum inclusive value of the range.",
-				Type:        schema.TypeInt,
-				Required:    true,
-				ForceNew:    true,
-			},
+func resourceIntegerV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: integerSchemaV0(),
+	}
+}
 
-			"max": {
-				Description: "The maximum inclusive value of the range.",
-				Type:        schema.TypeInt,
-				Required:    true,
-				ForceNew:    true,
-			},
+// integerSchemaV1 uses integerSchemaV0 to obtain the V0 version of the Schema key-value entries but requires that the
+// generation entry be configured.
+func integerSchemaV1() map[string]*schema.Schema {
+	integerSchema := integerSchemaV0()
+	integerSchema["generation"] = generationSchema(false)
 
-			"seed": {
-				Description: "A custom seed to always produce the same value.",
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-			},
+	return integerSchema
+}
+
+// integerSchemaV0 returns the Schema key-value entries of random_integer before the generation entry was added.
+func integerSchemaV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"keepers": {
+			Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+				"resource. See [the main provider documentation](../index.html) for more information.",
+			Type:     schema.TypeMap,
+			Optional: true,
+			ForceNew: true,
+		},
+
+		"min": {
+			Description: "The minimum inclusive value of the range.",
+			Type:        schema.TypeInt,
+			Required:    true,
+			ForceNew:    true,
+		},
+
+		"max": {
+			Description: "The maximum value of the range, which is inclusive unless `max_inclusive` is `false`.",
+			Type:        schema.TypeInt,
+			Required:    true,
+			ForceNew:    true,
+		},
+
+		"max_inclusive": {
+			Description: "Whether `max` is included in the range. When `false`, the result is generated from the " +
+				"half-open range `[min, max)`, e.g., `max = length(var.list)` to pick an index of a list, and " +
+				"`max` must be greater than `min`. Default value is `true`.",
+			Type:     schema.TypeBool,
+			Optional: true,
+			ForceNew: true,
+		},
+
+		"seed": {
+			Description: "A custom seed to always produce the same value. When set, the value is generated " +
+				"using a non-cryptographic random number generator seeded from `seed`, otherwise a " +
+				"cryptographic random number generator is used.",
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+		},
+
+		"coprime_with": {
+			Description: "When set, the result is guaranteed to be coprime with this value, i.e. the " +
+				"greatest common divisor of the result and `coprime_with` is 1. The minimum value is 2.",
+			Type:             schema.TypeInt,
+			Optional:         true,
+			ForceNew:         true,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(2)),
+		},
+
+		"avoid_residues": {
+			Description: "A residue that the result must avoid, i.e. the result modulo `modulus` will not " +
+				"equal `residue`. May be specified more than once. At least one value between `min` and `max` " +
+				"must avoid every residue.",
+			Type:     schema.TypeList,
+			Optional: true,
+			ForceNew: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"modulus": {
+						Description:      "The modulus. The minimum value is 2.",
+						Type:             schema.TypeInt,
+						Required:         true,
+						ForceNew:         true,
+						ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(2)),
+					},
 
-			"result": {
-				Description: "The random integer result.",
-				Type:        schema.TypeInt,
-				Computed:    true,
+					"residue": {
+						Description: "The residue to avoid, which must be less than `modulus`. The " +
+							"minimum value is 0.",
+						Type:             schema.TypeInt,
+						Required:         true,
+						ForceNew:         true,
+						ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+					},
+				},
 			},
+		},
 
-			"id": {
-				Description: "The string representation of the integer result.",
-				Type:        schema.TypeString,
-				Computed:    true,
+		"multiple_of": {
+			Description: "When set, the result is guaranteed to be a multiple of this value, e.g. `10` for " +
+				"disk sizes in multiples of 10 GB. At least one multiple must lie between `min` and `max`. The " +
+				"minimum value is 1.",
+			Type:             schema.TypeInt,
+			Optional:         true,
+			ForceNew:         true,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+		},
+
+		"exclude": {
+			Description: "Values that the result must not equal, e.g. reserved ports. At least one value " +
+				"between `min` and `max` must not be excluded.",
+			Type:     schema.TypeList,
+			Optional: true,
+			ForceNew: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeInt,
 			},
 		},
-		UseJSONNumber: true,
+
+		"append_luhn": {
+			Description: "Append a Luhn check digit to the result, e.g., for account numbers that must pass a " +
+				"Luhn check. The result is then the generated value followed by `check_digit`, so " +
+				"`min` and `max` apply to the generated value rather than to the result. When `true`, `min` " +
+				"must be at least 0. Default value is `false`.",
+			Type:     schema.TypeBool,
+			Optional: true,
+			ForceNew: true,
+		},
+
+		"result": {
+			Description: "The random integer result.",
+			Type:        schema.TypeInt,
+			Computed:    true,
+		},
+
+		"check_digit": {
+			Description: "The Luhn check digit appended to the result when `append_luhn` is `true`.",
+			Type:        schema.TypeInt,
+			Computed:    true,
+		},
+
+		"id": {
+			Description: "The string representation of the integer result.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
 	}
 }
 
@@ -76,6 +196,24 @@ func CreateInteger(_ context.Context, d *schema.ResourceData, meta interface{})
 	min := d.Get("min").(int)
 	max := d.Get("max").(int)
 	seed := d.Get("seed").(string)
+	if seed == "" {
+		seed = resourceSeed(meta, "random_integer", resourceInteger().Schema, d)
+	}
+	coprimeWith := d.Get("coprime_with").(int)
+	multipleOf := d.Get("multiple_of").(int)
+
+	// max_inclusive is read from the raw config as it has no default, so that states created before it was added do
+	// not plan a replacement, but is true when unset.
+	if config := d.GetRawConfig(); !config.IsNull() && !config.GetAttr("max_inclusive").IsNull() &&
+		!d.Get("max_inclusive").(bool) {
+		if max <= min {
+			return append(diags, diag.Errorf("max (%d) must be greater than min (%d) when max_inclusive is false",
+				max, min)...)
+		}
+
+		// From here on max is the largest value that may be generated.
+		max--
+	}
 
 	if max < min {
 		return append(diags, diag.Diagnostic{
@@ -83,18 +221,397 @@ func CreateInteger(_ context.Context, d *schema.ResourceData, meta interface{})
 			Summary:  "minimum value needs to be smaller than or equal to maximum value",
 		})
 	}
-	rand := NewRand(seed)
-	number := rand.Intn((max+1)-min) + min
+
+	appendLuhn := d.Get("append_luhn").(bool)
+	if appendLuhn && min < 0 {
+		return append(diags, diag.Errorf("min (%d) must be >= 0 when append_luhn is true", min)...)
+	}
+	if appendLuhn && max > maxLuhnInteger {
+		return append(diags, diag.Errorf("max (%d) must be <= %d when append_luhn is true, so that the result "+
+			"with the check digit appended does not overflow", max, maxLuhnInteger)...)
+	}
+
+	// The result is generated from the multiples of multipleOf between first and last, so that it is always a
+	// multiple without needing to regenerate it.
+	var first, last int
+	if multipleOf > 0 {
+		first = min + mod(-min, multipleOf)
+		last = max - mod(max, multipleOf)
+
+		if first > last {
+			return append(diags, diag.Errorf("no multiple of %d lies between %d and %d", multipleOf, min, max)...)
+		}
+	}
+
+	var avoidResidues []residue
+	for _, v := range d.Get("avoid_residues").([]interface{}) {
+		m := v.(map[string]interface{})
+		r := residue{modulus: m["modulus"].(int), residue: m["residue"].(int)}
+
+		if r.residue >= r.modulus {
+			return append(diags, diag.Errorf("avoid_residues residue (%d) must be less than modulus (%d)", r.residue, r.modulus)...)
+		}
+
+		avoidResidues = append(avoidResidues, r)
+	}
+
+	excluded := make(map[int]bool)
+	for _, v := range d.Get("exclude").([]interface{}) {
+		excluded[v.(int)] = true
+	}
+
+	// periodic returns whether number is allowed by coprime_with and avoid_residues, which depends only on number
+	// modulo coprime_with and each modulus.
+	periodic := func(number int) bool {
+		if coprimeWith > 0 && gcd(number, coprimeWith) != 1 {
+			return false
+		}
+
+		for _, r := range avoidResidues {
+			if mod(number, r.modulus) == r.residue {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	allowed := func(number int) bool {
+		return !excluded[number] && (multipleOf == 0 || mod(number, multipleOf) == 0) && periodic(number)
+	}
+
+	infeasible := func() diag.Diagnostics {
+		switch {
+		case len(excluded) > 0:
+			return append(diags, diag.Errorf("no value between %d and %d remains once exclude and the other "+
+				"constraints are applied", min, max)...)
+		case len(avoidResidues) == 0:
+			return append(diags, diag.Errorf("no value between %d and %d is coprime with %d", min, max, coprimeWith)...)
+		case coprimeWith > 0:
+			return append(diags, diag.Errorf("no value between %d and %d avoids the configured residues and is "+
+				"coprime with %d", min, max, coprimeWith)...)
+		default:
+			return append(diags, diag.Errorf("no value between %d and %d avoids the configured residues", min, max)...)
+		}
+	}
+
+	var next func() (int, error)
+	candidates := integerCandidates{first: min, step: 1, lastIndex: uint64(max) - uint64(min)}
+	if multipleOf > 0 {
+		candidates = integerCandidates{first: first, step: multipleOf, lastIndex: (uint64(last) - uint64(first)) / uint64(multipleOf)}
+		nextIndex := integerGenerator(seed, 0, (last-first)/multipleOf)
+
+		next = func() (int, error) {
+			index, err := nextIndex()
+			return first + index*multipleOf, err
+		}
+	} else {
+		next = integerGenerator(seed, min, max)
+	}
+
+	var moduli []int
+	if coprimeWith > 0 {
+		moduli = append(moduli, coprimeWith)
+	}
+	for _, r := range avoidResidues {
+		moduli = append(moduli, r.modulus)
+	}
+
+	var number int
+	var err error
+	if coprimeWith == 0 && len(avoidResidues) == 0 && len(excluded) == 0 {
+		number, err = next()
+		if err != nil {
+			return append(diags, diag.Errorf("error generating random integer: %s", err)...)
+		}
+	} else if set, ok := newAllowedIntegers(candidates, moduli, periodic, excluded); ok {
+		// The allowed values are counted and one is chosen uniformly by its position, so that the result does not
+		// depend on how few values are allowed.
+		count := set.count()
+		if count.Sign() == 0 {
+			return infeasible()
+		}
+
+		n, err := randomBigIndex(seed, count)
+		if err != nil {
+			return append(diags, diag.Errorf("error generating random integer: %s", err)...)
+		}
+
+		number = set.nth(n.Uint64())
+	} else {
+		// There are too many candidates in a period to count them, so random candidates are rejected until one is
+		// allowed. As a period then spans more than maxIntegerPeriod candidates, each constraint removes only a small
+		// proportion of them. Each excluded value can remove at most one allowed value, so if any value is allowed it
+		// will be found within the first len(excluded)+1 periods.
+		size := max - min + 1
+		if size <= 0 {
+			// max-min+1 overflows when the range spans more than half of the values of an int.
+			size = math.MaxInt
+		}
+		period := 1
+		for _, m := range append(moduli, multipleOf) {
+			if m > 0 {
+				period = lcm(period, m, size)
+			}
+		}
+
+		count := size
+		if period <= size/(len(excluded)+1) {
+			count = period * (len(excluded) + 1)
+		}
+		if count > maxIntegerPeriod {
+			count = maxIntegerPeriod
+		}
+
+		if !anyInRange(min, max, count, allowed) {
+			return infeasible()
+		}
+
+		number, err = next()
+		if err != nil {
+			return append(diags, diag.Errorf("error generating random integer: %s", err)...)
+		}
+
+		for attempt := 1; !allowed(number); attempt++ {
+			if attempt >= maxIntegerPeriod {
+				return append(diags, diag.Errorf("unable to generate a value between %d and %d that satisfies "+
+					"the configured constraints after %d attempts", min, max, maxIntegerPeriod)...)
+			}
+
+			number, err = next()
+			if err != nil {
+				return append(diags, diag.Errorf("error generating random integer: %s", err)...)
+			}
+		}
+	}
+
+	if appendLuhn {
+		checkDigit, err := luhnCheckDigit(strconv.Itoa(number))
+		if err != nil {
+			return append(diags, diag.Errorf("error computing check digit: %s", err)...)
+		}
+		if err := d.Set("check_digit", checkDigit); err != nil {
+			return diag.Errorf("error setting check_digit: %s", err)
+		}
+
+		number = number*10 + checkDigit
+	}
 
 	if err := d.Set("result", number); err != nil {
 		return diag.Errorf("error setting result: %s", err)
 	}
 
+	if err := setGeneration(d); err != nil {
+		return diag.Errorf("error setting generation: %s", err)
+	}
+
 	d.SetId(strconv.Itoa(number))
 
 	return nil
 }
 
+// maxLuhnInteger is the largest value of random_integer to which a check digit can be appended without overflowing.
+const maxLuhnInteger = (math.MaxInt - 9) / 10
+
+// gcd returns the greatest common divisor of the absolute values of a and b.
+func gcd(a, b int) int {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// residue is a value that the result of a random_integer, modulo modulus, must not equal.
+type residue struct {
+	modulus int
+	residue int
+}
+
+// mod returns a modulo m in the range [0, m), unlike the % operator whose result takes the sign of a.
+func mod(a, m int) int {
+	return ((a % m) + m) % m
+}
+
+// lcm returns the least common multiple of a and b, or limit if that is smaller. A value of a less than 1 is
+// treated as 1.
+func lcm(a, b, limit int) int {
+	if a < 1 {
+		a = 1
+	}
+
+	multiple := a / gcd(a, b)
+	if multiple > limit/b {
+		return limit
+	}
+
+	return multiple * b
+}
+
+// maxIntegerPeriod bounds the number of candidates that newAllowedIntegers enumerates to count those allowed.
+const maxIntegerPeriod = 1 << 20
+
+// integerCandidates are the values first + i*step for i from 0 to lastIndex inclusive.
+type integerCandidates struct {
+	first     int
+	step      int
+	lastIndex uint64
+}
+
+// value returns the candidate at index i. The arithmetic wraps, as the difference between the first and last
+// candidates may not fit in an int.
+func (c integerCandidates) value(i uint64) int {
+	return int(uint64(c.first) + i*uint64(c.step))
+}
+
+// index returns the index of value among the candidates and whether it is one of them.
+func (c integerCandidates) index(value int) (uint64, bool) {
+	if value < c.first {
+		return 0, false
+	}
+
+	offset := uint64(value) - uint64(c.first)
+	if offset%uint64(c.step) != 0 || offset/uint64(c.step) > c.lastIndex {
+		return 0, false
+	}
+
+	return offset / uint64(c.step), true
+}
+
+// allowedIntegers are the candidates allowed by a predicate that repeats every period candidates, less those that
+// are excluded. They are counted and indexed from the candidates of a single period, rather than by testing every
+// candidate, in the same way that nthPort indexes the ports that are not excluded.
+type allowedIntegers struct {
+	candidates integerCandidates
+	period     uint64
+	// offsets are the sorted indices in [0, period) of the candidates allowed by the predicate.
+	offsets []uint64
+	// excludedRanks are the sorted positions, among the candidates allowed by the predicate, of those excluded.
+	excludedRanks []uint64
+}
+
+// newAllowedIntegers returns the candidates allowed by periodic, whose result for a value depends only on the value
+// modulo each of moduli, that are not in excluded. It returns false when a period spans more than maxIntegerPeriod
+// candidates, which are then too many to enumerate.
+func newAllowedIntegers(candidates integerCandidates, moduli []int, periodic func(int) bool, excluded map[int]bool) (*allowedIntegers, bool) {
+	// Stepping from one candidate to the next, a value modulo m repeats every m/gcd(m, step) candidates.
+	period := 1
+	for _, m := range moduli {
+		period = lcm(period, m/gcd(m, candidates.step), maxIntegerPeriod+1)
+	}
+
+	size := uint64(period)
+	if candidates.lastIndex < size {
+		size = candidates.lastIndex + 1
+	} else if period > maxIntegerPeriod {
+		return nil, false
+	}
+
+	a := &allowedIntegers{candidates: candidates, period: uint64(period)}
+	for i := uint64(0); i < size; i++ {
+		if periodic(candidates.value(i)) {
+			a.offsets = append(a.offsets, i)
+		}
+	}
+
+	for v := range excluded {
+		if i, ok := candidates.index(v); ok && periodic(v) {
+			r, _ := a.rank(i)
+			a.excludedRanks = append(a.excludedRanks, r)
+		}
+	}
+	sort.Slice(a.excludedRanks, func(i, j int) bool { return a.excludedRanks[i] < a.excludedRanks[j] })
+
+	return a, true
+}
+
+// rank returns the number of candidates before index i that are allowed by the predicate, and whether the candidate
+// at i is also allowed.
+func (a *allowedIntegers) rank(i uint64) (uint64, bool) {
+	offset := i % a.period
+	below := sort.Search(len(a.offsets), func(j int) bool { return a.offsets[j] >= offset })
+
+	return i/a.period*uint64(len(a.offsets)) + uint64(below), below < len(a.offsets) && a.offsets[below] == offset
+}
+
+// count returns the number of allowed candidates, which exceeds the range of an int64 when the candidates span most
+// of the values of an int.
+func (a *allowedIntegers) count() *big.Int {
+	r, ok := a.rank(a.candidates.lastIndex)
+	n := new(big.Int).SetUint64(r)
+	if ok {
+		n.Add(n, big.NewInt(1))
+	}
+
+	return n.Sub(n, big.NewInt(int64(len(a.excludedRanks))))
+}
+
+// nth returns the allowed candidate n places from the first, counting from 0, where n is less than count.
+func (a *allowedIntegers) nth(n uint64) int {
+	// Each excluded candidate at or before the nth allowed one moves it one place further on.
+	for _, r := range a.excludedRanks {
+		if r > n {
+			break
+		}
+		n++
+	}
+
+	perPeriod := uint64(len(a.offsets))
+	return a.candidates.value(n/perPeriod*a.period + a.offsets[n%perPeriod])
+}
+
+// randomBigIndex returns a random value in [0, n). When seed is set the value comes from a math/rand generator
+// seeded from it, as for integerGenerator, otherwise from crypto/rand.
+func randomBigIndex(seed string, n *big.Int) (*big.Int, error) {
+	if seed != "" {
+		return new(big.Int).Rand(NewRand(seed), n), nil
+	}
+
+	return rand.Int(rand.Reader, n)
+}
+
+// anyInRange returns true if any of the first count values from min, not exceeding max, is allowed.
+func anyInRange(min, max, count int, allowed func(int) bool) bool {
+	for i := 0; i < count && min+i <= max; i++ {
+		if allowed(min + i) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// integerGenerator returns a function producing successive random values between min and max inclusive. When seed
+// is set the values come from a math/rand generator seeded from it, so that the same seed always produces the same
+// values, otherwise they come from crypto/rand.
+func integerGenerator(seed string, min, max int) func() (int, error) {
+	if seed != "" {
+		rand := NewRand(seed)
+
+		return func() (int, error) {
+			return rand.Intn((max+1)-min) + min, nil
+		}
+	}
+
+	// The size of the range is computed with big.Int as max-min+1 may overflow an int.
+	size := new(big.Int).Sub(big.NewInt(int64(max)), big.NewInt(int64(min)))
+	size.Add(size, big.NewInt(1))
+
+	return func() (int, error) {
+		n, err := rand.Int(rand.Reader, size)
+		if err != nil {
+			return 0, err
+		}
+
+		return int(n.Add(n, big.NewInt(int64(min))).Int64()), nil
+	}
+}
+
 func ImportInteger(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	parts := strings.Split(d.Id(), ",")
 	if len(parts) != 3 && len(parts) != 4 {
@@ -134,6 +651,10 @@ func ImportInteger(_ context.Context, d *schema.ResourceData, meta interface{})
 		}
 	}
 
+	if err := d.Set("generation", 1); err != nil {
+		return nil, fmt.Errorf("error setting generation: %w", err)
+	}
+
 	d.SetId(parts[0])
 
 	return []*schema.ResourceData{d}, nil
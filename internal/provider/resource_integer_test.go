@@ -1,7 +1,12 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -115,6 +120,50 @@ func TestAccResourceIntegerBig(t *testing.T) {
 	})
 }
 
+func TestAccResourceIntegerCoprimeWith(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testRandomIntegerCoprimeWith,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceIntegerCoprimeWith("random_integer.integer_1", 12),
+				),
+			},
+			{
+				Config:      testRandomIntegerCoprimeWithInvalid,
+				ExpectError: regexp.MustCompile(`.*expected coprime_with to be at least \(2\), got 1`),
+			},
+		},
+	})
+}
+
+func TestAccResourceIntegerAvoidResidues(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testRandomIntegerAvoidResidues,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceIntegerAvoidResidues("random_integer.integer_1", map[int]int{4: 0, 3: 1}),
+				),
+			},
+			{
+				Config:      testRandomIntegerAvoidResiduesInfeasible,
+				ExpectError: regexp.MustCompile(`.*no value between 1 and 100 avoids the configured residues`),
+			},
+			{
+				Config:      testRandomIntegerAvoidResiduesInvalid,
+				ExpectError: regexp.MustCompile(`.*avoid_residues residue \(4\) must be less than modulus \(4\)`),
+			},
+		},
+	})
+}
+
 func testAccResourceIntegerBasic(id string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[id]
@@ -182,6 +231,167 @@ func testAccResourceIntegerSeedless(id string) resource.TestCheckFunc {
 	}
 }
 
+// testAccResourceIntegerCoprimeWith checks that the result shares no common divisor (other than 1) with modulus.
+func testAccResourceIntegerCoprimeWith(id string, modulus int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("Not found: %s", id)
+		}
+		result, err := strconv.Atoi(rs.Primary.Attributes["result"])
+		if err != nil {
+			return fmt.Errorf("Invalid result: %s", err)
+		}
+
+		if gcd(result, modulus) != 1 {
+			return fmt.Errorf("Invalid result %d. Result is not coprime with %d", result, modulus)
+		}
+		return nil
+	}
+}
+
+func TestAccResourceIntegerExclude(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testRandomIntegerExclude,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("random_integer.port", "result", "8003"),
+				),
+			},
+			{
+				Config: testRandomIntegerExcludeWithResidues,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("random_integer.port", "result", "5"),
+				),
+			},
+			{
+				Config:      testRandomIntegerExcludeInfeasible,
+				ExpectError: regexp.MustCompile(`.*no value between 1 and 3 remains once exclude and the other constraints are applied`),
+			},
+		},
+	})
+}
+
+func TestAccResourceIntegerMultipleOf(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testRandomIntegerMultipleOf,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceIntegerMultipleOf("random_integer.disk_size", 15, 95, 10),
+				),
+			},
+			{
+				Config:      testRandomIntegerMultipleOfInfeasible,
+				ExpectError: regexp.MustCompile(`.*no multiple of 10 lies between 11 and 19`),
+			},
+		},
+	})
+}
+
+func TestAccResourceIntegerAppendLuhn(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testRandomIntegerAppendLuhn,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_integer.account", "result", regexp.MustCompile(`^[1-9][0-9]{6}$`)),
+					resource.TestCheckResourceAttrWith("random_integer.account", "result", func(value string) error {
+						if !luhnValid(value, numChars) {
+							return fmt.Errorf("result %q does not pass a Luhn check", value)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith("random_integer.account", "check_digit", func(value string) error {
+						if len(value) != 1 {
+							return fmt.Errorf("check_digit %q is not a single digit", value)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config:      testRandomIntegerAppendLuhnNegative,
+				ExpectError: regexp.MustCompile(`.*min \(-5\) must be >= 0 when append_luhn is true`),
+			},
+		},
+	})
+}
+
+func TestAccResourceIntegerMaxInclusive(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testRandomIntegerMaxInclusive,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("random_integer.inclusive", "result", "7"),
+					resource.TestCheckResourceAttr("random_integer.exclusive", "result", "7"),
+					resource.TestCheckResourceAttr("random_integer.exclusive_negative", "result", "-1"),
+					resource.TestCheckResourceAttr("random_integer.exclusive_max_int", "result", "9223372036854775806"),
+				),
+			},
+			{
+				Config:      testRandomIntegerMaxExclusiveEmpty,
+				ExpectError: regexp.MustCompile(`.*max \(7\) must be greater than min \(7\) when max_inclusive is false`),
+			},
+		},
+	})
+}
+
+func testAccResourceIntegerAvoidResidues(id string, residues map[int]int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("Not found: %s", id)
+		}
+		result, err := strconv.Atoi(rs.Primary.Attributes["result"])
+		if err != nil {
+			return fmt.Errorf("Invalid result: %s", err)
+		}
+
+		for modulus, residue := range residues {
+			if mod(result, modulus) == residue {
+				return fmt.Errorf("Invalid result %d. Result is congruent to %d modulo %d", result, residue, modulus)
+			}
+		}
+		return nil
+	}
+}
+
+func testAccResourceIntegerMultipleOf(id string, min, max, multipleOf int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("Not found: %s", id)
+		}
+		result, err := strconv.Atoi(rs.Primary.Attributes["result"])
+		if err != nil {
+			return fmt.Errorf("Invalid result: %s", err)
+		}
+
+		if result < min || result > max {
+			return fmt.Errorf("Invalid result %d. Result is outside the range %d to %d", result, min, max)
+		}
+		if result%multipleOf != 0 {
+			return fmt.Errorf("Invalid result %d. Result is not a multiple of %d", result, multipleOf)
+		}
+		return nil
+	}
+}
+
 const (
 	testRandomIntegerBasic = `
 resource "random_integer" "integer_1" {
@@ -212,4 +422,349 @@ resource "random_integer" "integer_1" {
    min  = 7227701560655103597
    seed = 12345
 }`
+
+	testRandomIntegerCoprimeWith = `
+resource "random_integer" "integer_1" {
+   min          = 1
+   max          = 1000
+   coprime_with = 12
+}
+`
+
+	testRandomIntegerCoprimeWithInvalid = `
+resource "random_integer" "integer_1" {
+   min          = 1
+   max          = 1000
+   coprime_with = 1
+}
+`
+
+	testRandomIntegerAvoidResidues = `
+resource "random_integer" "integer_1" {
+   min = 1
+   max = 12
+
+   avoid_residues {
+     modulus = 4
+     residue = 0
+   }
+
+   avoid_residues {
+     modulus = 3
+     residue = 1
+   }
+}
+`
+
+	testRandomIntegerAvoidResiduesInfeasible = `
+resource "random_integer" "integer_1" {
+   min = 1
+   max = 100
+
+   avoid_residues {
+     modulus = 2
+     residue = 0
+   }
+
+   avoid_residues {
+     modulus = 2
+     residue = 1
+   }
+}
+`
+
+	testRandomIntegerAvoidResiduesInvalid = `
+resource "random_integer" "integer_1" {
+   min = 1
+   max = 100
+
+   avoid_residues {
+     modulus = 4
+     residue = 4
+   }
+}
+`
+
+	testRandomIntegerMaxInclusive = `
+resource "random_integer" "inclusive" {
+   min           = 7
+   max           = 7
+   max_inclusive = true
+}
+
+resource "random_integer" "exclusive" {
+   min           = 7
+   max           = 8
+   max_inclusive = false
+}
+
+resource "random_integer" "exclusive_negative" {
+   min           = -1
+   max           = 0
+   max_inclusive = false
+}
+
+resource "random_integer" "exclusive_max_int" {
+   min           = 9223372036854775806
+   max           = 9223372036854775807
+   max_inclusive = false
+}
+`
+
+	testRandomIntegerMaxExclusiveEmpty = `
+resource "random_integer" "exclusive" {
+   min           = 7
+   max           = 7
+   max_inclusive = false
+}
+`
+
+	testRandomIntegerMultipleOf = `
+resource "random_integer" "disk_size" {
+   min         = 15
+   max         = 95
+   multiple_of = 10
+}
+`
+
+	testRandomIntegerMultipleOfInfeasible = `
+resource "random_integer" "disk_size" {
+   min         = 11
+   max         = 19
+   multiple_of = 10
+}
+`
+
+	testRandomIntegerAppendLuhn = `
+resource "random_integer" "account" {
+   min         = 100000
+   max         = 999999
+   append_luhn = true
+}
+`
+
+	testRandomIntegerAppendLuhnNegative = `
+resource "random_integer" "account" {
+   min         = -5
+   max         = 5
+   append_luhn = true
+}
+`
+
+	testRandomIntegerExclude = `
+resource "random_integer" "port" {
+   min     = 8000
+   max     = 8003
+   exclude = [8000, 8001, 8002]
+}
+`
+
+	testRandomIntegerExcludeWithResidues = `
+resource "random_integer" "port" {
+   min     = 1
+   max     = 6
+   exclude = [1, 3]
+
+   avoid_residues {
+     modulus = 2
+     residue = 0
+   }
+}
+`
+
+	testRandomIntegerExcludeInfeasible = `
+resource "random_integer" "port" {
+   min     = 1
+   max     = 3
+   exclude = [3, 1, 2, 2]
+}
+`
 )
+
+func TestLcm(t *testing.T) {
+	cases := []struct {
+		name     string
+		a, b     int
+		limit    int
+		expected int
+	}{
+		{"coprime", 4, 3, 100, 12},
+		{"common factor", 4, 6, 100, 12},
+		{"unset", 0, 5, 100, 5},
+		{"limited", 97, 89, 100, 100},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := lcm(c.a, c.b, c.limit); got != c.expected {
+				t.Errorf("expected %d, got %d", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestIntegerGenerator(t *testing.T) {
+	cases := []struct {
+		name     string
+		min, max int
+	}{
+		{"small", 1, 3},
+		{"single", 7, 7},
+		{"negative", -5, -1},
+		{"full range", math.MinInt64, math.MaxInt64},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			next := integerGenerator("", c.min, c.max)
+			for i := 0; i < 100; i++ {
+				got, err := next()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got < c.min || got > c.max {
+					t.Fatalf("got %d; want a value between %d and %d", got, c.min, c.max)
+				}
+			}
+		})
+	}
+
+	seen := make(map[int]bool)
+	next := integerGenerator("", 1, 3)
+	for i := 0; i < 100; i++ {
+		got, err := next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen[got] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("expected every value between 1 and 3 to be generated, got %v", seen)
+	}
+
+	first, err := integerGenerator("12345", 1, 1000)()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if got, _ := integerGenerator("12345", 1, 1000)(); got != first {
+			t.Fatalf("expected seeded values to match, got %d and %d", first, got)
+		}
+	}
+}
+
+func TestAllowedIntegers(t *testing.T) {
+	cases := []struct {
+		name        string
+		candidates  integerCandidates
+		coprimeWith int
+		residues    []residue
+		excluded    []int
+	}{
+		{name: "exclude only", candidates: integerCandidates{first: 1, step: 1, lastIndex: 199}, excluded: []int{1, 5, 200, 300}},
+		{name: "coprime", candidates: integerCandidates{first: -20, step: 1, lastIndex: 60}, coprimeWith: 12},
+		{name: "residues and exclude", candidates: integerCandidates{first: 3, step: 1, lastIndex: 97},
+			residues: []residue{{modulus: 4, residue: 0}, {modulus: 6, residue: 1}}, excluded: []int{5, 7, 8, 11}},
+		{name: "multiples", candidates: integerCandidates{first: 10, step: 5, lastIndex: 40}, coprimeWith: 3,
+			residues: []residue{{modulus: 4, residue: 1}}, excluded: []int{20, 25, 27}},
+		{name: "nothing allowed", candidates: integerCandidates{first: 2, step: 2, lastIndex: 10}, coprimeWith: 2},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			excluded := make(map[int]bool)
+			for _, v := range c.excluded {
+				excluded[v] = true
+			}
+			moduli := []int{}
+			if c.coprimeWith > 0 {
+				moduli = append(moduli, c.coprimeWith)
+			}
+			for _, r := range c.residues {
+				moduli = append(moduli, r.modulus)
+			}
+			periodic := func(number int) bool {
+				if c.coprimeWith > 0 && gcd(number, c.coprimeWith) != 1 {
+					return false
+				}
+				for _, r := range c.residues {
+					if mod(number, r.modulus) == r.residue {
+						return false
+					}
+				}
+				return true
+			}
+
+			var expected []int
+			for i := uint64(0); i <= c.candidates.lastIndex; i++ {
+				if v := c.candidates.value(i); periodic(v) && !excluded[v] {
+					expected = append(expected, v)
+				}
+			}
+
+			set, ok := newAllowedIntegers(c.candidates, moduli, periodic, excluded)
+			if !ok {
+				t.Fatal("expected the candidates to be enumerable")
+			}
+			if got := set.count(); got.Cmp(big.NewInt(int64(len(expected)))) != 0 {
+				t.Fatalf("expected count %d, got %s", len(expected), got)
+			}
+			for i, v := range expected {
+				if got := set.nth(uint64(i)); got != v {
+					t.Errorf("expected value %d at %d, got %d", v, i, got)
+				}
+			}
+		})
+	}
+
+	full := integerCandidates{first: math.MinInt64, step: 1, lastIndex: math.MaxUint64}
+	set, ok := newAllowedIntegers(full, nil, func(int) bool { return true }, map[int]bool{0: true})
+	if !ok {
+		t.Fatal("expected the full range to be enumerable")
+	}
+	if got, expected := set.count(), new(big.Int).SetUint64(math.MaxUint64); got.Cmp(expected) != 0 {
+		t.Errorf("expected count %s, got %s", expected, got)
+	}
+	if got := set.nth(uint64(1) << 63); got != 1 {
+		t.Errorf("expected 0 to be skipped, got %d", got)
+	}
+
+	if _, ok := newAllowedIntegers(integerCandidates{first: 0, step: 1, lastIndex: math.MaxUint64}, []int{maxIntegerPeriod + 1},
+		func(int) bool { return true }, nil); ok {
+		t.Error("expected a period longer than maxIntegerPeriod not to be enumerated")
+	}
+}
+
+func TestCreateIntegerSparse(t *testing.T) {
+	exclude := make([]interface{}, 0, 199)
+	for i := 1; i < 200; i++ {
+		exclude = append(exclude, i)
+	}
+
+	for i := 0; i < 200; i++ {
+		d := resourceInteger().TestResourceData()
+		for k, v := range map[string]interface{}{"min": 1, "max": 200, "exclude": exclude} {
+			if err := d.Set(k, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if diags := CreateInteger(context.Background(), d, nil); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if got := d.Get("result").(int); got != 200 {
+			t.Fatalf("expected 200, got %d", got)
+		}
+	}
+
+	d := resourceInteger().TestResourceData()
+	for k, v := range map[string]interface{}{"min": 4, "max": 4, "coprime_with": 2} {
+		if err := d.Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	diags := CreateInteger(context.Background(), d, nil)
+	if !diags.HasError() || diags[0].Summary != "no value between 4 and 4 is coprime with 2" {
+		t.Errorf("expected coprime_with to be infeasible, got: %v", diags)
+	}
+}
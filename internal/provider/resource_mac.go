@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	// macLength is the length in bytes of an EUI-48 MAC address.
+	macLength = 6
+
+	// macMulticastBit is set in the first octet of a multicast MAC address.
+	macMulticastBit = 0x01
+
+	// macLocalBit is set in the first octet of a locally administered MAC address.
+	macLocalBit = 0x02
+)
+
+func resourceMAC() *schema.Resource {
+	return &schema.Resource{
+		Description: "The resource `random_mac` generates a random 48-bit MAC address, e.g., for the network " +
+			"interfaces of virtual machines.\n" +
+			"\n" +
+			"Unless `oui_prefix` is set, the address is a unicast, locally administered address, so it cannot " +
+			"clash with the address of any physical network interface.\n" +
+			"\n" +
+			"This resource *does* use a cryptographic random number generator.",
+		CreateContext: CreateMAC,
+		ReadContext:   schema.NoopContext,
+		DeleteContext: RemoveResourceFromState,
+		CustomizeDiff: logKeepersChange,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportMAC,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"keepers": {
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+					"resource. See [the main provider documentation](../index.html) for more information.",
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"oui_prefix": {
+				Description: "The first three octets of the address, given as colon or hyphen separated " +
+					"hexadecimal digits, e.g., `52:54:00`. Only the last three octets are random when set, and " +
+					"the locally administered bit is left as given. The multicast bit must not be set.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validateOUIPrefix),
+			},
+
+			"result": {
+				Description: "The generated MAC address in lowercase, colon separated form, e.g., " +
+					"`02:00:5e:10:00:01`.",
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"dash": {
+				Description: "The generated MAC address in lowercase, hyphen separated form, e.g., " +
+					"`02-00-5e-10-00-01`.",
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"dot": {
+				Description: "The generated MAC address in lowercase, dot separated groups of four digits, e.g., " +
+					"`0200.5e10.0001`.",
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"generation": generationSchema(false),
+
+			"id": {
+				Description: "The generated MAC address in lowercase, colon separated form.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func CreateMAC(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	var oui []byte
+	if v, ok := d.GetOk("oui_prefix"); ok {
+		var err error
+		if oui, err = parseOUIPrefix(v.(string)); err != nil {
+			return diag.Errorf("error parsing oui_prefix: %s", err)
+		}
+	}
+
+	mac, err := generateMAC(rand.Reader, oui)
+	if err != nil {
+		return diag.Errorf("error generating MAC address: %s", err)
+	}
+
+	if err := setMAC(d, mac); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setGeneration(d); err != nil {
+		return diag.Errorf("error setting generation: %s", err)
+	}
+
+	return nil
+}
+
+func ImportMAC(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	mac, err := net.ParseMAC(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing MAC address: %w", err)
+	}
+
+	if len(mac) != macLength {
+		return nil, fmt.Errorf("expected a 48-bit MAC address, got %d bits", 8*len(mac))
+	}
+
+	if err := setMAC(d, mac); err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("generation", 1); err != nil {
+		return nil, fmt.Errorf("error setting generation: %w", err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// generateMAC generates a MAC address from reader. When oui is nil, the address is random apart from the multicast
+// and locally administered bits, which are cleared and set respectively. Otherwise the address begins with oui and
+// only the remaining octets are random.
+func generateMAC(reader io.Reader, oui []byte) (net.HardwareAddr, error) {
+	mac := make(net.HardwareAddr, macLength)
+	if _, err := io.ReadFull(reader, mac); err != nil {
+		return nil, err
+	}
+
+	if oui != nil {
+		copy(mac, oui)
+	} else {
+		mac[0] = mac[0]&^macMulticastBit | macLocalBit
+	}
+
+	return mac, nil
+}
+
+// setMAC sets id and each of the formats of mac in d.
+func setMAC(d *schema.ResourceData, mac net.HardwareAddr) error {
+	d.SetId(mac.String())
+
+	hexStr := hex.EncodeToString(mac)
+	formats := map[string]string{
+		"result": mac.String(),
+		"dash":   strings.ReplaceAll(mac.String(), ":", "-"),
+		"dot":    hexStr[0:4] + "." + hexStr[4:8] + "." + hexStr[8:12],
+	}
+
+	for k, v := range formats {
+		if err := d.Set(k, v); err != nil {
+			return fmt.Errorf("error setting %s: %w", k, err)
+		}
+	}
+
+	return nil
+}
+
+// parseOUIPrefix parses three octets of hexadecimal digits separated by colons or hyphens.
+func parseOUIPrefix(s string) ([]byte, error) {
+	octets := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ':' || r == '-'
+	})
+	if len(octets) != 3 {
+		return nil, fmt.Errorf("expected 3 octets, got %q", s)
+	}
+
+	oui := make([]byte, 0, len(octets))
+	for _, octet := range octets {
+		if len(octet) != 2 {
+			return nil, fmt.Errorf("expected octets of 2 hexadecimal digits, got %q", octet)
+		}
+
+		b, err := hex.DecodeString(octet)
+		if err != nil {
+			return nil, fmt.Errorf("expected octets of 2 hexadecimal digits, got %q", octet)
+		}
+
+		oui = append(oui, b[0])
+	}
+
+	if oui[0]&macMulticastBit != 0 {
+		return nil, fmt.Errorf("the multicast bit must not be set, got %q", s)
+	}
+
+	return oui, nil
+}
+
+func validateOUIPrefix(i interface{}, k string) ([]string, []error) {
+	if _, err := parseOUIPrefix(i.(string)); err != nil {
+		return nil, []error{fmt.Errorf("invalid %s: %w", k, err)}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceMAC(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceMACConfig,
+				Check: resource.ComposeTestCheckFunc(
+					// The first octet of a unicast, locally administered address ends in 2, 6, a or e.
+					resource.TestMatchResourceAttr("random_mac.basic", "result", regexp.MustCompile(`^[0-9a-f][26ae](:[0-9a-f]{2}){5}$`)),
+					resource.TestMatchResourceAttr("random_mac.basic", "dash", regexp.MustCompile(`^[0-9a-f][26ae](-[0-9a-f]{2}){5}$`)),
+					resource.TestMatchResourceAttr("random_mac.basic", "dot", regexp.MustCompile(`^[0-9a-f][26ae][0-9a-f]{2}(\.[0-9a-f]{4}){2}$`)),
+					resource.TestCheckResourceAttrPair("random_mac.basic", "id", "random_mac.basic", "result"),
+					resource.TestMatchResourceAttr("random_mac.oui", "result", regexp.MustCompile(`^52:54:00(:[0-9a-f]{2}){3}$`)),
+				),
+			},
+			{
+				ResourceName:      "random_mac.basic",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:            "random_mac.oui",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"oui_prefix"},
+			},
+		},
+	})
+}
+
+func TestAccResourceMACErrors(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceMACConfigMulticastOUI,
+				ExpectError: regexp.MustCompile(`invalid oui_prefix: the multicast bit must not be set, got "01:00:5e"`),
+			},
+		},
+	})
+}
+
+func TestAccResourceMAC_importInvalid(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceMACConfig,
+			},
+			{
+				ResourceName:  "random_mac.basic",
+				ImportState:   true,
+				ImportStateId: "02:00:5e:10:00:00:00:01",
+				ExpectError:   regexp.MustCompile(`expected a 48-bit MAC address, got 64 bits`),
+			},
+		},
+	})
+}
+
+func TestGenerateMAC(t *testing.T) {
+	random := []byte{0xff, 0x11, 0x22, 0x33, 0x44, 0x55}
+
+	mac, err := generateMAC(bytes.NewReader(random), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := mac.String(), "fe:11:22:33:44:55"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+
+	mac, err = generateMAC(bytes.NewReader(random), []byte{0x52, 0x54, 0x00})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := mac.String(), "52:54:00:33:44:55"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+
+	if _, err := generateMAC(bytes.NewReader(random[:5]), nil); err == nil {
+		t.Error("expected error generating a MAC address from insufficient random bytes")
+	}
+}
+
+func TestParseOUIPrefix(t *testing.T) {
+	cases := []struct {
+		input       string
+		expected    []byte
+		expectedErr string
+	}{
+		{"52:54:00", []byte{0x52, 0x54, 0x00}, ""},
+		{"AC-DE-48", []byte{0xac, 0xde, 0x48}, ""},
+		{"52:54", nil, `expected 3 octets, got "52:54"`},
+		{"52:54:00:01", nil, `expected 3 octets, got "52:54:00:01"`},
+		{"525:4:00", nil, `expected octets of 2 hexadecimal digits, got "525"`},
+		{"52:zz:00", nil, `expected octets of 2 hexadecimal digits, got "zz"`},
+		{"01:00:5e", nil, `the multicast bit must not be set, got "01:00:5e"`},
+	}
+
+	for _, c := range cases {
+		got, err := parseOUIPrefix(c.input)
+		if c.expectedErr != "" {
+			if err == nil || err.Error() != c.expectedErr {
+				t.Errorf("%q: got error %v; want %q", c.input, err, c.expectedErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", c.input, err)
+			continue
+		}
+		if !bytes.Equal(got, c.expected) {
+			t.Errorf("%q: got %x; want %x", c.input, got, c.expected)
+		}
+	}
+}
+
+const (
+	testAccResourceMACConfig = `
+resource "random_mac" "basic" {
+}
+
+resource "random_mac" "oui" {
+  oui_prefix = "52:54:00"
+}
+`
+
+	testAccResourceMACConfigMulticastOUI = `
+resource "random_mac" "multicast" {
+  oui_prefix = "01:00:5e"
+}
+`
+)
@@ -2,22 +2,38 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/hkdf"
 )
 
 // resourcePassword and resourceString both use the same set of CustomizeDiffFunc(s) in order to handle the deprecation
 // of the `number` attribute and the simultaneous addition of the `numeric` attribute. planDefaultIfAllNull handles
 // ensuring that both `number` and `numeric` default to `true` when they are both absent from config.
 // planSyncIfChange handles keeping number and numeric in-sync when either one has been changed.
+// planEmptyOverrideSpecial rejects a min_special that an empty override_special cannot satisfy. logKeepersChange logs
+// which keepers keys changed and planKeepersChange decides whether a change to keepers replaces the resource or
+// rotates the password in place, in which case every attribute derived from the result, such as bcrypt_hash, is
+// regenerated along with it, and planGeneration plans the incremented generation.
 func resourcePassword() *schema.Resource {
 	customizeDiffFuncs := planDefaultIfAllNull(true, "number", "numeric")
 	customizeDiffFuncs = append(customizeDiffFuncs, planSyncIfChange("number", "numeric"))
 	customizeDiffFuncs = append(customizeDiffFuncs, planSyncIfChange("numeric", "number"))
+	customizeDiffFuncs = append(customizeDiffFuncs, planEmptyOverrideSpecial)
+	customizeDiffFuncs = append(customizeDiffFuncs, logKeepersChange)
+	customizeDiffFuncs = append(customizeDiffFuncs, planKeepersChange("result", "results", "bcrypt_hash", "sha256_hash",
+		"crypt_sha512", "password_hash", "secret_file", "result_base64", "result_hex"))
+	customizeDiffFuncs = append(customizeDiffFuncs, planGeneration)
 
 	return &schema.Resource{
 		Description: "Identical to [random_string](string.html) with the exception that the result is " +
@@ -26,13 +42,14 @@ func resourcePassword() *schema.Resource {
 			"\n" +
 			"This resource *does* use a cryptographic random number generator.",
 		CreateContext: createPassword,
-		ReadContext:   readNil,
+		ReadContext:   readResultEncodings,
+		UpdateContext: updateOnKeepersChange(createPassword),
 		DeleteContext: RemoveResourceFromState,
-		Schema:        passwordSchemaV2(),
+		Schema:        passwordSchemaV3(),
 		Importer: &schema.ResourceImporter{
 			StateContext: importPasswordFunc,
 		},
-		SchemaVersion: 2,
+		SchemaVersion: 3,
 		StateUpgraders: []schema.StateUpgrader{
 			{
 				Version: 0,
@@ -44,6 +61,11 @@ func resourcePassword() *schema.Resource {
 				Type:    resourcePasswordV1().CoreConfigSchema().ImpliedType(),
 				Upgrade: resourcePasswordStringStateUpgradeV1,
 			},
+			{
+				Version: 2,
+				Type:    resourcePasswordV2().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourcePasswordStateUpgradeV2,
+			},
 		},
 		CustomizeDiff: customdiff.All(
 			customizeDiffFuncs...,
@@ -52,12 +74,59 @@ func resourcePassword() *schema.Resource {
 }
 
 func createPassword(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	diags := createStringFunc(true)(ctx, d, meta)
+	quantity := d.Get("quantity").(int)
+	mutualDistance := d.Get("mutual_distance").(int)
+
+	if quantity > 0 {
+		if diags := validatePasswordSet(d, meta, quantity, mutualDistance); diags.HasError() {
+			return diags
+		}
+	}
+
+	if _, ok := d.GetOk("word_count"); ok && !d.Get("passphrase").(bool) {
+		return diag.Errorf("passphrase must be true when word_count is set")
+	}
+
+	if _, ok := d.GetOk("wordlist"); ok && !d.Get("passphrase").(bool) {
+		return diag.Errorf("passphrase must be true when wordlist is set")
+	}
+
+	for _, k := range []string{"argon2_memory", "argon2_iterations", "argon2_parallelism"} {
+		if _, ok := d.GetOk(k); ok && d.Get("hash_algorithm").(string) != hashAlgorithmArgon2id {
+			return diag.Errorf("hash_algorithm must be %s when %s is set", hashAlgorithmArgon2id, k)
+		}
+	}
+
+	var diags diag.Diagnostics
+	if d.Get("passphrase").(bool) {
+		diags = createPassphrase(ctx, d, meta)
+	} else {
+		diags = createStringFunc("random_password")(ctx, d, meta)
+	}
 	if diags.HasError() {
 		return diags
 	}
 
-	hash, err := generateHash(d.Get("result").(string))
+	if quantity > 0 {
+		results := []string{d.Get("result").(string)}
+
+		for len(results) < quantity {
+			result, diags := generateMutuallyDistinctString(d, meta, results, mutualDistance)
+			if diags.HasError() {
+				d.SetId("")
+				return diags
+			}
+
+			results = append(results, result)
+		}
+
+		if err := d.Set("results", results); err != nil {
+			d.SetId("")
+			return append(diags, diag.Errorf("err: %s", err)...)
+		}
+	}
+
+	hash, err := generateHash(d.Get("result").(string), bcryptCost(d))
 	if err != nil {
 		diags = append(diags, diag.Errorf("err: %s", err)...)
 		return diags
@@ -68,9 +137,290 @@ func createPassword(ctx context.Context, d *schema.ResourceData, meta interface{
 		return diags
 	}
 
+	if err := d.Set("sha256_hash", generateSHA256Hash(d.Get("result").(string))); err != nil {
+		diags = append(diags, diag.Errorf("err: %s", err)...)
+		return diags
+	}
+
+	passwordHash, err := generatePasswordHash(d, d.Get("result").(string), hash)
+	if err != nil {
+		diags = append(diags, diag.Errorf("err: %s", err)...)
+		return diags
+	}
+
+	if err := d.Set("password_hash", passwordHash); err != nil {
+		diags = append(diags, diag.Errorf("err: %s", err)...)
+		return diags
+	}
+
+	if passwordHashAlgorithm(d) == hashAlgorithmBcrypt {
+		diags = append(diags, warnBcryptTruncation(d.Get("result").(string))...)
+	}
+
+	cryptSHA512, err := generateSHA512Crypt(d.Get("result").(string))
+	if err != nil {
+		diags = append(diags, diag.Errorf("err: %s", err)...)
+		return diags
+	}
+
+	if err := d.Set("crypt_sha512", cryptSHA512); err != nil {
+		diags = append(diags, diag.Errorf("err: %s", err)...)
+		return diags
+	}
+
+	secretFile := renderSecretFile(d.Get("result").(string), d.Get("secret_name").(string), d.Get("trailing_newline").(bool))
+	if err := d.Set("secret_file", secretFile); err != nil {
+		diags = append(diags, diag.Errorf("err: %s", err)...)
+		return diags
+	}
+
+	if err := setGeneration(d); err != nil {
+		diags = append(diags, diag.Errorf("err: %s", err)...)
+		return diags
+	}
+
+	return diags
+}
+
+// createPassphrase handles the generation of a passphrase for random_password, setting the same attributes as
+// createStringFunc.
+func createPassphrase(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	result, diags := generatePassphrase(d)
+	if diags.HasError() {
+		return diags
+	}
+
+	if err := d.Set("result", string(result)); err != nil {
+		return append(diags, diag.Errorf("error setting result: %s", err)...)
+	}
+	if err := setResultEncodings(d, string(result)); err != nil {
+		return append(diags, diag.Errorf("error setting result encodings: %s", err)...)
+	}
+
+	if err := d.Set("number", d.Get("number").(bool)); err != nil {
+		return append(diags, diag.Errorf("error setting number: %s", err)...)
+	}
+	if err := d.Set("numeric", d.Get("numeric").(bool)); err != nil {
+		return append(diags, diag.Errorf("error setting numeric: %s", err)...)
+	}
+
+	d.SetId("none")
+
 	return nil
 }
 
+// generatePassphrase generates a passphrase that satisfies the configuration held in d.
+func generatePassphrase(d *schema.ResourceData) ([]byte, diag.Diagnostics) {
+	wordCount := d.Get("word_count").(int)
+
+	if _, ok := d.GetOk("length"); ok || wordCount < 1 {
+		return nil, diag.Errorf("word_count must be set and length must not be set when passphrase is true")
+	}
+
+	separator := passphraseSeparator(d)
+
+	wordlist, diags := passphraseWordlist(d)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	words, err := generateRandomWords(wordlist, wordCount)
+	if err != nil {
+		return nil, diag.Errorf("error generating random words: %s", err)
+	}
+
+	return []byte(strings.Join(words, separator)), nil
+}
+
+// passphraseSeparator returns the configured word_separator, defaulting to a single space.
+func passphraseSeparator(d *schema.ResourceData) string {
+	if v, ok := d.GetOk("word_separator"); ok {
+		return v.(string)
+	}
+
+	return " "
+}
+
+// passphraseWordlist returns the candidate words for a passphrase. When wordlist is configured in d, its non-blank
+// lines are returned after checking that there are at least 2 of them and that none contains the word separator.
+// Otherwise the built-in list is returned.
+func passphraseWordlist(d *schema.ResourceData) ([]string, diag.Diagnostics) {
+	v, ok := d.GetOk("wordlist")
+	if !ok {
+		return defaultWordlist(), nil
+	}
+
+	separator := passphraseSeparator(d)
+
+	var words []string
+	for _, line := range strings.Split(v.(string), "\n") {
+		word := strings.TrimSpace(line)
+		if word == "" {
+			continue
+		}
+
+		if strings.Contains(word, separator) {
+			return nil, diag.Errorf("wordlist entry %q contains the word_separator %q", word, separator)
+		}
+
+		words = append(words, word)
+	}
+
+	if len(words) < 2 {
+		return nil, diag.Errorf("wordlist must contain at least 2 words, got %d", len(words))
+	}
+
+	return words, nil
+}
+
+// deriveReader returns an HKDF-SHA256 reader keyed by the root_key of the derive block m. The environment and
+// purpose are NUL-separated in the info parameter so that, for example, "ab" and "c" cannot collide with "a" and "bc".
+func deriveReader(m map[string]interface{}) io.Reader {
+	info := strings.Join([]string{"random_password", m["environment"].(string), m["purpose"].(string)}, "\x00")
+
+	return &deriveStream{
+		prk:  hkdf.Extract(sha256.New, []byte(m["root_key"].(string)), nil),
+		info: []byte(info),
+	}
+}
+
+// deriveBlockSize is the number of bytes read from each HKDF expansion of a deriveStream, which is the most that
+// HKDF-SHA256 can produce.
+const deriveBlockSize = 255 * sha256.Size
+
+// deriveStream is an io.Reader of the concatenated HKDF-SHA256 expansions of prk for info followed by a big-endian
+// counter, starting at 0. A single expansion is capped at deriveBlockSize bytes, which rejection sampling of a long
+// result could exceed, so the stream moves on to the next counter rather than failing.
+type deriveStream struct {
+	prk     []byte
+	info    []byte
+	counter uint64
+	buf     []byte
+}
+
+func (s *deriveStream) Read(p []byte) (int, error) {
+	if len(s.buf) == 0 {
+		info := make([]byte, len(s.info)+8)
+		copy(info, s.info)
+		binary.BigEndian.PutUint64(info[len(s.info):], s.counter)
+
+		s.buf = make([]byte, deriveBlockSize)
+		if _, err := io.ReadFull(hkdf.Expand(sha256.New, s.prk, info), s.buf); err != nil {
+			s.buf = nil
+			return 0, err
+		}
+		s.counter++
+	}
+
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+
+	return n, nil
+}
+
+// generatePasswordResult generates either a passphrase or a random string, depending upon whether passphrase is
+// configured in d.
+func generatePasswordResult(d *schema.ResourceData, meta interface{}) ([]byte, diag.Diagnostics) {
+	if d.Get("passphrase").(bool) {
+		return generatePassphrase(d)
+	}
+
+	return generateStringResult(d, meta, stringRandReader(d, meta, "random_password"))
+}
+
+// validatePasswordSet returns an error diagnostic if it is not possible to generate quantity distinct passwords of
+// which no two share a common substring of length mutualDistance, given the configured character classes.
+func validatePasswordSet(d *schema.ResourceData, meta interface{}, quantity, mutualDistance int) diag.Diagnostics {
+	if d.Get("passphrase").(bool) {
+		words, diags := passphraseWordlist(d)
+		if diags.HasError() {
+			return diags
+		}
+
+		possible := 1
+		for i := 0; i < d.Get("word_count").(int) && possible < quantity; i++ {
+			possible *= len(words)
+		}
+
+		if possible < quantity {
+			return diag.Errorf("unable to generate %d distinct passphrases from %d words, consider increasing "+
+				"word_count", quantity, len(words))
+		}
+
+		return nil
+	}
+
+	length := d.Get("length").(int)
+	chars, _ := stringCharSets(d, meta)
+
+	distinct := make(map[byte]struct{})
+	for i := 0; i < len(chars); i++ {
+		distinct[chars[i]] = struct{}{}
+	}
+
+	// Every password must contain at least one substring of length mutualDistance (or be distinct, when
+	// mutualDistance exceeds length) that appears in no other password.
+	substringLength := length
+	if mutualDistance > 0 && mutualDistance < length {
+		substringLength = mutualDistance
+	}
+
+	possible := 1
+	for i := 0; i < substringLength && possible < quantity; i++ {
+		possible *= len(distinct)
+	}
+
+	if possible < quantity {
+		return diag.Errorf("unable to generate %d passwords that are mutually distinct with a mutual_distance of %d "+
+			"from %d distinct characters, consider increasing length or enabling additional character classes",
+			quantity, mutualDistance, len(distinct))
+	}
+
+	return nil
+}
+
+// generateMutuallyDistinctString generates a random string that is distinct from every string in existing and, when
+// distance is greater than zero, shares no common substring of length distance with any of them.
+func generateMutuallyDistinctString(d *schema.ResourceData, meta interface{}, existing []string, distance int) (string, diag.Diagnostics) {
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		result, diags := generatePasswordResult(d, meta)
+		if diags.HasError() {
+			return "", diags
+		}
+
+		if isMutuallyDistinct(string(result), existing, distance) {
+			return string(result), nil
+		}
+	}
+
+	return "", diag.Errorf("unable to generate a password that is mutually distinct from the other %d passwords "+
+		"after %d attempts, consider increasing length or enabling additional character classes",
+		len(existing), maxGenerateAttempts)
+}
+
+// isMutuallyDistinct returns true if candidate differs from every string in existing and, when distance is greater
+// than zero, shares no common substring of length distance with any of them.
+func isMutuallyDistinct(candidate string, existing []string, distance int) bool {
+	substrings := make(map[string]struct{})
+	for i := 0; distance > 0 && i+distance <= len(candidate); i++ {
+		substrings[candidate[i:i+distance]] = struct{}{}
+	}
+
+	for _, e := range existing {
+		if e == candidate {
+			return false
+		}
+
+		for i := 0; distance > 0 && i+distance <= len(e); i++ {
+			if _, ok := substrings[e[i:i+distance]]; ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 func importPasswordFunc(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	val := d.Id()
 	d.SetId("none")
@@ -79,18 +429,63 @@ func importPasswordFunc(ctx context.Context, d *schema.ResourceData, meta interf
 		return nil, fmt.Errorf("resource password import failed, error setting result: %w", err)
 	}
 
-	hash, err := generateHash(val)
+	if err := setResultEncodings(d, val); err != nil {
+		return nil, fmt.Errorf("resource password import failed, error setting result encodings: %w", err)
+	}
+
+	hash, err := generateHash(val, bcryptCost(d))
 	if err != nil {
 		return nil, fmt.Errorf("resource password import failed, generate hash error: %w", err)
 	}
 
+	// An importer cannot return warnings, so the warning is logged instead.
+	for _, w := range warnBcryptTruncation(val) {
+		log.Printf("[WARN] resource password import: %s: %s", w.Summary, w.Detail)
+	}
+
 	if err := d.Set("bcrypt_hash", hash); err != nil {
 		return nil, fmt.Errorf("resource password import failed, error setting bcrypt_hash: %w", err)
 	}
 
+	if err := d.Set("sha256_hash", generateSHA256Hash(val)); err != nil {
+		return nil, fmt.Errorf("resource password import failed, error setting sha256_hash: %w", err)
+	}
+
+	passwordHash, err := generatePasswordHash(d, val, hash)
+	if err != nil {
+		return nil, fmt.Errorf("resource password import failed, generate password_hash error: %w", err)
+	}
+
+	if err := d.Set("password_hash", passwordHash); err != nil {
+		return nil, fmt.Errorf("resource password import failed, error setting password_hash: %w", err)
+	}
+
+	cryptSHA512, err := generateSHA512Crypt(val)
+	if err != nil {
+		return nil, fmt.Errorf("resource password import failed, generate crypt_sha512 error: %w", err)
+	}
+
+	if err := d.Set("crypt_sha512", cryptSHA512); err != nil {
+		return nil, fmt.Errorf("resource password import failed, error setting crypt_sha512: %w", err)
+	}
+
+	if err := d.Set("secret_file", renderSecretFile(val, "", false)); err != nil {
+		return nil, fmt.Errorf("resource password import failed, error setting secret_file: %w", err)
+	}
+
+	if err := d.Set("generation", 1); err != nil {
+		return nil, fmt.Errorf("resource password import failed, error setting generation: %w", err)
+	}
+
 	return []*schema.ResourceData{d}, nil
 }
 
+func resourcePasswordV2() *schema.Resource {
+	return &schema.Resource{
+		Schema: passwordSchemaV2(),
+	}
+}
+
 func resourcePasswordV1() *schema.Resource {
 	return &schema.Resource{
 		Schema: passwordSchemaV1(),
@@ -113,7 +508,7 @@ func resourcePasswordStateUpgradeV0(_ context.Context, rawState map[string]inter
 		return nil, fmt.Errorf("resource password state upgrade failed, result is not a string: %T", rawState["result"])
 	}
 
-	hash, err := generateHash(result)
+	hash, err := generateHash(result, bcrypt.DefaultCost)
 	if err != nil {
 		return nil, fmt.Errorf("resource password state upgrade failed, generate hash error: %w", err)
 	}
@@ -123,8 +518,142 @@ func resourcePasswordStateUpgradeV0(_ context.Context, rawState map[string]inter
 	return rawState, nil
 }
 
-func generateHash(toHash string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(toHash), bcrypt.DefaultCost)
+func resourcePasswordStateUpgradeV2(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return nil, fmt.Errorf("resource password state upgrade failed, state is nil")
+	}
+
+	result, ok := rawState["result"].(string)
+	if !ok {
+		return nil, fmt.Errorf("resource password state upgrade failed, result is not a string: %T", rawState["result"])
+	}
+
+	cryptSHA512, err := generateSHA512Crypt(result)
+	if err != nil {
+		return nil, fmt.Errorf("resource password state upgrade failed, generate crypt_sha512 error: %w", err)
+	}
+
+	passwordHash, err := upgradedPasswordHash(rawState, result)
+	if err != nil {
+		return nil, fmt.Errorf("resource password state upgrade failed, generate password_hash error: %w", err)
+	}
+
+	rawState["sha256_hash"] = generateSHA256Hash(result)
+	rawState["crypt_sha512"] = cryptSHA512
+	rawState["secret_file"] = renderSecretFile(result, "", false)
+	rawState["password_hash"] = passwordHash
+	rawState["generation"] = 1
+
+	return rawState, nil
+}
+
+// upgradedPasswordHash returns the password_hash for rawState, a state that may have been created before
+// password_hash was added. Any password_hash already in rawState is kept. Otherwise hash_algorithm cannot have been
+// set, so the password_hash is the bcrypt_hash of rawState, or a bcrypt hash of result when it has none.
+func upgradedPasswordHash(rawState map[string]interface{}, result string) (string, error) {
+	if hash, ok := rawState["password_hash"].(string); ok && hash != "" {
+		return hash, nil
+	}
+
+	if hash, ok := rawState["bcrypt_hash"].(string); ok && hash != "" {
+		return hash, nil
+	}
+
+	return generateHash(result, bcrypt.DefaultCost)
+}
+
+func generateHash(toHash string, cost int) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(toHash), cost)
 
 	return string(hash), err
 }
+
+// bcryptMaxInputLength is the number of bytes of its input that bcrypt hashes. Any further bytes are ignored.
+const bcryptMaxInputLength = 72
+
+// warnBcryptTruncation returns a warning when toHash is longer than bcrypt hashes, as any password that shares its
+// first 72 bytes then matches the hash.
+func warnBcryptTruncation(toHash string) diag.Diagnostics {
+	if len(toHash) <= bcryptMaxInputLength {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "bcrypt hash only covers the first 72 bytes of the result",
+		Detail: fmt.Sprintf("The result is %d bytes long, but bcrypt ignores any input after the first %d bytes, "+
+			"so verifying a password against the bcrypt hash only checks its first %d bytes. Set hash_algorithm "+
+			"to %s and use password_hash instead.", len(toHash), bcryptMaxInputLength, bcryptMaxInputLength,
+			hashAlgorithmArgon2id),
+	}}
+}
+
+// passwordHashAlgorithm returns the configured hash_algorithm, or bcrypt when hash_algorithm has not been set.
+func passwordHashAlgorithm(d *schema.ResourceData) string {
+	if v, ok := d.GetOk("hash_algorithm"); ok {
+		return v.(string)
+	}
+
+	return hashAlgorithmBcrypt
+}
+
+// generatePasswordHash returns a hash of toHash using the hash_algorithm configured in d. For bcrypt, this is
+// bcryptHash, the bcrypt_hash already generated from toHash. For argon2id, the argon2_* arguments are used, falling
+// back to their defaults when they have not been set.
+func generatePasswordHash(d *schema.ResourceData, toHash, bcryptHash string) (string, error) {
+	if passwordHashAlgorithm(d) != hashAlgorithmArgon2id {
+		return bcryptHash, nil
+	}
+
+	params := argon2Params{
+		memory:      argon2DefaultMemory,
+		iterations:  argon2DefaultIterations,
+		parallelism: argon2DefaultParallelism,
+	}
+	if v, ok := d.GetOk("argon2_memory"); ok {
+		params.memory = uint32(v.(int))
+	}
+	if v, ok := d.GetOk("argon2_iterations"); ok {
+		params.iterations = uint32(v.(int))
+	}
+	if v, ok := d.GetOk("argon2_parallelism"); ok {
+		params.parallelism = uint8(v.(int))
+	}
+
+	return generateArgon2idHash(randReader, toHash, params)
+}
+
+// bcryptCost returns the configured bcrypt_cost, or bcrypt.DefaultCost when bcrypt_cost has not been set. Using
+// Default on the attribute is avoided as it would cause resources created prior to the addition of bcrypt_cost to
+// be replaced.
+func bcryptCost(d *schema.ResourceData) int {
+	if cost, ok := d.GetOk("bcrypt_cost"); ok {
+		return cost.(int)
+	}
+
+	return bcrypt.DefaultCost
+}
+
+func generateSHA256Hash(toHash string) string {
+	hash := sha256.Sum256([]byte(toHash))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// renderSecretFile renders result as the contents of a secret file, optionally preceded by a header line naming the
+// secret and optionally followed by a newline.
+func renderSecretFile(result, name string, trailingNewline bool) string {
+	var b strings.Builder
+
+	if name != "" {
+		b.WriteString("# " + name + "\n")
+	}
+
+	b.WriteString(result)
+
+	if trailingNewline {
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
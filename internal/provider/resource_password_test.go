@@ -1,13 +1,19 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"golang.org/x/crypto/bcrypt"
@@ -26,6 +32,7 @@ func TestAccResourcePasswordBasic(t *testing.T) {
 					testAccResourceStringCheck("random_password.basic", &customLens{
 						customLen: 12,
 					}),
+					testAccResourcePasswordSHA256Hash("random_password.basic"),
 				),
 			},
 			{
@@ -47,12 +54,34 @@ func TestAccResourcePasswordBasic(t *testing.T) {
 				},
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"bcrypt_hash", "length", "lower", "number", "numeric", "special", "upper", "min_lower", "min_numeric", "min_special", "min_upper", "override_special"},
+				ImportStateVerifyIgnore: []string{"bcrypt_hash", "crypt_sha512", "length", "lower", "number", "numeric", "special", "upper", "min_lower", "min_numeric", "min_special", "min_upper", "override_special"},
 			},
 		},
 	})
 }
 
+// TestAccResourcePasswordKeepersUpdate verifies that changing keepers with replace_on_keeper_change set to false
+// rotates the password and its hashes without replacing the resource, whose id remains "none".
+func TestAccResourcePasswordKeepersUpdate(t *testing.T) {
+	steps := testAccKeepersUpdateSteps("random_password.keepers", `resource "random_password" "keepers" {
+							length                   = 12
+							keepers                  = %s
+							replace_on_keeper_change = false
+						}`, "result", "bcrypt_hash", "sha256_hash")
+	for i := range steps {
+		steps[i].Check = resource.ComposeTestCheckFunc(
+			steps[i].Check,
+			resource.TestCheckResourceAttr("random_password.keepers", "id", "none"),
+		)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps:             steps,
+	})
+}
+
 func TestAccResourcePasswordOverride(t *testing.T) {
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -105,6 +134,334 @@ func TestAccResourcePasswordMin(t *testing.T) {
 	})
 }
 
+func TestAccResourcePasswordBcryptCost(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "random_password" "default_cost" {
+							length = 12
+						}`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourcePasswordBcryptCost("random_password.default_cost", bcrypt.DefaultCost),
+				),
+			},
+			{
+				Config: `resource "random_password" "cost" {
+							length = 12
+							bcrypt_cost = 12
+						}`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourcePasswordBcryptCost("random_password.cost", 12),
+				),
+			},
+			{
+				Config: `resource "random_password" "cost" {
+							length = 12
+							bcrypt_cost = 3
+						}`,
+				ExpectError: regexp.MustCompile(`.*expected bcrypt_cost to be in the range \(4 - 31\), got 3`),
+			},
+		},
+	})
+}
+
+func TestAccResourcePasswordHashAlgorithm(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "random_password" "default" {
+							length = 12
+						}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("random_password.default", "password_hash", "random_password.default", "bcrypt_hash"),
+				),
+			},
+			{
+				Config: `resource "random_password" "argon2id" {
+							length             = 12
+							hash_algorithm     = "argon2id"
+							argon2_memory      = 1024
+							argon2_iterations  = 2
+							argon2_parallelism = 1
+						}`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourcePasswordBcryptCost("random_password.argon2id", bcrypt.DefaultCost),
+					resource.TestCheckResourceAttrWith("random_password.argon2id", "password_hash", func(value string) error {
+						if !strings.HasPrefix(value, "$argon2id$v=19$m=1024,t=2,p=1$") {
+							return fmt.Errorf("password_hash %q does not use the configured parameters", value)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: `resource "random_password" "argon2id" {
+							length        = 12
+							argon2_memory = 1024
+						}`,
+				ExpectError: regexp.MustCompile(`.*hash_algorithm must be argon2id when argon2_memory is set`),
+			},
+		},
+	})
+}
+
+func TestAccResourcePasswordMutualDistance(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "random_password" "set" {
+							length = 12
+							quantity = 5
+							mutual_distance = 3
+						}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("random_password.set", "results.#", "5"),
+					resource.TestCheckResourceAttrPair("random_password.set", "result", "random_password.set", "results.0"),
+					testAccResourcePasswordMutualDistance("random_password.set", 5, 3),
+				),
+			},
+			{
+				Config: `resource "random_password" "set" {
+							length = 4
+							quantity = 11
+							mutual_distance = 1
+							upper = false
+							lower = false
+							special = false
+						}`,
+				ExpectError: regexp.MustCompile(`.*unable to generate 11 passwords that are mutually distinct with a mutual_distance of 1`),
+			},
+		},
+	})
+}
+
+func TestAccResourcePasswordPassphrase(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "random_password" "passphrase" {
+							passphrase = true
+							word_count = 4
+							word_separator = "-"
+						}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_password.passphrase", "result", regexp.MustCompile(`^[a-z]+-[a-z]+-[a-z]+-[a-z]+$`)),
+					testAccResourcePasswordBcryptCost("random_password.passphrase", bcrypt.DefaultCost),
+				),
+			},
+			{
+				Config: `resource "random_password" "passphrase" {
+							passphrase = true
+							word_count = 4
+							length = 12
+						}`,
+				ExpectError: regexp.MustCompile(`.*only one of .length,word_count. can be specified`),
+			},
+			{
+				Config: `resource "random_password" "passphrase" {
+							passphrase = false
+							word_count = 4
+						}`,
+				ExpectError: regexp.MustCompile(`.*passphrase must be true when word_count is set`),
+			},
+		},
+	})
+}
+
+func TestAccResourcePasswordPassphraseWordlist(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "random_password" "passphrase" {
+							passphrase = true
+							word_count = 6
+							word_separator = "."
+							wordlist = <<-EOT
+							alpha
+							bravo
+							EOT
+						}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_password.passphrase", "result", regexp.MustCompile(`^(alpha|bravo)(\.(alpha|bravo)){5}$`)),
+				),
+			},
+			{
+				Config: `resource "random_password" "passphrase" {
+							passphrase = true
+							word_count = 4
+							wordlist = "alpha"
+						}`,
+				ExpectError: regexp.MustCompile(`.*wordlist must contain at least 2 words`),
+			},
+			{
+				Config: `resource "random_password" "passphrase" {
+							passphrase = true
+							word_count = 4
+							word_separator = "-"
+							wordlist = "alpha\nbravo-charlie"
+						}`,
+				ExpectError: regexp.MustCompile(`.*wordlist entry "bravo-charlie" contains the word_separator "-"`),
+			},
+		},
+	})
+}
+
+func TestAccResourcePasswordSecretFile(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "random_password" "secret" {
+							length = 12
+						}`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourcePasswordSecretFile("random_password.secret", "", false),
+				),
+			},
+			{
+				Config: `resource "random_password" "secret" {
+							length = 12
+							trailing_newline = true
+						}`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourcePasswordSecretFile("random_password.secret", "", true),
+				),
+			},
+			{
+				Config: `resource "random_password" "secret" {
+							length = 12
+							secret_name = "db_password"
+						}`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourcePasswordSecretFile("random_password.secret", "# db_password\n", false),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePasswordCryptSHA512(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "random_password" "shadow" {
+							length = 16
+						}`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourcePasswordCryptSHA512("random_password.shadow"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePasswordDerive(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "random_password" "db_a" {
+							length = 24
+							derive {
+								root_key    = "correct horse battery staple"
+								environment = "production"
+								purpose     = "database"
+							}
+						}
+
+						resource "random_password" "db_b" {
+							length = 24
+							derive {
+								root_key    = "correct horse battery staple"
+								environment = "production"
+								purpose     = "database"
+							}
+						}
+
+						resource "random_password" "cache" {
+							length = 24
+							derive {
+								root_key    = "correct horse battery staple"
+								environment = "production"
+								purpose     = "cache"
+							}
+						}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("random_password.db_a", "result", "random_password.db_b", "result"),
+					testAccResourcePasswordResultsDiffer("random_password.db_a", "random_password.cache"),
+				),
+			},
+			{
+				Config: `resource "random_password" "derived" {
+							length = 24
+							quantity = 2
+							derive {
+								root_key    = "correct horse battery staple"
+								environment = "production"
+								purpose     = "database"
+							}
+						}`,
+				ExpectError: regexp.MustCompile(`.*"derive": conflicts with quantity`),
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_NumberNumericCombinations(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "random_password" "both" {
+							length  = 12
+							number  = false
+							numeric = false
+						}`,
+				ExpectError: regexp.MustCompile(`"number": conflicts with numeric`),
+			},
+			{
+				Config: `resource "random_password" "number" {
+							length = 12
+							number = false
+						}
+
+						resource "random_password" "numeric" {
+							length  = 12
+							numeric = false
+						}
+
+						resource "random_password" "neither" {
+							length = 12
+						}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("random_password.number", "number", "false"),
+					resource.TestCheckResourceAttr("random_password.number", "numeric", "false"),
+					resource.TestCheckResourceAttr("random_password.numeric", "number", "false"),
+					resource.TestCheckResourceAttr("random_password.numeric", "numeric", "false"),
+					resource.TestCheckResourceAttr("random_password.neither", "number", "true"),
+					resource.TestCheckResourceAttr("random_password.neither", "numeric", "true"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccResourcePassword_UpdateNumberAndNumeric(t *testing.T) {
 	t.Parallel()
 	resource.UnitTest(t, resource.TestCase{
@@ -163,8 +520,8 @@ func TestAccResourcePassword_UpdateNumberAndNumeric(t *testing.T) {
 	})
 }
 
-// TestAccResourcePassword_StateUpgraders covers the state upgrades from v0 to V2 and V1 to V2.
-// This includes the addition of bcrypt_hash and numeric attributes.
+// TestAccResourcePassword_StateUpgraders covers the state upgrades from v0 to V3, V1 to V3 and V2 to V3.
+// This includes the addition of bcrypt_hash, numeric and sha256_hash attributes.
 func TestAccResourcePassword_StateUpgraders(t *testing.T) {
 	t.Parallel()
 
@@ -331,6 +688,29 @@ func TestAccResourcePassword_StateUpgraders(t *testing.T) {
 		},
 	}
 
+	v2Cases := []struct {
+		name                string
+		configBeforeUpgrade string
+		configDuringUpgrade string
+		beforeStateUpgrade  []resource.TestCheckFunc
+		afterStateUpgrade   []resource.TestCheckFunc
+	}{
+		{
+			name: "%s sha256_hash",
+			configBeforeUpgrade: `resource "random_password" "default" {
+						length = 12
+					}`,
+			beforeStateUpgrade: []resource.TestCheckFunc{
+				resource.TestCheckNoResourceAttr("random_password.default", "sha256_hash"),
+			},
+			afterStateUpgrade: []resource.TestCheckFunc{
+				testAccResourcePasswordSHA256Hash("random_password.default"),
+			},
+		},
+	}
+
+	v1Cases = append(v1Cases, v2Cases...)
+
 	v0Cases := v1Cases
 	v0Cases = append(v0Cases, struct {
 		name                string
@@ -360,6 +740,7 @@ func TestAccResourcePassword_StateUpgraders(t *testing.T) {
 	}{
 		"3.1.3": v0Cases,
 		"3.2.0": v1Cases,
+		"3.3.1": v2Cases,
 	}
 
 	for providerVersion, v := range cases {
@@ -392,6 +773,134 @@ func TestAccResourcePassword_StateUpgraders(t *testing.T) {
 	}
 }
 
+// testAccResourcePasswordBcryptCost checks that bcrypt_hash was generated with the expected cost and matches result.
+func testAccResourcePasswordBcryptCost(id string, cost int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("not found: %s", id)
+		}
+
+		hash := []byte(rs.Primary.Attributes["bcrypt_hash"])
+
+		actualCost, err := bcrypt.Cost(hash)
+		if err != nil {
+			return fmt.Errorf("error reading bcrypt_hash cost: %w", err)
+		}
+
+		if actualCost != cost {
+			return fmt.Errorf("bcrypt_hash cost is %d; want %d", actualCost, cost)
+		}
+
+		if err := bcrypt.CompareHashAndPassword(hash, []byte(rs.Primary.Attributes["result"])); err != nil {
+			return fmt.Errorf("bcrypt_hash does not match result: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// testAccResourcePasswordMutualDistance checks that no two of the quantity results are equal or share a common
+// substring of length distance.
+func testAccResourcePasswordMutualDistance(id string, quantity, distance int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("not found: %s", id)
+		}
+
+		var results []string
+		for i := 0; i < quantity; i++ {
+			result := rs.Primary.Attributes[fmt.Sprintf("results.%d", i)]
+
+			if !isMutuallyDistinct(result, results, distance) {
+				return fmt.Errorf("results.%d (%s) is not mutually distinct from %v", i, result, results)
+			}
+
+			results = append(results, result)
+		}
+
+		return nil
+	}
+}
+
+// testAccResourcePasswordSHA256Hash checks that sha256_hash is the hex-encoded SHA-256 hash of result.
+func testAccResourcePasswordSHA256Hash(id string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("not found: %s", id)
+		}
+
+		hash := sha256.Sum256([]byte(rs.Primary.Attributes["result"]))
+
+		if got, want := rs.Primary.Attributes["sha256_hash"], hex.EncodeToString(hash[:]); got != want {
+			return fmt.Errorf("sha256_hash is %s; want %s", got, want)
+		}
+
+		return nil
+	}
+}
+
+func TestIsMutuallyDistinct(t *testing.T) {
+	cases := []struct {
+		name      string
+		candidate string
+		existing  []string
+		distance  int
+		expected  bool
+	}{
+		{
+			name:      "no existing",
+			candidate: "abcd",
+			expected:  true,
+		},
+		{
+			name:      "duplicate without distance",
+			candidate: "abcd",
+			existing:  []string{"wxyz", "abcd"},
+			expected:  false,
+		},
+		{
+			name:      "distinct without distance",
+			candidate: "abcd",
+			existing:  []string{"abce", "bcda"},
+			expected:  true,
+		},
+		{
+			name:      "common substring of distance",
+			candidate: "abcd",
+			existing:  []string{"xbcx"},
+			distance:  2,
+			expected:  false,
+		},
+		{
+			name:      "common substring shorter than distance",
+			candidate: "abcd",
+			existing:  []string{"xbcx"},
+			distance:  3,
+			expected:  true,
+		},
+		{
+			name:      "distance exceeds length",
+			candidate: "abcd",
+			existing:  []string{"abcd"},
+			distance:  5,
+			expected:  false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := isMutuallyDistinct(c.candidate, c.existing, c.distance)
+
+			if actual != c.expected {
+				t.Errorf("expected: %t, got: %t", c.expected, actual)
+			}
+		})
+	}
+}
+
 func TestResourcePasswordStateUpgradeV0(t *testing.T) {
 	cases := []struct {
 		name            string
@@ -446,3 +955,302 @@ func TestResourcePasswordStateUpgradeV0(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderSecretFile(t *testing.T) {
+	cases := []struct {
+		name            string
+		secretName      string
+		trailingNewline bool
+		expected        string
+	}{
+		{
+			name:     "defaults",
+			expected: "abc123",
+		},
+		{
+			name:            "trailing newline",
+			trailingNewline: true,
+			expected:        "abc123\n",
+		},
+		{
+			name:       "name header",
+			secretName: "db_password",
+			expected:   "# db_password\nabc123",
+		},
+		{
+			name:            "name header and trailing newline",
+			secretName:      "db_password",
+			trailingNewline: true,
+			expected:        "# db_password\nabc123\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := renderSecretFile("abc123", c.secretName, c.trailingNewline)
+			if !cmp.Equal(actual, c.expected) {
+				t.Errorf("expected: %q, got: %q", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGenerateStringResultDerived(t *testing.T) {
+	derive := func(purpose string) string {
+		d := resourcePassword().TestResourceData()
+		for k, v := range map[string]interface{}{
+			"length":      32,
+			"upper":       true,
+			"lower":       true,
+			"numeric":     true,
+			"special":     true,
+			"min_special": 4,
+			"derive": []interface{}{map[string]interface{}{
+				"root_key":    "correct horse battery staple",
+				"environment": "production",
+				"purpose":     purpose,
+			}},
+		} {
+			if err := d.Set(k, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		result, diags := generateStringResult(d, nil, stringRandReader(d, nil, "random_password"))
+		if diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+
+		return string(result)
+	}
+
+	first := derive("database")
+	for i := 0; i < 10; i++ {
+		if next := derive("database"); next != first {
+			t.Fatalf("expected derived results to match, got %q and %q", first, next)
+		}
+	}
+
+	if other := derive("cache"); other == first {
+		t.Errorf("expected different purposes to derive different results, both got %q", first)
+	}
+
+	if n := len(regexp.MustCompile(`[!@#$%&*()\-_=+\[\]{}<>:?]`).FindAllString(first, -1)); n < 4 {
+		t.Errorf("expected derived result %q to contain at least 4 special characters, got %d", first, n)
+	}
+}
+
+func TestDeriveReaderUncapped(t *testing.T) {
+	m := map[string]interface{}{
+		"root_key":    "correct horse battery staple",
+		"environment": "production",
+		"purpose":     "database",
+	}
+
+	read := func() []byte {
+		b := make([]byte, 3*deriveBlockSize+1)
+		if _, err := io.ReadFull(deriveReader(m), b); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return b
+	}
+
+	first := read()
+	if !bytes.Equal(first, read()) {
+		t.Fatal("expected derived streams to match")
+	}
+
+	if bytes.Equal(first[:deriveBlockSize], first[deriveBlockSize:2*deriveBlockSize]) {
+		t.Error("expected each block of the derived stream to differ")
+	}
+}
+
+func TestCreatePasswordBcryptTruncationWarning(t *testing.T) {
+	for _, c := range []struct {
+		length   int
+		expected bool
+	}{
+		{length: 72},
+		{length: 100, expected: true},
+	} {
+		t.Run(fmt.Sprintf("length %d", c.length), func(t *testing.T) {
+			d := resourcePassword().TestResourceData()
+			for k, v := range map[string]interface{}{
+				"length":  c.length,
+				"upper":   true,
+				"lower":   true,
+				"numeric": true,
+				"special": true,
+			} {
+				if err := d.Set(k, v); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			diags := createPassword(context.Background(), d, nil)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			warned := false
+			for _, w := range diags {
+				if w.Severity == diag.Warning && w.Summary == "bcrypt hash only covers the first 72 bytes of the result" {
+					warned = true
+				}
+			}
+			if warned != c.expected {
+				t.Errorf("expected bcrypt truncation warning: %t, got: %v", c.expected, diags)
+			}
+		})
+	}
+}
+
+func TestResourcePasswordStateUpgradeV2(t *testing.T) {
+	cases := []struct {
+		name            string
+		stateV2         map[string]interface{}
+		err             error
+		expectedStateV3 map[string]interface{}
+	}{
+		{
+			name:    "raw state is nil",
+			stateV2: nil,
+			err:     errors.New("resource password state upgrade failed, state is nil"),
+		},
+		{
+			name:    "result is not string",
+			stateV2: map[string]interface{}{"result": 0},
+			err:     errors.New("resource password state upgrade failed, result is not a string: int"),
+		},
+		{
+			name:    "success",
+			stateV2: map[string]interface{}{"result": "abc123"},
+			expectedStateV3: map[string]interface{}{
+				"result":        "abc123",
+				"sha256_hash":   "6ca13d52ca70c883e0f0bb101e425a89e8624de51db2d2392593af6a84118090",
+				"crypt_sha512":  "$6$salt$hash",
+				"secret_file":   "abc123",
+				"password_hash": "$2a$10$hash",
+				"generation":    1,
+			},
+		},
+		{
+			name:    "bcrypt_hash is kept as password_hash",
+			stateV2: map[string]interface{}{"result": "abc123", "bcrypt_hash": "$2a$10$existing"},
+			expectedStateV3: map[string]interface{}{
+				"result":        "abc123",
+				"bcrypt_hash":   "$2a$10$existing",
+				"sha256_hash":   "6ca13d52ca70c883e0f0bb101e425a89e8624de51db2d2392593af6a84118090",
+				"crypt_sha512":  "$6$salt$hash",
+				"secret_file":   "abc123",
+				"password_hash": "$2a$10$existing",
+				"generation":    1,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actualStateV3, err := resourcePasswordStateUpgradeV2(context.Background(), c.stateV2, nil)
+
+			if c.err != nil {
+				if !cmp.Equal(c.err.Error(), err.Error()) {
+					t.Errorf("expected: %q, got: %q", c.err.Error(), err)
+				}
+				if !cmp.Equal(c.expectedStateV3, actualStateV3) {
+					t.Errorf("expected: %+v, got: %+v", c.expectedStateV3, actualStateV3)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("err should be nil, actual: %v", err)
+				}
+
+				// Verify crypt_sha512 against the plaintext as its salt is random
+				if err := verifySHA512Crypt(c.stateV2["result"].(string), actualStateV3["crypt_sha512"].(string)); err != nil {
+					t.Error(err)
+				}
+
+				// Verify a generated password_hash against the plaintext as its salt is random
+				passwordHash, _ := actualStateV3["password_hash"].(string)
+				if passwordHash == "" {
+					t.Fatal("expected password_hash to be set")
+				}
+				if _, ok := c.stateV2["bcrypt_hash"]; !ok {
+					if err := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(c.stateV2["result"].(string))); err != nil {
+						t.Error(err)
+					}
+					delete(actualStateV3, "password_hash")
+					delete(c.expectedStateV3, "password_hash")
+				}
+
+				// Delete crypt_sha512 from actualStateV3 and expectedStateV3 so can compare
+				delete(actualStateV3, "crypt_sha512")
+				delete(c.expectedStateV3, "crypt_sha512")
+				if !cmp.Equal(actualStateV3, c.expectedStateV3) {
+					t.Errorf("expected: %v, got: %v", c.expectedStateV3, actualStateV3)
+				}
+			}
+		})
+	}
+}
+
+// testAccResourcePasswordCryptSHA512 verifies that the crypt_sha512 of the
+// named resource is a SHA-512 crypt string of its result.
+func testAccResourcePasswordCryptSHA512(id string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("Not found: %s", id)
+		}
+
+		crypted := rs.Primary.Attributes["crypt_sha512"]
+		if !strings.HasPrefix(crypted, "$6$") {
+			return fmt.Errorf("crypt_sha512 %q is not a SHA-512 crypt string", crypted)
+		}
+
+		return verifySHA512Crypt(rs.Primary.Attributes["result"], crypted)
+	}
+}
+
+// testAccResourcePasswordResultsDiffer verifies that the results of the two
+// named resources differ.
+func testAccResourcePasswordResultsDiffer(a, b string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		var results []string
+		for _, id := range []string{a, b} {
+			rs, ok := s.RootModule().Resources[id]
+			if !ok {
+				return fmt.Errorf("Not found: %s", id)
+			}
+			results = append(results, rs.Primary.Attributes["result"])
+		}
+
+		if results[0] == results[1] {
+			return fmt.Errorf("expected %s and %s to have different results", a, b)
+		}
+
+		return nil
+	}
+}
+
+func testAccResourcePasswordSecretFile(id, header string, trailingNewline bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("Not found: %s", id)
+		}
+
+		secretFile := rs.Primary.Attributes["secret_file"]
+		result := rs.Primary.Attributes["result"]
+
+		if !strings.HasPrefix(secretFile, header+result) {
+			return fmt.Errorf("secret_file %q does not start with %q followed by the result", secretFile, header)
+		}
+
+		if got := strings.HasSuffix(secretFile, "\n"); got != trailingNewline {
+			return fmt.Errorf("secret_file trailing newline is %t; want %t", got, trailingNewline)
+		}
+
+		return nil
+	}
+}
@@ -26,44 +26,73 @@ func resourcePet() *schema.Resource {
 		CreateContext: CreatePet,
 		ReadContext:   schema.NoopContext,
 		DeleteContext: RemoveResourceFromState,
+		CustomizeDiff: logKeepersChange,
 
-		Schema: map[string]*schema.Schema{
-			"keepers": {
-				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
-					"resource. See [the main provider documentation](../index.html) for more information.",
-				Type:     schema.TypeMap,
-				Optional: true,
-				ForceNew: true,
+		Schema:        petSchemaV1(),
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourcePetV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: generationStateUpgrade,
 			},
+		},
+	}
+}
 
-			"length": {
-				Description: "The length (in words) of the pet name. Defaults to 2",
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Default:     2,
-				ForceNew:    true,
-			},
+func resourcePetV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: petSchemaV0(),
+	}
+}
 
-			"prefix": {
-				Description: "A string to prefix the name with.",
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-			},
+// petSchemaV1 uses petSchemaV0 to obtain the V0 version of the Schema key-value entries but requires that the
+// generation entry be configured.
+func petSchemaV1() map[string]*schema.Schema {
+	petSchema := petSchemaV0()
+	petSchema["generation"] = generationSchema(false)
 
-			"separator": {
-				Description: "The character to separate words in the pet name. Defaults to \"-\"",
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "-",
-				ForceNew:    true,
-			},
+	return petSchema
+}
 
-			"id": {
-				Description: "The random pet name",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
+// petSchemaV0 returns the Schema key-value entries of random_pet before the generation entry was added.
+func petSchemaV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"keepers": {
+			Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+				"resource. See [the main provider documentation](../index.html) for more information.",
+			Type:     schema.TypeMap,
+			Optional: true,
+			ForceNew: true,
+		},
+
+		"length": {
+			Description: "The length (in words) of the pet name. Defaults to 2",
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     2,
+			ForceNew:    true,
+		},
+
+		"prefix": {
+			Description: "A string to prefix the name with.",
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+		},
+
+		"separator": {
+			Description: "The character to separate words in the pet name. Defaults to \"-\"",
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "-",
+			ForceNew:    true,
+		},
+
+		"id": {
+			Description: "The random pet name",
+			Type:        schema.TypeString,
+			Computed:    true,
 		},
 	}
 }
@@ -81,5 +110,9 @@ func CreatePet(_ context.Context, d *schema.ResourceData, meta interface{}) diag
 
 	d.SetId(pet)
 
+	if err := setGeneration(d); err != nil {
+		return diag.Errorf("error setting generation: %s", err)
+	}
+
 	return nil
 }
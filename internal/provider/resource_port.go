@@ -0,0 +1,255 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	// dynamicPortMin and dynamicPortMax bound the dynamic, or ephemeral, port range defined by RFC 6335.
+	dynamicPortMin = 49152
+	dynamicPortMax = 65535
+)
+
+func resourcePort() *schema.Resource {
+	return &schema.Resource{
+		Description: "The resource `random_port` generates a random TCP or UDP port number between `min` and " +
+			"`max`, which default to the dynamic port range of 49152 to 65535, avoiding any ranges given in " +
+			"`exclude_ranges`.\n" +
+			"\n" +
+			"This resource *does* use a cryptographic random number generator.",
+		CreateContext: CreatePort,
+		ReadContext:   schema.NoopContext,
+		DeleteContext: RemoveResourceFromState,
+		CustomizeDiff: logKeepersChange,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportPort,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"keepers": {
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+					"resource. See [the main provider documentation](../index.html) for more information.",
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"min": {
+				Description:      "The minimum inclusive port number. Default value is `49152`.",
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          dynamicPortMin,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsPortNumber),
+			},
+
+			"max": {
+				Description:      "The maximum inclusive port number. Default value is `65535`.",
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          dynamicPortMax,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsPortNumber),
+			},
+
+			"exclude_ranges": {
+				Description: "Ranges of port numbers that the result must not fall within, e.g., ports reserved " +
+					"for other services. May be specified more than once. At least one port between `min` and " +
+					"`max` must not be excluded.",
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"from": {
+							Description:      "The first port number of the range.",
+							Type:             schema.TypeInt,
+							Required:         true,
+							ForceNew:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IsPortNumber),
+						},
+
+						"to": {
+							Description: "The last port number of the range, which must not be less than " +
+								"`from`.",
+							Type:             schema.TypeInt,
+							Required:         true,
+							ForceNew:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IsPortNumber),
+						},
+					},
+				},
+			},
+
+			"result": {
+				Description: "The random port number.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+
+			"generation": generationSchema(false),
+
+			"id": {
+				Description: "The string representation of the port number.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// portRange is an inclusive range of port numbers.
+type portRange struct {
+	from int
+	to   int
+}
+
+func CreatePort(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	min := d.Get("min").(int)
+	max := d.Get("max").(int)
+
+	if max < min {
+		return diag.Errorf("min (%d) must be less than or equal to max (%d)", min, max)
+	}
+
+	var excluded []portRange
+	for _, v := range d.Get("exclude_ranges").([]interface{}) {
+		m := v.(map[string]interface{})
+		r := portRange{from: m["from"].(int), to: m["to"].(int)}
+
+		if r.to < r.from {
+			return diag.Errorf("exclude_ranges to (%d) must be greater than or equal to from (%d)", r.to, r.from)
+		}
+
+		excluded = append(excluded, r)
+	}
+
+	allowed := allowedPortRanges(min, max, excluded)
+
+	count := 0
+	for _, r := range allowed {
+		count += r.to - r.from + 1
+	}
+	if count == 0 {
+		return diag.Errorf("no port between %d and %d remains once exclude_ranges are applied", min, max)
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(count)))
+	if err != nil {
+		return diag.Errorf("error generating random port: %s", err)
+	}
+
+	port, err := nthPort(allowed, int(n.Int64()))
+	if err != nil {
+		return diag.Errorf("error choosing port: %s", err)
+	}
+
+	if err := d.Set("result", port); err != nil {
+		return diag.Errorf("error setting result: %s", err)
+	}
+
+	if err := setGeneration(d); err != nil {
+		return diag.Errorf("error setting generation: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(port))
+
+	return nil
+}
+
+// allowedPortRanges returns the sorted, non-overlapping ranges of ports between min and max that are not within any
+// of excluded.
+func allowedPortRanges(min, max int, excluded []portRange) []portRange {
+	sorted := make([]portRange, len(excluded))
+	copy(sorted, excluded)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].from < sorted[j].from
+	})
+
+	var allowed []portRange
+	next := min
+	for _, r := range sorted {
+		if r.to < next {
+			continue
+		}
+		if r.from > max {
+			break
+		}
+		if r.from > next {
+			allowed = append(allowed, portRange{from: next, to: r.from - 1})
+		}
+		next = r.to + 1
+	}
+	if next <= max {
+		allowed = append(allowed, portRange{from: next, to: max})
+	}
+
+	return allowed
+}
+
+// nthPort returns the port n places from the start of allowed, counting from 0. Choosing a uniformly random n, rather
+// than rejecting random ports that are excluded, takes the same time however few ports are allowed. An error is
+// returned when n is negative or allowed holds n or fewer ports.
+func nthPort(allowed []portRange, n int) (int, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("port index %d must not be negative", n)
+	}
+
+	i := n
+	for _, r := range allowed {
+		if size := r.to - r.from + 1; i >= size {
+			i -= size
+			continue
+		}
+
+		return r.from + i, nil
+	}
+
+	return 0, fmt.Errorf("port index %d out of range, only %d ports are allowed", n, n-i)
+}
+
+func ImportPort(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), ",")
+	if len(parts) != 1 && len(parts) != 3 {
+		return nil, fmt.Errorf("Invalid import usage: expecting {result} or {result},{min},{max}")
+	}
+
+	values := []int{0, dynamicPortMin, dynamicPortMax}
+	for i, name := range []string{"result", "min", "max"}[:len(parts)] {
+		v, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", name, err)
+		}
+
+		values[i] = v
+	}
+
+	result, min, max := values[0], values[1], values[2]
+	if result < min || result > max {
+		return nil, fmt.Errorf("result (%d) must be between min (%d) and max (%d)", result, min, max)
+	}
+
+	for i, name := range []string{"result", "min", "max"} {
+		if err := d.Set(name, values[i]); err != nil {
+			return nil, fmt.Errorf("error setting %s: %w", name, err)
+		}
+	}
+
+	if err := d.Set("generation", 1); err != nil {
+		return nil, fmt.Errorf("error setting generation: %w", err)
+	}
+
+	d.SetId(strconv.Itoa(result))
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccResourcePort(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourcePortConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckPortBetween("random_port.default", dynamicPortMin, dynamicPortMax),
+					resource.TestCheckResourceAttr("random_port.default", "min", "49152"),
+					resource.TestCheckResourceAttr("random_port.default", "max", "65535"),
+					resource.TestCheckResourceAttrPair("random_port.default", "id", "random_port.default", "result"),
+					// Only 8003 and 8007 are not excluded.
+					resource.TestMatchResourceAttr("random_port.excluded", "result", regexp.MustCompile(`^800[37]$`)),
+				),
+			},
+			{
+				ResourceName:      "random_port.default",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:            "random_port.excluded",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccResourcePortImportID("random_port.excluded", "8000", "8010"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"exclude_ranges"},
+			},
+		},
+	})
+}
+
+func TestAccResourcePortErrors(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourcePortConfigAllExcluded,
+				ExpectError: regexp.MustCompile(`no port between 8000 and 8010 remains once exclude_ranges are applied`),
+			},
+			{
+				Config:      testAccResourcePortConfigInvalidRange,
+				ExpectError: regexp.MustCompile(`exclude_ranges to \(8000\) must be greater than or equal to from \(8005\)`),
+			},
+			{
+				Config:      testAccResourcePortConfigMinGreaterThanMax,
+				ExpectError: regexp.MustCompile(`min \(9000\) must be less than or equal to max \(8000\)`),
+			},
+		},
+	})
+}
+
+func TestAllowedPortRanges(t *testing.T) {
+	cases := []struct {
+		name     string
+		min, max int
+		excluded []portRange
+		expected []portRange
+	}{
+		{
+			name:     "no exclusions",
+			min:      10,
+			max:      20,
+			expected: []portRange{{10, 20}},
+		},
+		{
+			name:     "unsorted and overlapping",
+			min:      10,
+			max:      20,
+			excluded: []portRange{{15, 16}, {12, 13}, {13, 14}},
+			expected: []portRange{{10, 11}, {17, 20}},
+		},
+		{
+			name:     "outside min and max",
+			min:      10,
+			max:      20,
+			excluded: []portRange{{1, 10}, {20, 30}, {40, 50}},
+			expected: []portRange{{11, 19}},
+		},
+		{
+			name:     "everything excluded",
+			min:      10,
+			max:      20,
+			excluded: []portRange{{10, 14}, {15, 20}},
+			expected: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := allowedPortRanges(c.min, c.max, c.excluded); !reflect.DeepEqual(got, c.expected) {
+				t.Errorf("got %v; want %v", got, c.expected)
+			}
+		})
+	}
+}
+
+func TestNthPort(t *testing.T) {
+	allowed := []portRange{{10, 11}, {17, 20}}
+
+	var got []int
+	for n := 0; n < 6; n++ {
+		port, err := nthPort(allowed, n)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		got = append(got, port)
+	}
+
+	if expected := []int{10, 11, 17, 18, 19, 20}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v; want %v", got, expected)
+	}
+
+	for _, n := range []int{-1, 6} {
+		if _, err := nthPort(allowed, n); err == nil {
+			t.Errorf("expected an error for port index %d", n)
+		}
+	}
+}
+
+func testCheckPortBetween(name string, min, max int) resource.TestCheckFunc {
+	return resource.TestCheckResourceAttrWith(name, "result", func(value string) error {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		if port < min || port > max {
+			return fmt.Errorf("expected a port between %d and %d, got %d", min, max, port)
+		}
+		return nil
+	})
+}
+
+func testAccResourcePortImportID(name, min, max string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return "", fmt.Errorf("not found: %s", name)
+		}
+
+		return rs.Primary.Attributes["result"] + "," + min + "," + max, nil
+	}
+}
+
+const (
+	testAccResourcePortConfig = `
+resource "random_port" "default" {
+}
+
+resource "random_port" "excluded" {
+  min = 8000
+  max = 8010
+
+  exclude_ranges {
+    from = 8000
+    to   = 8002
+  }
+
+  exclude_ranges {
+    from = 8004
+    to   = 8006
+  }
+
+  exclude_ranges {
+    from = 8008
+    to   = 9000
+  }
+}
+`
+
+	testAccResourcePortConfigAllExcluded = `
+resource "random_port" "excluded" {
+  min = 8000
+  max = 8010
+
+  exclude_ranges {
+    from = 7000
+    to   = 8005
+  }
+
+  exclude_ranges {
+    from = 8006
+    to   = 8010
+  }
+}
+`
+
+	testAccResourcePortConfigInvalidRange = `
+resource "random_port" "invalid" {
+  exclude_ranges {
+    from = 8005
+    to   = 8000
+  }
+}
+`
+
+	testAccResourcePortConfigMinGreaterThanMax = `
+resource "random_port" "invalid" {
+  min = 9000
+  max = 8000
+}
+`
+)
@@ -2,9 +2,14 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceShuffle() *schema.Resource {
@@ -14,86 +19,371 @@ func resourceShuffle() *schema.Resource {
 		CreateContext: CreateShuffle,
 		ReadContext:   schema.NoopContext,
 		DeleteContext: RemoveResourceFromState,
+		CustomizeDiff: logKeepersChange,
 
-		Schema: map[string]*schema.Schema{
-			"keepers": {
-				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
-					"resource. See [the main provider documentation](../index.html) for more information.",
-				Type:     schema.TypeMap,
-				Optional: true,
-				ForceNew: true,
+		Schema:        shuffleSchemaV1(),
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceShuffleV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: generationStateUpgrade,
 			},
+		},
+	}
+}
+
+func resourceShuffleV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: shuffleSchemaV0(),
+	}
+}
+
+// shuffleSchemaV1 uses shuffleSchemaV0 to obtain the V0 version of the Schema key-value entries but requires that the
+// generation entry be configured.
+func shuffleSchemaV1() map[string]*schema.Schema {
+	shuffleSchema := shuffleSchemaV0()
+	shuffleSchema["generation"] = generationSchema(false)
+
+	return shuffleSchema
+}
+
+// shuffleSchemaV0 returns the Schema key-value entries of random_shuffle before the generation entry was added.
+func shuffleSchemaV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"keepers": {
+			Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+				"resource. See [the main provider documentation](../index.html) for more information.",
+			Type:     schema.TypeMap,
+			Optional: true,
+			ForceNew: true,
+		},
+
+		"seed": {
+			Description: "Arbitrary string with which to seed the random number generator, in order to " +
+				"produce less-volatile permutations of the list.\n" +
+				"\n" +
+				"The random number generator of the Go standard library is seeded with the CRC-64 checksum of " +
+				"`seed`, using the ISO polynomial, interpreted as a signed 64-bit integer, so `seed_int` set to " +
+				"that checksum produces the same result.\n" +
+				"\n" +
+				"**Important:** Even with an identical seed, it is not guaranteed that the same permutation " +
+				"will be produced across different versions of Terraform. This argument causes the " +
+				"result to be *less volatile*, but not fixed for all time, unless `stable` is `true`.",
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+		},
+
+		"seed_int": {
+			Description: "An integer used directly as the seed of the random number generator of the Go " +
+				"standard library, as an alternative to `seed`, so that the seed is not derived from a string. " +
+				"Cannot be used with `seed` or `stable`.\n" +
+				"\n" +
+				"**Important:** As with `seed`, it is not guaranteed that the same permutation will be produced " +
+				"across different versions of Terraform.",
+			Type:          schema.TypeInt,
+			Optional:      true,
+			ForceNew:      true,
+			ConflictsWith: []string{"seed", "stable"},
+		},
+
+		"stable": {
+			Description: "Generate permutations using a random number generator implemented by this provider, " +
+				"rather than the one provided by the Go standard library, so that the same `seed` and " +
+				"`input` always produce the same result, regardless of the version of Go the provider was " +
+				"built with. Requires `seed` to be set. Default value is `false`.",
+			Type:         schema.TypeBool,
+			Optional:     true,
+			ForceNew:     true,
+			RequiredWith: []string{"seed"},
+		},
+
+		"input": {
+			Description: "The list of strings to shuffle. To shuffle objects or tuples while keeping each one " +
+				"whole, e.g., pairs of availability zone and subnet, encode each item with `jsonencode` and " +
+				"decode the items of `result` with `jsondecode`.",
+			Type:     schema.TypeList,
+			Required: true,
+			ForceNew: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+
+		"result_count": {
+			Description: "The number of results to return. Defaults to the number of items in the " +
+				"`input` list. If fewer items are requested, some elements will be excluded from the " +
+				"result. If more items are requested, items will be repeated in the result but not more " +
+				"frequently than the number of items in the input list, unless `without_replacement` is " +
+				"`true`, in which case more items must not be requested. A warning is returned when the `input` " +
+				"list is empty, see `error_on_empty`.",
+			Type:     schema.TypeInt,
+			Optional: true,
+			ForceNew: true,
+		},
+
+		"error_on_empty": {
+			Description: "Return an error instead of a warning when the `input` list is empty but " +
+				"`result_count` is greater than `0`, e.g., to catch a module input that is unexpectedly empty. " +
+				"Default value is `false`, in which case the result is empty.",
+			Type:     schema.TypeBool,
+			Optional: true,
+			ForceNew: true,
+		},
+
+		"without_replacement": {
+			Description: "Ensure that no item of the `input` list appears in the result more often than it " +
+				"appears in `input`. When `true`, `result_count` must not exceed the number of items in the " +
+				"`input` list. Default value is `false`.",
+			Type:     schema.TypeBool,
+			Optional: true,
+			ForceNew: true,
+		},
+
+		"dedupe": {
+			Description: "Remove duplicate items from the `input` list before shuffling, keeping the first " +
+				"occurrence of each, so that `result` contains each distinct item at most once. `result_count` " +
+				"is then capped at the number of distinct items, and `hands`, `folds` and `fold_index` also " +
+				"apply to the distinct items. Cannot be used with `weights`. Default value is `false`.",
+			Type:          schema.TypeBool,
+			Optional:      true,
+			ForceNew:      true,
+			ConflictsWith: []string{"weights"},
+		},
+
+		"temperature": {
+			Description: "How far the shuffle may move items from their position in `input`, between `0`, " +
+				"which returns the items in their original order, and `1`, which allows any permutation. " +
+				"Each item is swapped with one at most `temperature` times the length of `input` positions " +
+				"later, so lower values keep the result mostly ordered. Defaults to a full shuffle.",
+			Type:             schema.TypeFloat,
+			Optional:         true,
+			ForceNew:         true,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.FloatBetween(0, 1)),
+		},
+
+		"weights": {
+			Description: "A list of positive integers, one for each item in `input`, giving the relative " +
+				"likelihood of each item being chosen ahead of the others, e.g., `[70, 30]` to choose the " +
+				"first of two items 70% of the time when `result_count` is `1`. Each permutation is built by " +
+				"repeatedly choosing one of the remaining items with probability proportional to its weight, " +
+				"so weights affect the order of items but, as without weights, items are only repeated once " +
+				"every item has been chosen. Defaults to choosing every item with equal probability.",
+			Type:          schema.TypeList,
+			Optional:      true,
+			ForceNew:      true,
+			ConflictsWith: []string{"temperature"},
+			Elem: &schema.Schema{
+				Type: schema.TypeInt,
+			},
+		},
+
+		"hands": {
+			Description: "The number of hands to deal the shuffled `input` into. When set, `cards_per_hand` " +
+				"must also be set and the hands are returned in `dealt`.",
+			Type:             schema.TypeInt,
+			Optional:         true,
+			ForceNew:         true,
+			RequiredWith:     []string{"cards_per_hand"},
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+		},
+
+		"cards_per_hand": {
+			Description: "The number of items dealt into each hand. `hands` multiplied by `cards_per_hand` " +
+				"must not exceed the number of items in the `input` list unless `with_replacement` is `true`.",
+			Type:             schema.TypeInt,
+			Optional:         true,
+			ForceNew:         true,
+			RequiredWith:     []string{"hands"},
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+		},
+
+		"with_replacement": {
+			Description: "Allow items to be dealt more than once when there are not enough items in the " +
+				"`input` list to fill every hand. Items will be repeated but not more frequently than the " +
+				"number of items in the input list. Default value is `false`.",
+			Type:     schema.TypeBool,
+			Optional: true,
+			ForceNew: true,
+		},
+
+		"folds": {
+			Description: "The number of folds to partition the shuffled `input` into, for example for k-fold " +
+				"cross-validation. When set, the folds are returned in `fold_results` and `fold_index`. The " +
+				"minimum value is 2 and the value must not exceed the number of items in the `input` list.",
+			Type:             schema.TypeInt,
+			Optional:         true,
+			ForceNew:         true,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(2)),
+		},
+
+		"separator": {
+			Description: "The string placed between each item of `result` when joining them into " +
+				"`result_string`, e.g., `,`. Default value is `\"\"`.",
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+		},
 
-			"seed": {
-				Description: "Arbitrary string with which to seed the random number generator, in order to " +
-					"produce less-volatile permutations of the list.\n" +
-					"\n" +
-					"**Important:** Even with an identical seed, it is not guaranteed that the same permutation " +
-					"will be produced across different versions of Terraform. This argument causes the " +
-					"result to be *less volatile*, but not fixed for all time.",
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+		"result": {
+			Description: "Random permutation of the list of strings given in `input`.",
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
 			},
+		},
+
+		"result_string": {
+			Description: "The items of `result`, in the same order, joined into a single string with " +
+				"`separator` between each item.",
+			Type:     schema.TypeString,
+			Computed: true,
+		},
 
-			"input": {
-				Description: "The list of strings to shuffle.",
-				Type:        schema.TypeList,
-				Required:    true,
-				ForceNew:    true,
+		"dealt": {
+			Description: "The hands dealt round-robin from a random permutation of the list of strings " +
+				"given in `input`, when `hands` is set. The hand number is the index in the list. This is a " +
+				"list rather than a map of hand to items, as a map attribute can only hold strings, numbers or " +
+				"booleans. Use `{ for hand, items in random_shuffle.example.dealt : hand => items }` where a " +
+				"map is needed.",
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeList,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
+		},
 
-			"result_count": {
-				Description: "The number of results to return. Defaults to the number of items in the " +
-					"`input` list. If fewer items are requested, some elements will be excluded from the " +
-					"result. If more items are requested, items will be repeated in the result but not more " +
-					"frequently than the number of items in the input list.",
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-			},
-
-			"result": {
-				Description: "Random permutation of the list of strings given in `input`.",
-				Type:        schema.TypeList,
-				Computed:    true,
+		"fold_results": {
+			Description: "The folds dealt round-robin from a random permutation of the list of strings given " +
+				"in `input`, when `folds` is set. Every item appears in exactly one fold and the sizes of any " +
+				"two folds differ by at most one. The fold number is the index in the list.",
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeList,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
+		},
 
-			"id": {
-				Description: "A static value used internally by Terraform, this should not be referenced in configurations.",
-				Type:        schema.TypeString,
-				Computed:    true,
+		"indices": {
+			Description: "The position in `input` of each item of `result`, in the same order as `result`, " +
+				"e.g., to audit which items were chosen when `result_count` excludes or repeats items.",
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeInt,
 			},
 		},
+
+		"fold_index": {
+			Description: "The fold number of each item in `input`, in the same order as `input`, when `folds` " +
+				"is set. When `dedupe` is `true`, there is one fold number for each distinct item.",
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeInt,
+			},
+		},
+
+		"id": {
+			Description: "A static value used internally by Terraform, this should not be referenced in configurations.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
 	}
 }
 
-func CreateShuffle(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+func CreateShuffle(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	input := d.Get("input").([]interface{})
 	seed := d.Get("seed").(string)
 
+	// seed_int is read from the raw config as 0 is a meaningful value that GetOk would treat as unset.
+	var seedInt *int64
+	if config := d.GetRawConfig(); !config.IsNull() && !config.GetAttr("seed_int").IsNull() {
+		s := int64(d.Get("seed_int").(int))
+		seedInt = &s
+	}
+
+	if seed == "" && seedInt == nil {
+		seed = resourceSeed(meta, "random_shuffle", resourceShuffle().Schema, d)
+	}
+	hands := d.Get("hands").(int)
+	cardsPerHand := d.Get("cards_per_hand").(int)
+	withReplacement := d.Get("with_replacement").(bool)
+	folds := d.Get("folds").(int)
+	stable := d.Get("stable").(bool)
+
+	// positions holds the position in the input entry of each item of input, which differs only when deduplicated.
+	positions := make([]int, len(input))
+	for i := range positions {
+		positions[i] = i
+	}
+	if d.Get("dedupe").(bool) {
+		input, positions = distinctItems(input)
+	}
+
+	weights, diags := getWeights(d, len(input))
+	if diags.HasError() {
+		return diags
+	}
+
+	diags = append(diags, emptyInputDiagnostics(d, len(input))...)
+	if diags.HasError() {
+		return diags
+	}
+
+	// temperature is read from the raw config as 0 is a meaningful value that GetOk would treat as unset.
+	var temperature *float64
+	if config := d.GetRawConfig(); !config.IsNull() && !config.GetAttr("temperature").IsNull() {
+		t := d.Get("temperature").(float64)
+		temperature = &t
+	}
+
+	if hands > 0 {
+		if len(input) == 0 {
+			return diag.Errorf("unable to deal hands from an empty input list")
+		}
+
+		if !withReplacement && hands*cardsPerHand > len(input) {
+			return diag.Errorf("hands (%d) * cards_per_hand (%d) must be <= the number of items in input (%d) "+
+				"unless with_replacement is true", hands, cardsPerHand, len(input))
+		}
+	}
+
+	if folds > len(input) {
+		return diag.Errorf("folds (%d) must be <= the number of items in input (%d)", folds, len(input))
+	}
+
 	resultCount := d.Get("result_count").(int)
-	if resultCount == 0 {
+	if resultCount == 0 || d.Get("dedupe").(bool) && resultCount > len(input) {
 		resultCount = len(input)
 	}
+
+	if d.Get("without_replacement").(bool) && resultCount > len(input) {
+		return diag.Errorf("result_count (%d) must be <= the number of items in input (%d) when without_replacement "+
+			"is true", resultCount, len(input))
+	}
 	result := make([]interface{}, 0, resultCount)
+	indices := make([]interface{}, 0, resultCount)
 
 	if len(input) > 0 {
-		rand := NewRand(seed)
+		shufflePerm := newShufflePerm(seed, seedInt, stable, temperature, weights)
 
 		// Keep producing permutations until we fill our result
 	Batches:
 		for {
-			perm := rand.Perm(len(input))
+			perm := shufflePerm(len(input))
 
 			for _, i := range perm {
 				result = append(result, input[i])
+				indices = append(indices, positions[i])
 
 				if len(result) >= resultCount {
 					break Batches
@@ -109,5 +399,246 @@ func CreateShuffle(_ context.Context, d *schema.ResourceData, _ interface{}) dia
 		return diag.Errorf("error setting result: %s", err)
 	}
 
-	return nil
+	if err := d.Set("indices", indices); err != nil {
+		return diag.Errorf("error setting indices: %s", err)
+	}
+
+	resultStrings := make([]string, len(result))
+	for i, item := range result {
+		resultStrings[i] = item.(string)
+	}
+	if err := d.Set("result_string", strings.Join(resultStrings, d.Get("separator").(string))); err != nil {
+		return diag.Errorf("error setting result_string: %s", err)
+	}
+
+	if hands > 0 {
+		dealt := dealHands(newShufflePerm(seed, seedInt, stable, temperature, weights), input, hands, cardsPerHand)
+
+		if err := d.Set("dealt", dealt); err != nil {
+			return diag.Errorf("error setting dealt: %s", err)
+		}
+	}
+
+	if folds > 0 {
+		foldResults, foldIndex := partitionFolds(newShufflePerm(seed, seedInt, stable, temperature, weights)(len(input)), input, folds)
+
+		if err := d.Set("fold_results", foldResults); err != nil {
+			return diag.Errorf("error setting fold_results: %s", err)
+		}
+		if err := d.Set("fold_index", foldIndex); err != nil {
+			return diag.Errorf("error setting fold_index: %s", err)
+		}
+	}
+
+	if err := setGeneration(d); err != nil {
+		return diag.Errorf("error setting generation: %s", err)
+	}
+
+	return diags
+}
+
+// emptyInputDiagnostics returns a warning when the input entry in d holds no items, given by count, but result_count
+// asks for some, as the result is then empty. The warning is an error when error_on_empty is true.
+func emptyInputDiagnostics(d *schema.ResourceData, count int) diag.Diagnostics {
+	resultCount := d.Get("result_count").(int)
+	if count > 0 || resultCount < 1 {
+		return nil
+	}
+
+	severity := diag.Warning
+	if d.Get("error_on_empty").(bool) {
+		severity = diag.Error
+	}
+
+	return diag.Diagnostics{{
+		Severity: severity,
+		Summary:  "input is empty",
+		Detail: fmt.Sprintf("result_count is %d but input has no items, so the result is empty. Check the "+
+			"value given for input, or remove result_count.", resultCount),
+		AttributePath: cty.GetAttrPath("input"),
+	}}
+}
+
+// getWeights returns the weights entry in d, which must hold one positive weight for each of the count items in the
+// input entry, or nil when weights are not set.
+func getWeights(d *schema.ResourceData, count int) ([]int, diag.Diagnostics) {
+	v, ok := d.GetOk("weights")
+	if !ok {
+		return nil, nil
+	}
+
+	rawWeights := v.([]interface{})
+	if len(rawWeights) != count {
+		return nil, diag.Errorf("weights must contain one item for each item in input, got %d weights for %d items",
+			len(rawWeights), count)
+	}
+
+	weights := make([]int, len(rawWeights))
+	for i, w := range rawWeights {
+		weights[i] = w.(int)
+		if weights[i] < 1 {
+			return nil, diag.Errorf("weights must all be positive, got %d at index %d", weights[i], i)
+		}
+	}
+
+	return weights, nil
+}
+
+// distinctItems returns the items of input with any duplicates removed, keeping the first occurrence of each item in
+// its original order, along with the position in input of each of the distinct items.
+func distinctItems(input []interface{}) ([]interface{}, []int) {
+	seen := make(map[interface{}]bool, len(input))
+	distinct := make([]interface{}, 0, len(input))
+	positions := make([]int, 0, len(input))
+	for i, item := range input {
+		if !seen[item] {
+			seen[item] = true
+			distinct = append(distinct, item)
+			positions = append(positions, i)
+		}
+	}
+
+	return distinct, positions
+}
+
+// partitionFolds deals the items of input into folds, round-robin, in the order given by perm. It returns the folds
+// along with the fold number of each item in input.
+func partitionFolds(perm []int, input []interface{}, folds int) ([][]interface{}, []interface{}) {
+	foldResults := make([][]interface{}, folds)
+	for i := range foldResults {
+		foldResults[i] = make([]interface{}, 0, (len(input)+folds-1)/folds)
+	}
+	foldIndex := make([]interface{}, len(input))
+
+	for position, i := range perm {
+		fold := position % folds
+		foldResults[fold] = append(foldResults[fold], input[i])
+		foldIndex[i] = fold
+	}
+
+	return foldResults, foldIndex
+}
+
+// dealHands deals cardsPerHand items from input into each of the hands, round-robin, in the order given by
+// successive random permutations of input.
+func dealHands(shufflePerm func(int) []int, input []interface{}, hands, cardsPerHand int) [][]interface{} {
+	dealt := make([][]interface{}, hands)
+	for i := range dealt {
+		dealt[i] = make([]interface{}, 0, cardsPerHand)
+	}
+
+	var perm []int
+	for card := 0; card < hands*cardsPerHand; card++ {
+		if len(perm) == 0 {
+			perm = shufflePerm(len(input))
+		}
+
+		dealt[card%hands] = append(dealt[card%hands], input[perm[0]])
+		perm = perm[1:]
+	}
+
+	return dealt
+}
+
+// shuffleRand is the subset of the methods of *rand.Rand used to generate permutations, so that they can also be
+// generated by a pcgRand.
+type shuffleRand interface {
+	Intn(n int) int
+	Int63n(n int64) int64
+	Perm(n int) []int
+}
+
+// newShufflePerm returns a function producing successive permutations from the first of these random number
+// generators that applies: one seeded with seedInt when it is not nil, the pcgRand of newStableRand(seed) when stable
+// is true, NewRand(seed) when seed is not empty, and otherwise one seeded from randReader. When weights is set the
+// permutations are those of weightedPerm. Otherwise, they are those of rand.Perm when temperature is nil, and limited
+// by boundedPerm when it is not.
+func newShufflePerm(seed string, seedInt *int64, stable bool, temperature *float64, weights []int) func(int) []int {
+	var rand shuffleRand = NewRand(seed)
+	if seedInt != nil {
+		rand = newIntRand(*seedInt)
+	} else if stable {
+		rand = newStableRand(seed)
+	} else if seed == "" {
+		rand = newReaderRand(randReader)
+	}
+
+	if weights != nil {
+		return func(int) []int {
+			return weightedPerm(rand, weights)
+		}
+	}
+
+	if temperature == nil {
+		return rand.Perm
+	}
+
+	return func(n int) []int {
+		return boundedPerm(rand, n, *temperature)
+	}
+}
+
+// boundedPerm returns a permutation of [0,n) produced by a Fisher-Yates shuffle in which each position may only be
+// swapped with one at most temperature*(n-1) positions later. A temperature of 0 returns the identity permutation
+// and a temperature of 1 is equivalent to an unbounded shuffle.
+func boundedPerm(rand shuffleRand, n int, temperature float64) []int {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+
+	distance := int(math.Round(temperature * float64(n-1)))
+	if distance < 1 {
+		return perm
+	}
+
+	for i := 0; i < n-1; i++ {
+		upper := i + distance
+		if upper > n-1 {
+			upper = n - 1
+		}
+
+		j := i + rand.Intn(upper-i+1)
+		perm[i], perm[j] = perm[j], perm[i]
+	}
+
+	return perm
+}
+
+// weightedPerm returns a permutation of [0,len(weights)) built by repeatedly choosing one of the remaining indices
+// with probability proportional to its weight, all of which must be positive.
+func weightedPerm(rand shuffleRand, weights []int) []int {
+	remaining := make([]int, len(weights))
+	remainingWeights := append([]int(nil), weights...)
+	var total int64
+	for i, w := range weights {
+		remaining[i] = i
+		total += int64(w)
+	}
+
+	perm := make([]int, 0, len(weights))
+	for len(remaining) > 0 {
+		chosen := weightedIndex(rand, remainingWeights, total)
+
+		perm = append(perm, remaining[chosen])
+		total -= int64(remainingWeights[chosen])
+		remaining = append(remaining[:chosen], remaining[chosen+1:]...)
+		remainingWeights = append(remainingWeights[:chosen], remainingWeights[chosen+1:]...)
+	}
+
+	return perm
+}
+
+// weightedIndex returns an index of weights chosen with probability proportional to its weight, where total is the
+// sum of weights.
+func weightedIndex(rand shuffleRand, weights []int, total int64) int {
+	r := rand.Int63n(total)
+
+	chosen := 0
+	for r >= int64(weights[chosen]) {
+		r -= int64(weights[chosen])
+		chosen++
+	}
+
+	return chosen
 }
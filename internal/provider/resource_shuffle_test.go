@@ -1,10 +1,14 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"strconv"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -54,6 +58,26 @@ func TestAccResourceShuffleShorter(t *testing.T) {
 	})
 }
 
+func TestAccResourceShuffleResultString(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceShuffleConfigResultString,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceShuffleCheck(
+						"random_shuffle.result_string",
+						[]string{"a", "c", "b"},
+					),
+					resource.TestCheckResourceAttr("random_shuffle.result_string", "result_string", "a,c,b"),
+					resource.TestCheckResourceAttr("random_shuffle.no_separator", "result_string", "acbed"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccResourceShuffleLonger(t *testing.T) {
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -66,6 +90,51 @@ func TestAccResourceShuffleLonger(t *testing.T) {
 						"random_shuffle.longer_length",
 						[]string{"a", "c", "b", "e", "d", "a", "e", "d", "c", "b", "a", "b"},
 					),
+					resource.TestCheckResourceAttr("random_shuffle.longer_length", "indices.#", "12"),
+					resource.TestCheckResourceAttr("random_shuffle.longer_length", "indices.1", "2"),
+					resource.TestCheckResourceAttr("random_shuffle.longer_length", "indices.11", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceShuffleWithoutReplacement(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceShuffleConfigWithoutReplacement,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceShuffleCheck(
+						"random_shuffle.without_replacement",
+						[]string{"a", "c", "b", "e", "d"},
+					),
+				),
+			},
+			{
+				Config:      testAccResourceShuffleConfigWithoutReplacementTooMany,
+				ExpectError: regexp.MustCompile(`.*result_count \(12\) must be <= the number of items in input \(5\) when\s+without_replacement is true`),
+			},
+		},
+	})
+}
+
+func TestAccResourceShuffleDedupe(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceShuffleConfigDedupe,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceShuffleCheck(
+						"random_shuffle.dedupe",
+						[]string{"a", "c", "b", "e", "d"},
+					),
+					resource.TestCheckResourceAttr("random_shuffle.dedupe", "indices.1", "3"),
+					resource.TestCheckResourceAttr("random_shuffle.dedupe", "indices.3", "6"),
 				),
 			},
 		},
@@ -86,10 +155,59 @@ func TestAccResourceShuffleEmpty(t *testing.T) {
 					),
 				),
 			},
+			{
+				Config:      testAccResourceShuffleConfigEmptyErrorOnEmpty,
+				ExpectError: regexp.MustCompile(`input is empty`),
+			},
 		},
 	})
 }
 
+func TestCreateShuffleEmptyInput(t *testing.T) {
+	cases := []struct {
+		name         string
+		resultCount  int
+		errorOnEmpty bool
+		expected     diag.Severity
+		expectDiags  bool
+	}{
+		{name: "result_count not set"},
+		{name: "warning", resultCount: 12, expected: diag.Warning, expectDiags: true},
+		{name: "error_on_empty", resultCount: 12, errorOnEmpty: true, expected: diag.Error, expectDiags: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := resourceShuffle().TestResourceData()
+			for k, v := range map[string]interface{}{
+				"input":          []string{},
+				"result_count":   c.resultCount,
+				"error_on_empty": c.errorOnEmpty,
+			} {
+				if err := d.Set(k, v); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			diags := CreateShuffle(context.Background(), d, nil)
+
+			if !c.expectDiags {
+				if len(diags) != 0 {
+					t.Fatalf("expected no diagnostics, got: %v", diags)
+				}
+				return
+			}
+
+			if len(diags) != 1 || diags[0].Severity != c.expected || diags[0].Summary != "input is empty" {
+				t.Fatalf("expected a single diagnostic with severity %v, got: %v", c.expected, diags)
+			}
+			if c.expected == diag.Warning && len(d.Get("result").([]interface{})) != 0 {
+				t.Errorf("expected an empty result, got: %v", d.Get("result"))
+			}
+		})
+	}
+}
+
 func TestAccResourceShuffleOne(t *testing.T) {
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -108,6 +226,344 @@ func TestAccResourceShuffleOne(t *testing.T) {
 	})
 }
 
+func TestAccResourceShuffleDeal(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceShuffleConfigDeal,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceShuffleDealtCheck(
+						"random_shuffle.deal",
+						[]string{"a", "b", "c", "d", "e", "f"},
+						2,
+						3,
+					),
+					resource.TestCheckResourceAttr("random_shuffle.deal", "dealt.0.0", "a"),
+					resource.TestCheckResourceAttr("random_shuffle.deal", "dealt.1.0", "f"),
+				),
+			},
+			{
+				Config:      testAccResourceShuffleConfigDealTooMany,
+				ExpectError: regexp.MustCompile(`.*hands \(3\) \* cards_per_hand \(3\) must be <= the number of items in input \(6\)`),
+			},
+		},
+	})
+}
+
+func TestAccResourceShuffleDealWithReplacement(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceShuffleConfigDealWithReplacement,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("random_shuffle.deal", "dealt.#", "3"),
+					resource.TestCheckResourceAttr("random_shuffle.deal", "dealt.0.#", "3"),
+					resource.TestCheckResourceAttr("random_shuffle.deal", "dealt.1.#", "3"),
+					resource.TestCheckResourceAttr("random_shuffle.deal", "dealt.2.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceShuffleTemperature(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceShuffleConfigTemperatureZero,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceShuffleCheck(
+						"random_shuffle.temperature",
+						[]string{"a", "b", "c", "d", "e"},
+					),
+				),
+			},
+			{
+				Config:      testAccResourceShuffleConfigTemperatureTooHigh,
+				ExpectError: regexp.MustCompile(`.*expected temperature to be in the range \(0\.000000 - 1\.000000\)`),
+			},
+		},
+	})
+}
+
+func TestAccResourceShuffleFolds(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceShuffleConfigFolds,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceShuffleFoldsCheck(
+						"random_shuffle.folds",
+						[]string{"a", "b", "c", "d", "e", "f", "g"},
+						3,
+					),
+				),
+			},
+			{
+				Config:      testAccResourceShuffleConfigFoldsTooMany,
+				ExpectError: regexp.MustCompile(`.*folds \(6\) must be <= the number of items in input \(5\)`),
+			},
+			{
+				Config:      testAccResourceShuffleConfigFoldsTooFew,
+				ExpectError: regexp.MustCompile(`.*expected folds to be at least \(2\), got 1`),
+			},
+		},
+	})
+}
+
+// Unlike the results above, these results are pinned by the stable argument and must not change.
+func TestAccResourceShuffleStable(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceShuffleConfigStable,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceShuffleCheck(
+						"random_shuffle.stable",
+						[]string{"b", "d", "e", "a", "c", "c", "a"},
+					),
+				),
+			},
+			{
+				Config:      testAccResourceShuffleConfigStableNoSeed,
+				ExpectError: regexp.MustCompile(`.*all of ` + "`seed,stable`" + ` must be specified`),
+			},
+		},
+	})
+}
+
+func TestAccResourceShuffleSeedInt(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceShuffleConfigSeedInt,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceShuffleCheck("random_shuffle.zero", []string{"e", "c", "d", "a", "b"}),
+					// The checksum of the seed "-" produces the same result as TestAccResourceShuffleDefault.
+					testAccResourceShuffleCheck("random_shuffle.checksum", []string{"a", "c", "b", "e", "d"}),
+				),
+			},
+			{
+				Config:      testAccResourceShuffleConfigSeedIntConflict,
+				ExpectError: regexp.MustCompile(`.*"seed_int": conflicts with seed`),
+			},
+		},
+	})
+}
+
+func TestNewShufflePermSeedInt(t *testing.T) {
+	// These permutations pin the results for a given seed_int, which is used directly as the math/rand seed.
+	cases := []struct {
+		seedInt  int64
+		expected []int
+	}{
+		{0, []int{4, 2, 3, 0, 1}},
+		{42, []int{0, 1, 3, 4, 2}},
+		{-1, []int{3, 0, 1, 4, 2}},
+		{6007801902912241664, []int{0, 2, 1, 4, 3}},
+	}
+
+	for _, c := range cases {
+		t.Run(strconv.FormatInt(c.seedInt, 10), func(t *testing.T) {
+			seedInt := c.seedInt
+			if got := newShufflePerm("", &seedInt, false, nil, nil)(5); !cmp.Equal(got, c.expected) {
+				t.Errorf("got %v; want %v", got, c.expected)
+			}
+		})
+	}
+
+	seedInt := stringSeed("-")
+	if got, want := newShufflePerm("", &seedInt, false, nil, nil)(5), newShufflePerm("-", nil, false, nil, nil)(5); !cmp.Equal(got, want) {
+		t.Errorf("seed_int %d: got %v; want the permutation of seed %q, %v", seedInt, got, "-", want)
+	}
+}
+
+func TestAccResourceShuffleWeights(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceShuffleConfigWeights,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceShuffleCheck(
+						"random_shuffle.weights",
+						[]string{"a", "b", "c", "d", "e", "e", "a"},
+					),
+				),
+			},
+			{
+				Config:      testAccResourceShuffleConfigWeightsWrongLength,
+				ExpectError: regexp.MustCompile(`.*weights must contain one item for each item in input, got 2 weights for 3 items`),
+			},
+			{
+				Config:      testAccResourceShuffleConfigWeightsNotPositive,
+				ExpectError: regexp.MustCompile(`.*weights must all be positive, got 0 at index 1`),
+			},
+		},
+	})
+}
+
+func TestWeightedPerm(t *testing.T) {
+	const trials = 10000
+
+	rand := NewRand("-")
+	weights := []int{70, 20, 10}
+
+	first := make([]int, len(weights))
+	for trial := 0; trial < trials; trial++ {
+		perm := weightedPerm(rand, weights)
+
+		seen := make(map[int]bool, len(weights))
+		for _, p := range perm {
+			if p < 0 || p >= len(weights) || seen[p] {
+				t.Fatalf("produced an invalid permutation: %v", perm)
+			}
+			seen[p] = true
+		}
+		if len(perm) != len(weights) {
+			t.Fatalf("produced a permutation of length %d; want %d", len(perm), len(weights))
+		}
+
+		first[perm[0]]++
+	}
+
+	for i, w := range weights {
+		expected := trials * w / 100
+		tolerance := trials / 50
+		if first[i] < expected-tolerance || first[i] > expected+tolerance {
+			t.Errorf("item %d was chosen first %d times; want %d +/- %d", i, first[i], expected, tolerance)
+		}
+	}
+}
+
+func TestBoundedPerm(t *testing.T) {
+	const n = 50
+
+	// displacement returns the total distance moved by each item, averaged over many permutations.
+	displacement := func(temperature float64) float64 {
+		rand := NewRand("-")
+
+		total := 0
+		for trial := 0; trial < 200; trial++ {
+			perm := boundedPerm(rand, n, temperature)
+
+			seen := make(map[int]bool, n)
+			for i, p := range perm {
+				if p < 0 || p >= n || seen[p] {
+					t.Fatalf("temperature %v produced an invalid permutation: %v", temperature, perm)
+				}
+				seen[p] = true
+
+				if p > i {
+					total += p - i
+				} else {
+					total += i - p
+				}
+			}
+		}
+
+		return float64(total) / 200
+	}
+
+	if got := displacement(0); got != 0 {
+		t.Errorf("temperature 0 displaced items by %v; want 0", got)
+	}
+
+	previous := 0.0
+	for _, temperature := range []float64{0.1, 0.5, 1} {
+		got := displacement(temperature)
+		if got <= previous {
+			t.Errorf("temperature %v displaced items by %v; want more than %v", temperature, got, previous)
+		}
+		previous = got
+	}
+}
+
+func TestPartitionFolds(t *testing.T) {
+	input := []interface{}{"a", "b", "c", "d", "e", "f", "g"}
+	perm := NewRand("-").Perm(len(input))
+
+	foldResults, foldIndex := partitionFolds(perm, input, 3)
+
+	if len(foldResults) != 3 {
+		t.Fatalf("got %d folds; want 3", len(foldResults))
+	}
+
+	seen := make(map[interface{}]int)
+	for fold, items := range foldResults {
+		if size := len(items); size < len(input)/3 || size > len(input)/3+1 {
+			t.Errorf("fold %d has %d items; want %d or %d", fold, size, len(input)/3, len(input)/3+1)
+		}
+
+		for _, item := range items {
+			seen[item]++
+		}
+	}
+
+	for i, item := range input {
+		if seen[item] != 1 {
+			t.Errorf("item %q appears in %d folds; want 1", item, seen[item])
+		}
+
+		found := false
+		for _, v := range foldResults[foldIndex[i].(int)] {
+			found = found || v == item
+		}
+		if !found {
+			t.Errorf("item %q has fold index %d but is not in that fold", item, foldIndex[i])
+		}
+	}
+}
+
+func TestCreateShuffleRandReader(t *testing.T) {
+	setRandReader(t, &sequenceReader{})
+
+	d := resourceShuffle().TestResourceData()
+	if err := d.Set("input", []string{"a", "b", "c", "d", "e"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if diags := CreateShuffle(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	expected := []interface{}{"e", "a", "b", "d", "c"}
+	if got := d.Get("result").([]interface{}); !cmp.Equal(got, expected) {
+		t.Errorf("got %v; want %v", got, expected)
+	}
+}
+
+func TestDistinctItems(t *testing.T) {
+	got, gotPositions := distinctItems([]interface{}{"b", "a", "b", "c", "a"})
+	want := []interface{}{"b", "a", "c"}
+	wantPositions := []int{0, 1, 3}
+
+	if len(got) != len(want) || len(gotPositions) != len(wantPositions) {
+		t.Fatalf("got %v at %v; want %v at %v", got, gotPositions, want, wantPositions)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("index %d is %q; want %q", i, got[i], want[i])
+		}
+		if gotPositions[i] != wantPositions[i] {
+			t.Errorf("position %d is %d; want %d", i, gotPositions[i], wantPositions[i])
+		}
+	}
+}
+
 func testAccResourceShuffleCheck(id string, wants []string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[id]
@@ -137,6 +593,92 @@ func testAccResourceShuffleCheck(id string, wants []string) resource.TestCheckFu
 	}
 }
 
+// testAccResourceShuffleDealtCheck checks that every hand holds cardsPerHand items and that no item from input has
+// been dealt more than once.
+func testAccResourceShuffleDealtCheck(id string, input []string, hands, cardsPerHand int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("Not found: %s", id)
+		}
+
+		attrs := rs.Primary.Attributes
+
+		if got, want := attrs["dealt.#"], strconv.Itoa(hands); got != want {
+			return fmt.Errorf("got %s hands; want %s", got, want)
+		}
+
+		remaining := make(map[string]int)
+		for _, card := range input {
+			remaining[card]++
+		}
+
+		for hand := 0; hand < hands; hand++ {
+			if got, want := attrs[fmt.Sprintf("dealt.%d.#", hand)], strconv.Itoa(cardsPerHand); got != want {
+				return fmt.Errorf("hand %d has %s items; want %s", hand, got, want)
+			}
+
+			for i := 0; i < cardsPerHand; i++ {
+				card := attrs[fmt.Sprintf("dealt.%d.%d", hand, i)]
+				if remaining[card] == 0 {
+					return fmt.Errorf("hand %d item %d is %q, which has already been dealt or is not in input", hand, i, card)
+				}
+				remaining[card]--
+			}
+		}
+
+		return nil
+	}
+}
+
+// testAccResourceShuffleFoldsCheck checks that every item from input appears in exactly one fold, that the fold
+// sizes differ by at most one and that fold_index gives the fold holding each item.
+func testAccResourceShuffleFoldsCheck(id string, input []string, folds int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("Not found: %s", id)
+		}
+
+		attrs := rs.Primary.Attributes
+
+		if got, want := attrs["fold_results.#"], strconv.Itoa(folds); got != want {
+			return fmt.Errorf("got %s folds; want %s", got, want)
+		}
+
+		foldOf := make(map[string]string)
+		for fold := 0; fold < folds; fold++ {
+			size, err := strconv.Atoi(attrs[fmt.Sprintf("fold_results.%d.#", fold)])
+			if err != nil {
+				return err
+			}
+			if size < len(input)/folds || size > (len(input)+folds-1)/folds {
+				return fmt.Errorf("fold %d has %d items, which is unbalanced", fold, size)
+			}
+
+			for i := 0; i < size; i++ {
+				item := attrs[fmt.Sprintf("fold_results.%d.%d", fold, i)]
+				if _, ok := foldOf[item]; ok {
+					return fmt.Errorf("item %q appears in more than one fold", item)
+				}
+				foldOf[item] = strconv.Itoa(fold)
+			}
+		}
+
+		for i, item := range input {
+			fold, ok := foldOf[item]
+			if !ok {
+				return fmt.Errorf("item %q does not appear in any fold", item)
+			}
+			if got := attrs[fmt.Sprintf("fold_index.%d", i)]; got != fold {
+				return fmt.Errorf("fold_index %d is %s; want %s", i, got, fold)
+			}
+		}
+
+		return nil
+	}
+}
+
 const (
 	testAccResourceShuffleConfigDefault = `
 resource "random_shuffle" "default_length" {
@@ -150,6 +692,20 @@ resource "random_shuffle" "shorter_length" {
     seed = "-"
     result_count = 3
 }
+`
+
+	testAccResourceShuffleConfigResultString = `
+resource "random_shuffle" "result_string" {
+    input = ["a", "b", "c", "d", "e"]
+    seed = "-"
+    result_count = 3
+    separator = ","
+}
+
+resource "random_shuffle" "no_separator" {
+    input = ["a", "b", "c", "d", "e"]
+    seed = "-"
+}
 `
 
 	testAccResourceShuffleConfigLonger = `
@@ -158,6 +714,33 @@ resource "random_shuffle" "longer_length" {
     seed = "-"
     result_count = 12
 }
+`
+
+	testAccResourceShuffleConfigWithoutReplacement = `
+resource "random_shuffle" "without_replacement" {
+    input = ["a", "b", "c", "d", "e"]
+    seed = "-"
+    result_count = 5
+    without_replacement = true
+}
+`
+
+	testAccResourceShuffleConfigWithoutReplacementTooMany = `
+resource "random_shuffle" "without_replacement" {
+    input = ["a", "b", "c", "d", "e"]
+    seed = "-"
+    result_count = 12
+    without_replacement = true
+}
+`
+
+	testAccResourceShuffleConfigDedupe = `
+resource "random_shuffle" "dedupe" {
+    input = ["a", "b", "a", "c", "b", "d", "e", "e"]
+    seed = "-"
+    result_count = 12
+    dedupe = true
+}
 `
 
 	testAccResourceShuffleConfigEmpty = `
@@ -166,6 +749,15 @@ resource "random_shuffle" "empty_length" {
     seed = "-"
     result_count = 12
 }
+`
+
+	testAccResourceShuffleConfigEmptyErrorOnEmpty = `
+resource "random_shuffle" "empty_length" {
+    input = []
+    seed = "-"
+    result_count = 12
+    error_on_empty = true
+}
 `
 
 	testAccResourceShuffleConfigOne = `
@@ -174,5 +766,127 @@ resource "random_shuffle" "one_length" {
     seed = "-"
     result_count = 1
 }
+`
+
+	testAccResourceShuffleConfigDeal = `
+resource "random_shuffle" "deal" {
+    input = ["a", "b", "c", "d", "e", "f"]
+    seed = "-"
+    hands = 2
+    cards_per_hand = 3
+}
+`
+
+	testAccResourceShuffleConfigDealTooMany = `
+resource "random_shuffle" "deal" {
+    input = ["a", "b", "c", "d", "e", "f"]
+    seed = "-"
+    hands = 3
+    cards_per_hand = 3
+}
+`
+
+	testAccResourceShuffleConfigTemperatureZero = `
+resource "random_shuffle" "temperature" {
+    input = ["a", "b", "c", "d", "e"]
+    temperature = 0
+}
+`
+
+	testAccResourceShuffleConfigTemperatureTooHigh = `
+resource "random_shuffle" "temperature" {
+    input = ["a", "b", "c", "d", "e"]
+    temperature = 1.5
+}
+`
+
+	testAccResourceShuffleConfigDealWithReplacement = `
+resource "random_shuffle" "deal" {
+    input = ["a", "b", "c", "d", "e"]
+    hands = 3
+    cards_per_hand = 3
+    with_replacement = true
+}
+`
+
+	testAccResourceShuffleConfigFolds = `
+resource "random_shuffle" "folds" {
+    input = ["a", "b", "c", "d", "e", "f", "g"]
+    seed = "-"
+    folds = 3
+}
+`
+
+	testAccResourceShuffleConfigStable = `
+resource "random_shuffle" "stable" {
+    input = ["a", "b", "c", "d", "e"]
+    seed = "-"
+    stable = true
+    result_count = 7
+}
+`
+
+	testAccResourceShuffleConfigStableNoSeed = `
+resource "random_shuffle" "stable" {
+    input = ["a", "b", "c", "d", "e"]
+    stable = true
+}
+`
+
+	testAccResourceShuffleConfigSeedInt = `
+resource "random_shuffle" "zero" {
+    input    = ["a", "b", "c", "d", "e"]
+    seed_int = 0
+}
+
+resource "random_shuffle" "checksum" {
+    input    = ["a", "b", "c", "d", "e"]
+    seed_int = 6007801902912241664
+}
+`
+
+	testAccResourceShuffleConfigSeedIntConflict = `
+resource "random_shuffle" "conflict" {
+    input    = ["a", "b", "c", "d", "e"]
+    seed     = "-"
+    seed_int = 1
+}
+`
+
+	testAccResourceShuffleConfigWeights = `
+resource "random_shuffle" "weights" {
+    input = ["a", "b", "c", "d", "e"]
+    seed = "-"
+    weights = [5, 1, 1, 1, 1]
+    result_count = 7
+}
+`
+
+	testAccResourceShuffleConfigWeightsWrongLength = `
+resource "random_shuffle" "weights" {
+    input = ["a", "b", "c"]
+    weights = [1, 2]
+}
+`
+
+	testAccResourceShuffleConfigWeightsNotPositive = `
+resource "random_shuffle" "weights" {
+    input = ["a", "b", "c"]
+    weights = [1, 0, 2]
+}
+`
+
+	testAccResourceShuffleConfigFoldsTooMany = `
+resource "random_shuffle" "folds" {
+    input = ["a", "b", "c", "d", "e"]
+    folds = 6
+}
+`
+
+	testAccResourceShuffleConfigFoldsTooFew = `
+resource "random_shuffle" "folds" {
+    input = ["a", "b", "c", "d", "e"]
+    folds = 1
+}
 `
 )
@@ -3,7 +3,10 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -11,11 +14,24 @@ import (
 // resourceString and resourcePassword both use the same set of CustomizeDiffFunc(s) in order to handle the deprecation
 // of the `number` attribute and the simultaneous addition of the `numeric` attribute. planDefaultIfAllNull handles
 // ensuring that both `number` and `numeric` default to `true` when they are both absent from config.
-// planSyncIfChange handles keeping number and numeric in-sync when either one has been changed.
+// planSyncIfChange handles keeping number and numeric in-sync when either one has been changed. isAtLeastSumOf ensures
+// that length is at least the sum of the min_* attributes, and planMaxLength that neither it nor the longest expansion
+// of grammar exceeds the max_string_length of the provider, when planning. planCase rejects a case that could break
+// other arguments, and planEmptyOverrideSpecial a min_special that an empty override_special cannot satisfy.
+// logKeepersChange logs which keepers keys changed, planKeepersChange decides whether a change to keepers replaces the
+// resource, and planGeneration plans the incremented generation when it does not.
 func resourceString() *schema.Resource {
 	customizeDiffFuncs := planDefaultIfAllNull(true, "number", "numeric")
 	customizeDiffFuncs = append(customizeDiffFuncs, planSyncIfChange("number", "numeric"))
 	customizeDiffFuncs = append(customizeDiffFuncs, planSyncIfChange("numeric", "number"))
+	customizeDiffFuncs = append(customizeDiffFuncs, isAtLeastSumOf("length", "min_upper", "min_lower", "min_numeric", "min_special"))
+	customizeDiffFuncs = append(customizeDiffFuncs, planMaxLength)
+	customizeDiffFuncs = append(customizeDiffFuncs, planCase)
+	customizeDiffFuncs = append(customizeDiffFuncs, planEmptyOverrideSpecial)
+	customizeDiffFuncs = append(customizeDiffFuncs, logKeepersChange)
+	customizeDiffFuncs = append(customizeDiffFuncs, planKeepersChange("result", "id", "result_base64", "result_hex", "check_digit",
+		"result_length", "sensitive_result", "results"))
+	customizeDiffFuncs = append(customizeDiffFuncs, planGeneration)
 
 	return &schema.Resource{
 		Description: "The resource `random_string` generates a random permutation of alphanumeric " +
@@ -26,14 +42,15 @@ func resourceString() *schema.Resource {
 			"Historically this resource's intended usage has been ambiguous as the original example used " +
 			"it in a password. For backwards compatibility it will continue to exist. For unique ids please " +
 			"use [random_id](id.html), for sensitive random values please use [random_password](password.html).",
-		CreateContext: createStringFunc(false),
-		ReadContext:   readNil,
+		CreateContext: createString,
+		ReadContext:   readString,
+		UpdateContext: updateOnKeepersChange(createString),
 		DeleteContext: RemoveResourceFromState,
 		// MigrateState is deprecated but the implementation is being left in place as per the
 		// [SDK documentation](https://github.com/hashicorp/terraform-plugin-sdk/blob/main/helper/schema/resource.go#L91).
 		MigrateState:  resourceRandomStringMigrateState,
-		SchemaVersion: 2,
-		Schema:        stringSchemaV2(),
+		SchemaVersion: 3,
+		Schema:        stringSchemaV3(),
 		Importer: &schema.ResourceImporter{
 			StateContext: importStringFunc,
 		},
@@ -43,6 +60,11 @@ func resourceString() *schema.Resource {
 				Type:    resourceStringV1().CoreConfigSchema().ImpliedType(),
 				Upgrade: resourcePasswordStringStateUpgradeV1,
 			},
+			{
+				Version: 2,
+				Type:    resourceStringV2().CoreConfigSchema().ImpliedType(),
+				Upgrade: generationStateUpgrade,
+			},
 		},
 		CustomizeDiff: customdiff.All(
 			customizeDiffFuncs...,
@@ -50,16 +72,240 @@ func resourceString() *schema.Resource {
 	}
 }
 
+// createString generates result as createStringFunc does and, when quantity is set, generates further distinct
+// strings from the same configuration until there are quantity of them in results. It also sets the generation, as
+// it is used both to create the resource and to generate a new result in place.
+func createString(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	diags := createStringFunc("random_string")(ctx, d, meta)
+	if diags.HasError() {
+		return diags
+	}
+
+	if err := setGeneration(d); err != nil {
+		d.SetId("")
+		return append(diags, diag.Errorf("error setting generation: %s", err)...)
+	}
+
+	quantity := d.Get("quantity").(int)
+	if quantity == 0 {
+		if err := d.Set("results", []string{}); err != nil {
+			return append(diags, diag.Errorf("error setting results: %s", err)...)
+		}
+
+		return diags
+	}
+
+	// The same reader is used for every string so that a seeded reader produces a different string each time.
+	reader := stringRandReader(d, meta, "random_string")
+	results := []string{d.Get("result").(string)}
+	for attempt := 1; len(results) < quantity; attempt++ {
+		if attempt > maxGenerateAttempts*quantity {
+			d.SetId("")
+			return append(diags, diag.Errorf("unable to generate %d distinct strings after %d attempts, consider "+
+				"increasing length or enabling additional character classes", quantity, maxGenerateAttempts*quantity)...)
+		}
+
+		result, _, valueDiags := generateStringValue(d, meta, reader)
+		if valueDiags.HasError() {
+			d.SetId("")
+			return append(diags, valueDiags...)
+		}
+
+		if isMutuallyDistinct(string(result), results, 0) {
+			results = append(results, string(result))
+		}
+	}
+
+	if err := d.Set("results", results); err != nil {
+		d.SetId("")
+		return append(diags, diag.Errorf("error setting results: %s", err)...)
+	}
+
+	return diags
+}
+
+// readString populates the result encodings as readResultEncodings does. It also stores results as an empty list
+// when it is not set, as a computed list that is absent from state is always planned as changing, so that resources
+// created before quantity was introduced do not plan a change to results.
+func readString(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := readResultEncodings(ctx, d, meta); diags.HasError() {
+		return diags
+	}
+
+	if err := d.Set("results", d.Get("results")); err != nil {
+		return diag.Errorf("error setting results: %s", err)
+	}
+
+	return nil
+}
+
+// keepersImportSeparator introduces the keepers of an import ID, so that a result may itself contain | characters.
+const keepersImportSeparator = "|keepers:"
+
+// importStringFunc imports a random_string from an ID of the form result, or result|keepers:key1=val1,key2=val2 to
+// also import keepers. The ID is split at the last keepersImportSeparator, so keeper values must not contain ,
+// characters.
 func importStringFunc(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	val := d.Id()
 
+	sep := strings.LastIndex(val, keepersImportSeparator)
+	if sep != -1 {
+		keepers, err := parseImportKeepers(val[sep+len(keepersImportSeparator):])
+		if err != nil {
+			return nil, err
+		}
+
+		if err := d.Set("keepers", keepers); err != nil {
+			return nil, fmt.Errorf("error setting keepers: %w", err)
+		}
+
+		val = val[:sep]
+		d.SetId(val)
+	}
+
+	results, err := parseImportResults(val)
+	if err != nil {
+		return nil, err
+	}
+
+	val = results[0]
+	d.SetId(val)
+
 	if err := d.Set("result", val); err != nil {
 		return nil, fmt.Errorf("error setting result: %w", err)
 	}
 
+	if err := setResultEncodings(d, val); err != nil {
+		return nil, fmt.Errorf("error setting result encodings: %w", err)
+	}
+
+	if len(results) > 1 {
+		if err := d.Set("results", results); err != nil {
+			return nil, fmt.Errorf("error setting results: %w", err)
+		}
+
+		if err := d.Set("quantity", len(results)); err != nil {
+			return nil, fmt.Errorf("error setting quantity: %w", err)
+		}
+	}
+
+	if err := setImportedStringConfig(d, results); err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("generation", 1); err != nil {
+		return nil, fmt.Errorf("error setting generation: %w", err)
+	}
+
 	return []*schema.ResourceData{d}, nil
 }
 
+// resultsImportPrefix marks an import ID given as a comma-delimited list of the results of a random_string with
+// quantity set, rather than a single result that may itself contain commas.
+const resultsImportPrefix = "results:"
+
+// parseImportResults parses the results of an import ID, given as a single result, as a newline-delimited list of the
+// results of a random_string with quantity set, or as a comma-delimited list of such results prefixed with
+// resultsImportPrefix. A single result containing commas is therefore never split. Every result of a random_string
+// has the same length, so the results of a list must all have the same length.
+func parseImportResults(s string) ([]string, error) {
+	if s == "" {
+		return nil, fmt.Errorf("invalid import ID, the result must not be empty")
+	}
+
+	var results []string
+	switch {
+	case strings.Contains(s, "\n"):
+		results = strings.Split(strings.TrimRight(s, "\n"), "\n")
+	case strings.HasPrefix(s, resultsImportPrefix):
+		results = strings.Split(strings.TrimPrefix(s, resultsImportPrefix), ",")
+	default:
+		return []string{s}, nil
+	}
+
+	for _, result := range results {
+		if result == "" {
+			return nil, fmt.Errorf("invalid import ID, the results must not be empty")
+		}
+		if utf8.RuneCountInString(result) != utf8.RuneCountInString(results[0]) {
+			return nil, fmt.Errorf("invalid import ID, the results must all have the same length")
+		}
+	}
+
+	return results, nil
+}
+
+// parseImportKeepers parses the keepers of an import ID, given in the form key1=val1,key2=val2. Every key must be
+// non-empty and unique, while values may be empty.
+func parseImportKeepers(s string) (map[string]interface{}, error) {
+	if s == "" {
+		return nil, fmt.Errorf("invalid import ID, expected keepers of the form key1=val1,key2=val2 after %s", keepersImportSeparator)
+	}
+
+	keepers := make(map[string]interface{})
+	for _, pair := range strings.Split(s, ",") {
+		sep := strings.Index(pair, "=")
+		if sep < 1 {
+			return nil, fmt.Errorf("invalid import ID, expected keeper of the form key=value, got: %q", pair)
+		}
+
+		key := pair[:sep]
+		if _, ok := keepers[key]; ok {
+			return nil, fmt.Errorf("invalid import ID, keeper %q is given more than once", key)
+		}
+
+		keepers[key] = pair[sep+1:]
+	}
+
+	return keepers, nil
+}
+
+// setImportedStringConfig sets length and the character class arguments to the values inferred from the imported
+// results, so that a plan following the import is a no-op for a configuration that matches. Each character class
+// is enabled when any of results contains at least one of its characters, with any character that is not an
+// uppercase or lowercase letter or a digit counting as special, and the min_* arguments are set to their defaults.
+// length is counted in bytes unless the results contain multi-byte characters, which are generated when length_unit
+// is runes or graphemes, in which case length is counted in runes and length_unit is set to runes.
+func setImportedStringConfig(d *schema.ResourceData, results []string) error {
+	val := strings.Join(results, "")
+	alphanumeric := upperChars + lowerChars + numChars
+	special := strings.IndexFunc(val, func(r rune) bool {
+		return !strings.ContainsRune(alphanumeric, r)
+	}) >= 0
+
+	config := map[string]interface{}{
+		"length":      len(results[0]),
+		"upper":       strings.ContainsAny(val, upperChars),
+		"lower":       strings.ContainsAny(val, lowerChars),
+		"number":      strings.ContainsAny(val, numChars),
+		"numeric":     strings.ContainsAny(val, numChars),
+		"special":     special,
+		"min_upper":   0,
+		"min_lower":   0,
+		"min_numeric": 0,
+		"min_special": 0,
+	}
+
+	if runes := utf8.RuneCountInString(results[0]); runes != len(results[0]) {
+		config["length"] = runes
+		config["length_unit"] = lengthUnitRunes
+	}
+
+	for k, v := range config {
+		if err := d.Set(k, v); err != nil {
+			return fmt.Errorf("error setting %s: %w", k, err)
+		}
+	}
+
+	return nil
+}
+
+func resourceStringV2() *schema.Resource {
+	return &schema.Resource{
+		Schema: stringSchemaV2(),
+	}
+}
+
 func resourceStringV1() *schema.Resource {
 	return &schema.Resource{
 		Schema: stringSchemaV1(),
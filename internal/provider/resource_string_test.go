@@ -1,11 +1,18 @@
 package provider
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -36,39 +43,928 @@ func TestAccResourceString(t *testing.T) {
 	})
 }
 
+func TestAccResourceStringImportConfig(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				// With 32 characters every enabled class is all but certain to appear in the result.
+				Config: `resource "random_string" "imported" {
+							length  = 32
+							special = false
+						}`,
+			},
+			{
+				ResourceName:      "random_string.imported",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestImportStringFunc(t *testing.T) {
+	cases := []struct {
+		name     string
+		id       string
+		expected map[string]interface{}
+	}{
+		{
+			name: "every class",
+			id:   "aB3-",
+			expected: map[string]interface{}{
+				"length": 4, "upper": true, "lower": true, "number": true, "numeric": true, "special": true,
+			},
+		},
+		{
+			name: "lowercase only",
+			id:   "abcdef",
+			expected: map[string]interface{}{
+				"length": 6, "upper": false, "lower": true, "number": false, "numeric": false, "special": false,
+			},
+		},
+		{
+			name: "digits and special",
+			id:   "12+34",
+			expected: map[string]interface{}{
+				"length": 5, "upper": false, "lower": false, "number": true, "numeric": true, "special": true,
+			},
+		},
+		{
+			name: "multi-byte characters",
+			id:   "äb€",
+			expected: map[string]interface{}{
+				"length": 3, "length_unit": "runes", "upper": false, "lower": true, "number": false, "numeric": false,
+				"special": true,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, stringSchemaV3(), nil)
+			d.SetId(c.id)
+
+			if _, err := importStringFunc(context.Background(), d, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := d.Get("result"); got != c.id {
+				t.Errorf("expected result: %q, got: %q", c.id, got)
+			}
+			for k, want := range c.expected {
+				if got := d.Get(k); got != want {
+					t.Errorf("expected %s: %v, got: %v", k, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestImportStringFuncResults(t *testing.T) {
+	cases := []struct {
+		name             string
+		id               string
+		expectedResults  []interface{}
+		expectedQuantity int
+		expectedError    string
+	}{
+		{
+			name:            "single result",
+			id:              "abc",
+			expectedResults: []interface{}{},
+		},
+		{
+			name:             "comma-delimited",
+			id:               "results:abc,def,ghi",
+			expectedResults:  []interface{}{"abc", "def", "ghi"},
+			expectedQuantity: 3,
+		},
+		{
+			name:             "newline-delimited",
+			id:               "ab,c\nde,f\n",
+			expectedResults:  []interface{}{"ab,c", "de,f"},
+			expectedQuantity: 2,
+		},
+		{
+			name:             "newline-delimited with keepers",
+			id:               "abc\ndef|keepers:a=1",
+			expectedResults:  []interface{}{"abc", "def"},
+			expectedQuantity: 2,
+		},
+		{
+			name:             "comma-delimited with keepers",
+			id:               "results:abc,def|keepers:a=1",
+			expectedResults:  []interface{}{"abc", "def"},
+			expectedQuantity: 2,
+		},
+		{
+			name:            "single result containing commas",
+			id:              "ab,cde",
+			expectedResults: []interface{}{},
+		},
+		{
+			name:            "single result of equal parts containing commas",
+			id:              "abc,def",
+			expectedResults: []interface{}{},
+		},
+		{
+			name:          "comma-delimited with different lengths",
+			id:            "results:abc,de",
+			expectedError: "the results must all have the same length",
+		},
+		{
+			name:          "comma-delimited with empty result",
+			id:            "results:abc,,def",
+			expectedError: "the results must not be empty",
+		},
+		{
+			name:          "newline-delimited with different lengths",
+			id:            "abc\nde",
+			expectedError: "the results must all have the same length",
+		},
+		{
+			name:          "newline-delimited with empty result",
+			id:            "abc\n\ndef",
+			expectedError: "the results must not be empty",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, stringSchemaV3(), nil)
+			d.SetId(c.id)
+
+			_, err := importStringFunc(context.Background(), d, nil)
+			if c.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), c.expectedError) {
+					t.Fatalf("expected error containing %q, got: %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(c.expectedResults) > 0 && d.Get("result") != c.expectedResults[0] {
+				t.Errorf("expected result: %q, got: %q", c.expectedResults[0], d.Get("result"))
+			}
+			if got := d.Get("results"); !reflect.DeepEqual(got, c.expectedResults) {
+				t.Errorf("expected results: %v, got: %v", c.expectedResults, got)
+			}
+			if got := d.Get("quantity"); got != c.expectedQuantity {
+				t.Errorf("expected quantity: %d, got: %v", c.expectedQuantity, got)
+			}
+		})
+	}
+}
+
+func TestCreateStringQuantity(t *testing.T) {
+	d := resourceString().TestResourceData()
+	for k, v := range map[string]interface{}{
+		"length":   8,
+		"lower":    true,
+		"numeric":  true,
+		"prefix":   "pool-",
+		"seed":     "fixture",
+		"quantity": 5,
+	} {
+		if err := d.Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if diags := createString(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	results := d.Get("results").([]interface{})
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got: %v", results)
+	}
+	if results[0] != d.Get("result") {
+		t.Errorf("expected the first result to be %q, got: %q", d.Get("result"), results[0])
+	}
+
+	seen := map[interface{}]bool{}
+	for _, result := range results {
+		if !regexp.MustCompile(`^pool-[a-z0-9]{8}$`).MatchString(result.(string)) {
+			t.Errorf("expected result %q to be prefixed and of length 8", result)
+		}
+		if seen[result] {
+			t.Errorf("expected distinct results, got %q more than once", result)
+		}
+		seen[result] = true
+	}
+}
+
+func TestImportStringFuncKeepers(t *testing.T) {
+	cases := []struct {
+		name            string
+		id              string
+		expectedResult  string
+		expectedKeepers map[string]interface{}
+		expectedError   string
+	}{
+		{
+			name:            "without keepers",
+			id:              "abc",
+			expectedResult:  "abc",
+			expectedKeepers: map[string]interface{}{},
+		},
+		{
+			name:            "single keeper",
+			id:              "abc|keepers:ami=ami-123",
+			expectedResult:  "abc",
+			expectedKeepers: map[string]interface{}{"ami": "ami-123"},
+		},
+		{
+			name:            "multiple keepers",
+			id:              "abc|keepers:a=1,b=,c=x=y",
+			expectedResult:  "abc",
+			expectedKeepers: map[string]interface{}{"a": "1", "b": "", "c": "x=y"},
+		},
+		{
+			name:            "result containing separator",
+			id:              "a|b|keepers:c=1",
+			expectedResult:  "a|b",
+			expectedKeepers: map[string]interface{}{"c": "1"},
+		},
+		{
+			name:            "result containing | without keepers",
+			id:              "a|b",
+			expectedResult:  "a|b",
+			expectedKeepers: map[string]interface{}{},
+		},
+		{
+			name:            "result containing | and =",
+			id:              "ab|c=d",
+			expectedResult:  "ab|c=d",
+			expectedKeepers: map[string]interface{}{},
+		},
+		{
+			name:          "empty keepers",
+			id:            "abc|keepers:",
+			expectedError: "expected keepers of the form key1=val1,key2=val2 after |keepers:",
+		},
+		{
+			name:          "keeper without value",
+			id:            "abc|keepers:a=1,b",
+			expectedError: `expected keeper of the form key=value, got: "b"`,
+		},
+		{
+			name:          "keeper without key",
+			id:            "abc|keepers:=1",
+			expectedError: `expected keeper of the form key=value, got: "=1"`,
+		},
+		{
+			name:          "duplicate keeper",
+			id:            "abc|keepers:a=1,a=2",
+			expectedError: `keeper "a" is given more than once`,
+		},
+		{
+			name:          "empty result",
+			id:            "|keepers:a=1",
+			expectedError: "the result must not be empty",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, stringSchemaV3(), nil)
+			d.SetId(c.id)
+
+			_, err := importStringFunc(context.Background(), d, nil)
+			if c.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), c.expectedError) {
+					t.Fatalf("expected error containing %q, got: %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if d.Id() != c.expectedResult {
+				t.Errorf("expected id: %q, got: %q", c.expectedResult, d.Id())
+			}
+			if got := d.Get("result"); got != c.expectedResult {
+				t.Errorf("expected result: %q, got: %q", c.expectedResult, got)
+			}
+			if got := d.Get("keepers"); !reflect.DeepEqual(got, c.expectedKeepers) {
+				t.Errorf("expected keepers: %v, got: %v", c.expectedKeepers, got)
+			}
+		})
+	}
+}
+
+func TestAccResourceStringImportKeepers(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "random_string" "keepers" {
+							length  = 32
+							special = false
+							keepers = {
+								ami  = "ami-123"
+								zone = "a"
+							}
+						}`,
+			},
+			{
+				ResourceName: "random_string.keepers",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["random_string.keepers"]
+					if !ok {
+						return "", fmt.Errorf("resource not found: random_string.keepers")
+					}
+					return rs.Primary.Attributes["result"] + "|keepers:ami=ami-123,zone=a", nil
+				},
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func TestAccResourceStringOverride(t *testing.T) {
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourceStringOverride,
+				Config: testAccResourceStringOverride,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceStringCheck("random_string.override", &customLens{
+						customLen: 4,
+					}),
+					patternMatch("random_string.override", "!!!!"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringOverrideSpecialEmpty(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringOverrideSpecialEmpty,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_string.empty", "result", regexp.MustCompile(`^[0-9]{8}$`)),
+				),
+			},
+			{
+				Config:      testAccResourceStringOverrideSpecialEmptyMinSpecial,
+				ExpectError: regexp.MustCompile(`min_special \(1\) cannot be satisfied when override_special is an empty string`),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringSpecialSet(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringSpecialSet,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_string.set", "result", regexp.MustCompile(`^[!#@]{8}$`)),
+				),
+			},
+			{
+				Config:      testAccResourceStringSpecialSetMultipleChars,
+				ExpectError: regexp.MustCompile(`Expected a single character`),
+			},
+			{
+				Config:      testAccResourceStringSpecialSetEmpty,
+				ExpectError: regexp.MustCompile(`Not enough list items`),
+			},
+			{
+				Config:      testAccResourceStringSpecialSetOverrideSpecial,
+				ExpectError: regexp.MustCompile(`"special_set": conflicts with override_special`),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringMin(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringMin,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceStringCheck("random_string.min", &customLens{
+						customLen: 12,
+					}),
+					regexMatch("random_string.min", regexp.MustCompile(`([a-z])`), 2),
+					regexMatch("random_string.min", regexp.MustCompile(`([A-Z])`), 3),
+					regexMatch("random_string.min", regexp.MustCompile(`([0-9])`), 4),
+					regexMatch("random_string.min", regexp.MustCompile(`([!#@])`), 1),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringMinEqualToLength(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringMinEqualToLength,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceStringCheck("random_string.min", &customLens{
+						customLen: 10,
+					}),
+					regexMatch("random_string.min", regexp.MustCompile(`([a-z])`), 2),
+					regexMatch("random_string.min", regexp.MustCompile(`([A-Z])`), 3),
+					regexMatch("random_string.min", regexp.MustCompile(`([0-9])`), 4),
+					regexMatch("random_string.min", regexp.MustCompile(`([!#@])`), 1),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringMinPct(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringMinPct,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceStringCheck("random_string.min_pct", &customLens{
+						customLen: 40,
+					}),
+					regexMatch("random_string.min_pct", regexp.MustCompile(`([0-9])`), 8),
+					regexMatch("random_string.min_pct", regexp.MustCompile(`([!#@])`), 4),
+				),
+			},
+			{
+				Config: `resource "random_string" "invalid" {
+							length          = 10
+							min_upper       = 5
+							min_numeric_pct = 0.55
+						}`,
+				ExpectError: regexp.MustCompile(`.*length \(10\) must be >= min_upper \+ min_lower \+ min_numeric \+ min_special \(11\)`),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringRequireEachClass(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringRequireEachClass,
+				Check: resource.ComposeTestCheckFunc(
+					regexMatch("random_string.each_class", regexp.MustCompile(`([a-z])`), 1),
+					regexMatch("random_string.each_class", regexp.MustCompile(`([A-Z])`), 1),
+					regexMatch("random_string.each_class", regexp.MustCompile(`([0-9])`), 1),
+					resource.TestMatchResourceAttr("random_string.each_class", "result", regexp.MustCompile(`^[a-zA-Z0-9]{3}$`)),
+				),
+			},
+			{
+				Config: `resource "random_string" "invalid" {
+							length             = 3
+							require_each_class = true
+						}`,
+				ExpectError: regexp.MustCompile(`.*length \(3\) must be >= min_upper \+ min_lower \+ min_numeric \+ min_special \(4\)`),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringAppendLuhn(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringAppendLuhn,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_string.account", "result", regexp.MustCompile(`^acct-[0-9]{16}$`)),
+					resource.TestCheckResourceAttrWith("random_string.account", "result", func(value string) error {
+						if !luhnValid(strings.TrimPrefix(value, "acct-"), numChars) {
+							return fmt.Errorf("result %q does not pass a Luhn check", value)
+						}
+						return nil
+					}),
+					resource.TestMatchResourceAttr("random_string.account", "check_digit", regexp.MustCompile(`^[0-9]$`)),
+				),
+			},
+			{
+				Config: `resource "random_string" "invalid" {
+							length      = 15
+							append_luhn = true
+						}`,
+				ExpectError: regexp.MustCompile(`.*append_luhn requires the enabled characters to be digits only`),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringNoPalindrome(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringNoPalindrome,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceStringCheck("random_string.no_palindrome", &customLens{
+						customLen: 3,
+					}),
+					resource.TestCheckResourceAttrWith("random_string.no_palindrome", "result", func(value string) error {
+						if isPalindrome(splitBytes(value)) {
+							return fmt.Errorf("result %q is a palindrome", value)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config:      testAccResourceStringNoPalindromeLengthTooShort,
+				ExpectError: regexp.MustCompile(`.*length \(1\) must be >= 2 when no_palindrome is true`),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringPrefixSuffix(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringPrefixSuffix,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_string.affixed", "result", regexp.MustCompile(`^svc-[a-z]{8}-v2$`)),
+					resource.TestMatchResourceAttr("random_string.affixed", "id", regexp.MustCompile(`^svc-[a-z]{8}-v2$`)),
+					resource.TestCheckResourceAttr("random_string.affixed", "result_length", "15"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringCase(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringCase,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_string.lower", "result", regexp.MustCompile(`^[^A-Z]{16}$`)),
+					resource.TestMatchResourceAttr("random_string.upper", "result", regexp.MustCompile(`^ID-[^a-z]{16}$`)),
+				),
+			},
+			{
+				Config: `resource "random_string" "invalid" {
+							length    = 16
+							case      = "lower"
+							min_upper = 1
+						}`,
+				ExpectError: regexp.MustCompile(`.*min_upper \(1\) cannot be satisfied when case is lower`),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringGrammar(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringGrammar,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_string.sku", "result", regexp.MustCompile(`^(HW|SW)-[0-9]{3}$`)),
+				),
+			},
+			{
+				Config:      testAccResourceStringGrammarRecursive,
+				ExpectError: regexp.MustCompile(`.*rule <sku> is recursive`),
+			},
+			{
+				Config:      testAccResourceStringGrammarWithLength,
+				ExpectError: regexp.MustCompile(`.*only one of .grammar,length. can be specified`),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringNoConsecutiveDuplicates(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringNoConsecutiveDuplicates,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith("random_string.no_duplicates", "result", func(value string) error {
+						for i := 1; i < len(value); i++ {
+							if value[i] == value[i-1] {
+								return fmt.Errorf("result %q has consecutive duplicates at position %d", value, i)
+							}
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config:      testAccResourceStringNoConsecutiveDuplicatesSingleChar,
+				ExpectError: regexp.MustCompile(`.*at least 2 distinct characters are required`),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringQuantity(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "random_string" "pool" {
+							length   = 12
+							quantity = 3
+						}`,
 				Check: resource.ComposeTestCheckFunc(
-					testAccResourceStringCheck("random_string.override", &customLens{
-						customLen: 4,
+					resource.TestCheckResourceAttr("random_string.pool", "results.#", "3"),
+					resource.TestCheckResourceAttrPair("random_string.pool", "results.0", "random_string.pool", "result"),
+					resource.TestCheckResourceAttrWith("random_string.pool", "results.2", testCheckLen(12)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringSensitive(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "random_string" "sensitive" {
+							length    = 12
+							sensitive = true
+						}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("random_string.sensitive", "id", "none"),
+					resource.TestCheckResourceAttr("random_string.sensitive", "result", ""),
+					resource.TestCheckResourceAttr("random_string.sensitive", "result_length", "12"),
+					resource.TestCheckResourceAttrWith("random_string.sensitive", "sensitive_result", testCheckLen(12)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringSeed(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringSeed,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("random_string.seeded_a", "result", "random_string.seeded_b", "result"),
+					resource.TestMatchResourceAttr("random_string.seeded_a", "result", regexp.MustCompile(`^([^0-9]*[0-9]){4}`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringPattern(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringPattern,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_string.license", "result", regexp.MustCompile(`^[A-Z]{3}-[0-9]{3}-[a-z]{2}-[a-z0-9]-A$`)),
+				),
+			},
+			{
+				Config:      testAccResourceStringPatternWithMin,
+				ExpectError: regexp.MustCompile(`.*"pattern": conflicts with min_numeric`),
+			},
+			{
+				Config:      testAccResourceStringPatternWithLength,
+				ExpectError: regexp.MustCompile(`.*only one of .grammar,length,pattern. can be specified`),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringRegex(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringRegex,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_string.sku", "result", regexp.MustCompile(`^[A-Z]{3}[0-9]{4}-?[a-f0-9]{2,4}$`)),
+				),
+			},
+			{
+				Config:      testAccResourceStringRegexUnbounded,
+				ExpectError: regexp.MustCompile(`unbounded quantifier \+ is not supported`),
+			},
+			{
+				Config:      testAccResourceStringRegexWithPattern,
+				ExpectError: regexp.MustCompile(`only one of .* can be specified, but .pattern,regex. were specified`),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringBIP39(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringBIP39,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith("random_string.mnemonic", "result", func(value string) error {
+						return validateBIP39Mnemonic(value, 24)
 					}),
-					patternMatch("random_string.override", "!!!!"),
 				),
 			},
+			{
+				Config:      testAccResourceStringBIP39InvalidWordCount,
+				ExpectError: regexp.MustCompile(`.*expected bip39_word_count to be one of \[12 15 18 21 24\], got 13`),
+			},
 		},
 	})
 }
 
-func TestAccResourceStringMin(t *testing.T) {
+func TestAccResourceStringOverlappingOverrideSpecial(t *testing.T) {
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourceStringMin,
+				Config: testAccResourceStringOverlappingOverrideSpecial,
 				Check: resource.ComposeTestCheckFunc(
-					testAccResourceStringCheck("random_string.min", &customLens{
-						customLen: 12,
+					resource.TestMatchResourceAttr("random_string.overlap", "result", regexp.MustCompile(`^(.*[0-9]){2}`)),
+					resource.TestMatchResourceAttr("random_string.overlap", "result", regexp.MustCompile(`^(.*[abc123]){2}`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringResultEncodings(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringResultEncodings,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceStringResultEncodingsCheck("random_string.encoded"),
+				),
+			},
+			{
+				ResourceName:            "random_string.encoded",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"length", "lower", "number", "numeric", "special", "upper", "min_lower", "min_numeric", "min_special", "min_upper", "override_special"},
+			},
+		},
+	})
+}
+
+func TestAccResourceStringLengthUnit(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringLengthUnit,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith("random_string.runes", "result", func(value string) error {
+						if n := utf8.RuneCountInString(value); n != 5 {
+							return fmt.Errorf("result %q contains %d runes; want 5", value, n)
+						}
+						return nil
 					}),
-					regexMatch("random_string.min", regexp.MustCompile(`([a-z])`), 2),
-					regexMatch("random_string.min", regexp.MustCompile(`([A-Z])`), 3),
-					regexMatch("random_string.min", regexp.MustCompile(`([0-9])`), 4),
-					regexMatch("random_string.min", regexp.MustCompile(`([!#@])`), 1),
+					resource.TestCheckResourceAttrWith("random_string.graphemes", "result", func(value string) error {
+						if n := len(splitGraphemes(value)); n != 5 {
+							return fmt.Errorf("result %q contains %d graphemes; want 5", value, n)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringMaxLength(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceStringMaxLengthExceeded,
+				ExpectError: regexp.MustCompile(`.*length \(1025\) must be <= max_string_length \(1024\)`),
+			},
+			{
+				Config: testAccResourceStringMaxLengthRaised,
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceStringCheck("random_string.long", &customLens{
+						customLen: 2048,
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringDefaultSpecialOverride(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceStringDefaultSpecialOverride,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_string.provider_default", "result", regexp.MustCompile(`^[-_]{12}$`)),
+					resource.TestMatchResourceAttr("random_string.override", "result", regexp.MustCompile(`^#{12}$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceStringKeepersUpdate(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: testAccKeepersUpdateSteps("random_string.keepers", `resource "random_string" "keepers" {
+							length                   = 12
+							keepers                  = %s
+							replace_on_keeper_change = false
+						}`, "result"),
+	})
+}
+
+func TestAccResourceString_NumberNumericCombinations(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "random_string" "both" {
+							length  = 12
+							number  = false
+							numeric = false
+						}`,
+				ExpectError: regexp.MustCompile(`"number": conflicts with numeric`),
+			},
+			{
+				Config: `resource "random_string" "number" {
+							length = 12
+							number = false
+						}
+
+						resource "random_string" "numeric" {
+							length  = 12
+							numeric = false
+						}
+
+						resource "random_string" "neither" {
+							length = 12
+						}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("random_string.number", "number", "false"),
+					resource.TestCheckResourceAttr("random_string.number", "numeric", "false"),
+					resource.TestCheckResourceAttr("random_string.numeric", "number", "false"),
+					resource.TestCheckResourceAttr("random_string.numeric", "numeric", "false"),
+					resource.TestCheckResourceAttr("random_string.neither", "number", "true"),
+					resource.TestCheckResourceAttr("random_string.neither", "numeric", "true"),
 				),
 			},
 		},
@@ -363,6 +1259,51 @@ const (
 resource "random_string" "basic" {
   length = 12
 }`
+	testAccResourceStringOverrideSpecialEmpty = `
+resource "random_string" "empty" {
+  length           = 8
+  upper            = false
+  lower            = false
+  override_special = ""
+}
+`
+
+	testAccResourceStringOverrideSpecialEmptyMinSpecial = `
+resource "random_string" "empty" {
+  length           = 8
+  override_special = ""
+  min_special      = 1
+}
+`
+
+	testAccResourceStringSpecialSet = `
+resource "random_string" "set" {
+  length      = 8
+  upper       = false
+  lower       = false
+  numeric     = false
+  special_set = ["!", "#", "@"]
+}`
+
+	testAccResourceStringSpecialSetMultipleChars = `
+resource "random_string" "set" {
+  length      = 8
+  special_set = ["!", "#@"]
+}`
+
+	testAccResourceStringSpecialSetEmpty = `
+resource "random_string" "set" {
+  length      = 8
+  special_set = []
+}`
+
+	testAccResourceStringSpecialSetOverrideSpecial = `
+resource "random_string" "set" {
+  length           = 8
+  override_special = "!"
+  special_set      = ["#"]
+}`
+
 	testAccResourceStringOverride = `
 resource "random_string" "override" {
 length = 4
@@ -380,6 +1321,222 @@ min_lower = 2
 min_upper = 3
 min_special = 1
 min_numeric = 4
+}`
+	testAccResourceStringMinEqualToLength = `
+resource "random_string" "min" {
+length = 10
+override_special = "!#@"
+min_lower = 2
+min_upper = 3
+min_special = 1
+min_numeric = 4
+}`
+	testAccResourceStringMinPct = `
+resource "random_string" "min_pct" {
+length = 40
+override_special = "!#@"
+min_numeric_pct = 0.2
+min_special_pct = 0.1
+}`
+	testAccResourceStringRequireEachClass = `
+resource "random_string" "each_class" {
+length = 3
+special = false
+require_each_class = true
+}`
+	testAccResourceStringAppendLuhn = `
+resource "random_string" "account" {
+length = 15
+upper = false
+lower = false
+special = false
+prefix = "acct-"
+append_luhn = true
+}`
+	testAccResourceStringNoPalindrome = `
+resource "random_string" "no_palindrome" {
+length = 3
+override_special = "!?"
+lower = false
+upper = false
+number = false
+no_palindrome = true
+}`
+	testAccResourceStringNoPalindromeLengthTooShort = `
+resource "random_string" "no_palindrome" {
+length = 1
+no_palindrome = true
+}`
+	testAccResourceStringPrefixSuffix = `
+resource "random_string" "affixed" {
+  length  = 8
+  upper   = false
+  numeric = false
+  special = false
+  prefix  = "svc-"
+  suffix  = "-v2"
+}`
+	testAccResourceStringGrammar = `
+resource "random_string" "sku" {
+  grammar = <<-EOT
+    <sku> ::= <category> "-" <digit> <digit> <digit>
+    <category> ::= "HW" | "SW"
+    <digit> ::= "0" | "1" | "2" | "3" | "4" | "5" | "6" | "7" | "8" | "9"
+  EOT
+}`
+	testAccResourceStringGrammarRecursive = `
+resource "random_string" "sku" {
+  grammar = <<-EOT
+    <sku> ::= "A" | "A" <sku>
+  EOT
+}`
+	testAccResourceStringGrammarWithLength = `
+resource "random_string" "sku" {
+  length  = 4
+  grammar = <<-EOT
+    <sku> ::= "A"
+  EOT
+}`
+	testAccResourceStringNoConsecutiveDuplicates = `
+resource "random_string" "no_duplicates" {
+  length                    = 32
+  upper                     = false
+  lower                     = false
+  numeric                   = false
+  override_special          = "!@"
+  no_consecutive_duplicates = true
+}`
+	testAccResourceStringNoConsecutiveDuplicatesSingleChar = `
+resource "random_string" "no_duplicates" {
+  length                    = 32
+  upper                     = false
+  lower                     = false
+  numeric                   = false
+  override_special          = "!"
+  no_consecutive_duplicates = true
+}`
+	testAccResourceStringSeed = `
+resource "random_string" "seeded_a" {
+  length      = 16
+  min_numeric = 4
+  seed        = "fixture"
+}
+
+resource "random_string" "seeded_b" {
+  length      = 16
+  min_numeric = 4
+  seed        = "fixture"
+}`
+	testAccResourceStringPattern = `
+resource "random_string" "license" {
+  pattern = "AAA-999-aa-*-\\A"
+  upper   = false
+  special = false
+}`
+	testAccResourceStringPatternWithMin = `
+resource "random_string" "license" {
+  pattern     = "AAA-999-aa"
+  min_numeric = 1
+}`
+	testAccResourceStringPatternWithLength = `
+resource "random_string" "license" {
+  pattern = "AAA-999-aa"
+  length  = 10
+}`
+	testAccResourceStringRegex = `
+resource "random_string" "sku" {
+  regex = "[A-Z]{3}\\d{4}-?[a-f0-9]{2,4}"
+}`
+	testAccResourceStringRegexUnbounded = `
+resource "random_string" "sku" {
+  regex = "[A-Z]+"
+}`
+	testAccResourceStringRegexWithPattern = `
+resource "random_string" "sku" {
+  regex   = "[A-Z]{3}"
+  pattern = "AAA"
+}`
+	testAccResourceStringBIP39 = `
+resource "random_string" "mnemonic" {
+  bip39_word_count = 24
+}`
+	testAccResourceStringBIP39InvalidWordCount = `
+resource "random_string" "mnemonic" {
+  bip39_word_count = 13
+}`
+	testAccResourceStringOverlappingOverrideSpecial = `
+resource "random_string" "overlap" {
+  length           = 4
+  upper            = false
+  lower            = false
+  override_special = "abc123"
+  min_numeric      = 2
+  min_special      = 2
+}`
+	testAccResourceStringLengthUnit = `
+resource "random_string" "runes" {
+  length           = 5
+  length_unit      = "runes"
+  upper            = false
+  lower            = false
+  numeric          = false
+  override_special = "äöü€"
+}
+
+resource "random_string" "graphemes" {
+  length           = 5
+  length_unit      = "graphemes"
+  upper            = false
+  numeric          = false
+  override_special = "\u00e9\U0001F44D\U0001F3FD\U0001F1FA\U0001F1F8"
+}`
+	testAccResourceStringMaxLengthExceeded = `
+resource "random_string" "long" {
+  length = 1025
+}`
+	testAccResourceStringMaxLengthRaised = `
+provider "random" {
+  max_string_length = 2048
+}
+
+resource "random_string" "long" {
+  length = 2048
+}`
+	testAccResourceStringCase = `
+resource "random_string" "lower" {
+  length      = 16
+  min_numeric = 2
+  min_special = 2
+  case        = "lower"
+}
+
+resource "random_string" "upper" {
+  length = 16
+  prefix = "id-"
+  case   = "upper"
+}`
+	testAccResourceStringDefaultSpecialOverride = `
+provider "random" {
+  default_special_override = "-_"
+}
+
+resource "random_string" "provider_default" {
+  length  = 12
+  upper   = false
+  lower   = false
+  numeric = false
+}
+
+resource "random_string" "override" {
+  length           = 12
+  upper            = false
+  lower            = false
+  numeric          = false
+  override_special = "#"
+}`
+	testAccResourceStringResultEncodings = `
+resource "random_string" "encoded" {
+  length = 12
 }`
 	testAccResourceStringInvalidConfig = `
 resource "random_string" "invalid_length" {
@@ -392,6 +1549,28 @@ resource "random_string" "invalid_length" {
 }`
 )
 
+// testAccResourceStringResultEncodingsCheck verifies that result_base64 and
+// result_hex of the named resource are encodings of its result.
+func testAccResourceStringResultEncodingsCheck(id string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("Not found: %s", id)
+		}
+
+		result := []byte(rs.Primary.Attributes["result"])
+
+		if got, want := rs.Primary.Attributes["result_base64"], base64.StdEncoding.EncodeToString(result); got != want {
+			return fmt.Errorf("result_base64 is %q; want %q", got, want)
+		}
+		if got, want := rs.Primary.Attributes["result_hex"], hex.EncodeToString(result); got != want {
+			return fmt.Errorf("result_hex is %q; want %q", got, want)
+		}
+
+		return nil
+	}
+}
+
 func testAccResourceStringCheck(id string, want *customLens) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[id]
@@ -2,13 +2,27 @@ package provider
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/binary"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+const (
+	uuidFormatStandard  = "standard"
+	uuidFormatUppercase = "uppercase"
+	uuidFormatCompact   = "compact"
+)
+
+var uuidFormats = []string{uuidFormatStandard, uuidFormatUppercase, uuidFormatCompact}
+
 func resourceUuid() *schema.Resource {
 	return &schema.Resource{
 		Description: "The resource `random_uuid` generates random uuid string that is intended to be " +
@@ -17,32 +31,142 @@ func resourceUuid() *schema.Resource {
 			"This resource uses [hashicorp/go-uuid](https://github.com/hashicorp/go-uuid) to generate a " +
 			"UUID-formatted string for use with services needed a unique string identifier.",
 		CreateContext: CreateUuid,
-		ReadContext:   schema.NoopContext,
+		ReadContext:   ReadUuid,
+		UpdateContext: updateOnKeepersChange(CreateUuid),
 		DeleteContext: RemoveResourceFromState,
 		Importer: &schema.ResourceImporter{
 			StateContext: ImportUuid,
 		},
+		CustomizeDiff: customdiff.All(
+			logKeepersChange,
+			planKeepersChange("result", "id"),
+			planGeneration,
+			isUUIDNamespaceAndName("namespace", "name"),
+		),
 
-		Schema: map[string]*schema.Schema{
-			"keepers": {
-				Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
-					"resource. See [the main provider documentation](../index.html) for more information.",
-				Type:     schema.TypeMap,
-				Optional: true,
-				ForceNew: true,
+		Schema:        uuidSchemaV1(),
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceUuidV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: generationStateUpgrade,
 			},
+		},
+	}
+}
 
-			"result": {
-				Description: "The generated uuid presented in string format.",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
+func resourceUuidV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: uuidSchemaV0(),
+	}
+}
 
-			"id": {
-				Description: "The generated uuid presented in string format.",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
+// uuidSchemaV1 uses uuidSchemaV0 to obtain the V0 version of the Schema key-value entries but requires that the
+// generation entry be configured.
+func uuidSchemaV1() map[string]*schema.Schema {
+	uuidSchema := uuidSchemaV0()
+	uuidSchema["generation"] = generationSchema(true)
+
+	return uuidSchema
+}
+
+// uuidSchemaV0 returns the Schema key-value entries of random_uuid before the generation entry was added.
+func uuidSchemaV0() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"keepers": {
+			Description: "Arbitrary map of values that, when changed, will trigger recreation of " +
+				"resource, or generation of a new uuid in place when `replace_on_keeper_change` is `false`. " +
+				"See [the main provider documentation](../index.html) for more information.",
+			Type:     schema.TypeMap,
+			Optional: true,
+		},
+
+		"replace_on_keeper_change": replaceOnKeeperChangeSchema(),
+
+		"detect_drift": detectDriftSchema(),
+
+		"comb": {
+			Description: "Generate a COMB (combined GUID/timestamp) uuid suitable for use as a clustered index " +
+				"key in Microsoft SQL Server. SQL Server orders `uniqueidentifier` values by their last six " +
+				"bytes first, so these are set to the number of milliseconds since the Unix epoch, big-endian, " +
+				"with the remaining bytes random. Successive results therefore sort in creation order, avoiding " +
+				"the index fragmentation caused by fully random values. Default value is `false`.",
+			Type:          schema.TypeBool,
+			Optional:      true,
+			ForceNew:      true,
+			ConflictsWith: []string{"namespace", "name", "version"},
+		},
+
+		"version": {
+			Description: "The version of random uuid to generate, either `4` or `7`. Version 7 uuids begin with " +
+				"the number of milliseconds since the Unix epoch, big-endian, followed by random bits, so " +
+				"successive results sort lexicographically in creation order, e.g., for database primary " +
+				"keys. Default value is `4`.\n" +
+				"\n" +
+				"**Important:** A version 7 uuid reveals the time at which it was created to anyone who can " +
+				"see it.",
+			Type:             schema.TypeInt,
+			Optional:         true,
+			ForceNew:         true,
+			ConflictsWith:    []string{"comb", "namespace", "name"},
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntInSlice([]int{4, 7})),
+		},
+
+		"namespace": {
+			Description: "A uuid identifying the namespace of `name`, e.g., " +
+				"`6ba7b810-9dad-11d1-80b4-00c04fd430c8` for DNS names. When `namespace` and `name` are both set, " +
+				"the result is a name-based (version 5) uuid computed from the SHA-1 hash of the namespace and " +
+				"name, so the same namespace and name always produce the same result. `namespace` and `name` must be set " +
+				"together, and neither may be empty.",
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+		},
+
+		"name": {
+			Description: "The name from which to compute a name-based (version 5) uuid within `namespace`.",
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+		},
+
+		"format": {
+			Description: "The format of `result`, one of `standard` (lowercase with hyphens, e.g., " +
+				"`aabbccdd-eeff-0011-2233-445566778899`), `uppercase` (uppercase with hyphens) or `compact` " +
+				"(lowercase without hyphens). `id` always uses the `standard` format. Default value is " +
+				"`standard`.",
+			Type:             schema.TypeString,
+			Optional:         true,
+			ForceNew:         true,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(uuidFormats, false)),
+		},
+
+		"prefix": {
+			Description: "A string to prepend to `result`, e.g., `order-`. `id` is always the uuid alone.",
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+		},
+
+		"suffix": {
+			Description: "A string to append to `result`. `id` is always the uuid alone.",
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+		},
+
+		"result": {
+			Description: "The generated uuid presented in string format, in the given `format` and with any " +
+				"`prefix` and `suffix`.",
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+
+		"id": {
+			Description: "The generated uuid presented in string format.",
+			Type:        schema.TypeString,
+			Computed:    true,
 		},
 	}
 }
@@ -50,24 +174,75 @@ func resourceUuid() *schema.Resource {
 func CreateUuid(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	result, err := uuid.GenerateUUID()
+	var result string
+	var err error
+	namespace, hasNamespace := d.GetOk("namespace")
+	name, hasName := d.GetOk("name")
+
+	switch {
+	case hasNamespace && hasName:
+		result, err = generateNameBasedUUID(namespace.(string), name.(string))
+	case d.Get("comb").(bool):
+		result, err = generateCombUUID(time.Now())
+	case d.Get("version").(int) == 7:
+		result, err = generateV7UUID(time.Now())
+	default:
+		result, err = generateRandomUUID(randReader)
+	}
 	if err != nil {
 		return append(diags, diag.Errorf("error generating uuid: %s", err)...)
 	}
 
-	if err := d.Set("result", result); err != nil {
+	if err := d.Set("result", uuidResult(d, result)); err != nil {
 		return append(diags, diag.Errorf("error setting result: %s", err)...)
 	}
 
+	if err := setGeneration(d); err != nil {
+		return append(diags, diag.Errorf("error setting generation: %s", err)...)
+	}
+
 	d.SetId(result)
 
 	return nil
 }
 
+// ReadUuid does nothing unless detect_drift is true, in which case the id and result held in state must still be the
+// same valid uuid, otherwise the resource is removed from state so that it is recreated.
+func ReadUuid(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if !d.Get("detect_drift").(bool) {
+		return nil
+	}
+
+	if _, err := uuid.ParseUUID(d.Id()); err != nil {
+		return removeOnDrift(d, fmt.Errorf("error parsing ID: %w", err))
+	}
+
+	result := d.Get("result").(string)
+	if _, err := parseUUIDInput(trimUUIDAffixes(d, result)); err != nil {
+		return removeOnDrift(d, fmt.Errorf("error parsing result: %w", err))
+	}
+	if expected := uuidResult(d, d.Id()); result != expected {
+		return removeOnDrift(d, fmt.Errorf("result %q does not match ID, expected %q", result, expected))
+	}
+
+	return nil
+}
+
 func ImportUuid(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	id := d.Id()
+	parts := strings.Split(d.Id(), ",")
+	if len(parts) > 4 {
+		return nil, fmt.Errorf("invalid import format, expected uuid[,format[,prefix[,suffix]]], got: %s", d.Id())
+	}
 
-	bytes, err := uuid.ParseUUID(id)
+	for i, k := range []string{"prefix", "suffix"} {
+		if len(parts) > i+2 && parts[i+2] != "" {
+			if err := d.Set(k, parts[i+2]); err != nil {
+				return nil, fmt.Errorf("error setting %s: %w", k, err)
+			}
+		}
+	}
+
+	bytes, err := parseUUIDInput(trimUUIDAffixes(d, parts[0]))
 	if err != nil {
 		return nil, fmt.Errorf("error parsing uuid bytes: %w", err)
 	}
@@ -77,11 +252,155 @@ func ImportUuid(_ context.Context, d *schema.ResourceData, meta interface{}) ([]
 		return nil, fmt.Errorf("error formatting uuid bytes: %w", err)
 	}
 
-	if err := d.Set("result", result); err != nil {
+	// An empty format, e.g., uuid,,prefix, leaves format unset.
+	if len(parts) >= 2 && parts[1] != "" {
+		format := parts[1]
+		switch format {
+		case uuidFormatStandard, uuidFormatUppercase, uuidFormatCompact:
+		default:
+			return nil, fmt.Errorf("invalid format %q, expected one of %s", format, strings.Join(uuidFormats, ", "))
+		}
+
+		if err := d.Set("format", format); err != nil {
+			return nil, fmt.Errorf("error setting format: %w", err)
+		}
+	}
+
+	if err := d.Set("result", uuidResult(d, result)); err != nil {
 		return nil, fmt.Errorf("error setting result: %w", err)
 	}
 
+	if err := d.Set("generation", 1); err != nil {
+		return nil, fmt.Errorf("error setting generation: %w", err)
+	}
+
 	d.SetId(result)
 
 	return []*schema.ResourceData{d}, nil
 }
+
+// parseUUIDInput parses a uuid in any of the formats accepted by the format argument, i.e., with or without hyphens
+// and in either case.
+func parseUUIDInput(s string) ([]byte, error) {
+	if len(s) == 32 {
+		s = fmt.Sprintf("%s-%s-%s-%s-%s", s[0:8], s[8:12], s[12:16], s[16:20], s[20:32])
+	}
+
+	return uuid.ParseUUID(strings.ToLower(s))
+}
+
+// formatUUIDResult returns the canonical uuid presented in the given format, where an empty format is treated as
+// standard.
+func formatUUIDResult(canonical, format string) string {
+	switch format {
+	case uuidFormatUppercase:
+		return strings.ToUpper(canonical)
+	case uuidFormatCompact:
+		return strings.ReplaceAll(canonical, "-", "")
+	default:
+		return canonical
+	}
+}
+
+// isUUIDNamespaceAndName returns a CustomizeDiffFunc that ensures namespaceKey and nameKey are either both set or
+// both not set, so that a name-based uuid is never half configured, and that namespaceKey is a valid uuid. An empty
+// string is treated as not set, as it is by CreateUuid.
+func isUUIDNamespaceAndName(namespaceKey, nameKey string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+		if !d.NewValueKnown(namespaceKey) || !d.NewValueKnown(nameKey) {
+			return nil
+		}
+
+		namespace, hasNamespace := d.GetOk(namespaceKey)
+		_, hasName := d.GetOk(nameKey)
+
+		switch {
+		case hasNamespace && !hasName:
+			return fmt.Errorf("%s must be set when %s is set", nameKey, namespaceKey)
+		case hasName && !hasNamespace:
+			return fmt.Errorf("%s must be set when %s is set", namespaceKey, nameKey)
+		case !hasNamespace:
+			return nil
+		}
+
+		if _, err := uuid.ParseUUID(namespace.(string)); err != nil {
+			return fmt.Errorf("expected %s to be a valid uuid, got %q: %w", namespaceKey, namespace, err)
+		}
+
+		return nil
+	}
+}
+
+// uuidResult returns the canonical uuid presented in the format given by d, with the prefix and suffix given by d.
+func uuidResult(d *schema.ResourceData, canonical string) string {
+	return d.Get("prefix").(string) + formatUUIDResult(canonical, d.Get("format").(string)) + d.Get("suffix").(string)
+}
+
+// trimUUIDAffixes returns s without the prefix and suffix given by d, where present.
+func trimUUIDAffixes(d *schema.ResourceData, s string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(s, d.Get("prefix").(string)), d.Get("suffix").(string))
+}
+
+// generateRandomUUIDBytes returns the 16 random bytes from which a uuid is built, read from randReader and retrying
+// as described by retryRandom.
+func generateRandomUUIDBytes() ([]byte, error) {
+	var bytes []byte
+	err := retryRandom(func() (err error) {
+		bytes, err = uuid.GenerateRandomBytesWithReader(16, randReader)
+		return err
+	})
+
+	return bytes, err
+}
+
+// generateCombUUID returns a random uuid whose last six bytes, which SQL Server compares first when ordering
+// uniqueidentifier values, hold the number of milliseconds between the Unix epoch and now.
+func generateCombUUID(now time.Time) (string, error) {
+	bytes, err := generateRandomUUIDBytes()
+	if err != nil {
+		return "", err
+	}
+
+	var timestamp [8]byte
+	binary.BigEndian.PutUint64(timestamp[:], uint64(now.UnixMilli()))
+	copy(bytes[10:], timestamp[2:])
+
+	return uuid.FormatUUID(bytes)
+}
+
+// generateV7UUID returns a version 7 uuid, as described in RFC 9562 section 5.7, whose first six bytes hold the number
+// of milliseconds between the Unix epoch and now and whose remaining bits, other than the version and variant, are
+// random.
+func generateV7UUID(now time.Time) (string, error) {
+	bytes, err := generateRandomUUIDBytes()
+	if err != nil {
+		return "", err
+	}
+
+	var timestamp [8]byte
+	binary.BigEndian.PutUint64(timestamp[:], uint64(now.UnixMilli()))
+	copy(bytes[:6], timestamp[2:])
+
+	bytes[6] = bytes[6]&0x0f | 0x70 // version 7
+	bytes[8] = bytes[8]&0x3f | 0x80 // RFC 4122 variant
+
+	return uuid.FormatUUID(bytes)
+}
+
+// generateNameBasedUUID returns the version 5 uuid for name within namespace, as described in RFC 4122 section 4.3.
+func generateNameBasedUUID(namespace, name string) (string, error) {
+	namespaceBytes, err := uuid.ParseUUID(namespace)
+	if err != nil {
+		return "", fmt.Errorf("error parsing namespace %q: %w", namespace, err)
+	}
+
+	h := sha1.New()
+	h.Write(namespaceBytes)
+	h.Write([]byte(name))
+	bytes := h.Sum(nil)[:16]
+
+	bytes[6] = bytes[6]&0x0f | 0x50 // version 5
+	bytes[8] = bytes[8]&0x3f | 0x80 // RFC 4122 variant
+
+	return uuid.FormatUUID(bytes)
+}
@@ -1,10 +1,19 @@
 package provider
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccResourceUUID(t *testing.T) {
@@ -31,8 +40,550 @@ func TestAccResourceUUID(t *testing.T) {
 	})
 }
 
+func TestAccResourceUUIDComb(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceUUIDConfigComb,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("random_uuid.comb", "comb", "true"),
+					resource.TestCheckResourceAttrWith("random_uuid.comb", "result", func(value string) error {
+						b, err := uuid.ParseUUID(value)
+						if err != nil {
+							return err
+						}
+
+						// The timestamp should be no later than now and recent enough to have been set by this test.
+						timestamp := time.UnixMilli(int64(b[10])<<40 | int64(b[11])<<32 | int64(b[12])<<24 |
+							int64(b[13])<<16 | int64(b[14])<<8 | int64(b[15]))
+						if age := time.Since(timestamp); age < 0 || age > time.Hour {
+							return fmt.Errorf("result %s has timestamp %s; want a recent time", value, timestamp)
+						}
+
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceUUIDVersion7(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceUUIDConfigVersion7,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr(
+						"random_uuid.v7",
+						"result",
+						regexp.MustCompile(`^[\da-f]{8}-[\da-f]{4}-7[\da-f]{3}-[89ab][\da-f]{3}-[\da-f]{12}$`),
+					),
+				),
+			},
+			{
+				ResourceName:            "random_uuid.v7",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"version"},
+			},
+		},
+	})
+}
+
+func TestCreateUuidRandReader(t *testing.T) {
+	setRandReader(t, &sequenceReader{})
+
+	d := resourceUuid().TestResourceData()
+	if diags := CreateUuid(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if expected := "00010203-0405-0607-0809-0a0b0c0d0e0f"; d.Get("result").(string) != expected {
+		t.Errorf("got %q; want %q", d.Get("result"), expected)
+	}
+}
+
+func TestReadUuidDetectDrift(t *testing.T) {
+	const id = "00010203-0405-0607-0809-0a0b0c0d0e0f"
+
+	cases := []struct {
+		name        string
+		id          string
+		result      string
+		format      string
+		prefix      string
+		detectDrift bool
+		removed     bool
+	}{
+		{name: "valid", id: id, result: id, detectDrift: true},
+		{name: "valid format", id: id, result: "000102030405060708090a0b0c0d0e0f", format: uuidFormatCompact, detectDrift: true},
+		{name: "invalid id", id: "not-a-uuid", result: id, detectDrift: true, removed: true},
+		{name: "invalid result", id: id, result: "not-a-uuid", detectDrift: true, removed: true},
+		{name: "mismatched result", id: id, result: "ffffffff-0405-0607-0809-0a0b0c0d0e0f", detectDrift: true, removed: true},
+		{name: "valid prefix", id: id, result: "order-" + id, prefix: "order-", detectDrift: true},
+		{name: "missing prefix", id: id, result: id, prefix: "order-", detectDrift: true, removed: true},
+		{name: "not detected", id: id, result: "not-a-uuid"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := resourceUuid().TestResourceData()
+			d.SetId(c.id)
+			for k, v := range map[string]interface{}{"result": c.result, "format": c.format, "prefix": c.prefix, "detect_drift": c.detectDrift} {
+				if err := d.Set(k, v); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			diags := ReadUuid(context.Background(), d, nil)
+			if !c.removed {
+				if len(diags) != 0 {
+					t.Fatalf("expected no diagnostics, got: %v", diags)
+				}
+				if d.Id() != c.id {
+					t.Errorf("got id %q; want %q", d.Id(), c.id)
+				}
+				return
+			}
+
+			if len(diags) != 1 || diags[0].Severity != diag.Warning {
+				t.Errorf("expected a warning, got: %v", diags)
+			}
+			if d.Id() != "" {
+				t.Errorf("expected the resource to be removed from state, got id %q", d.Id())
+			}
+		})
+	}
+}
+
+func TestGenerateV7UUID(t *testing.T) {
+	start := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	previous := ""
+	for i := 0; i < 1000; i++ {
+		now := start.Add(time.Duration(i) * time.Millisecond)
+		result, err := generateV7UUID(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := uuid.ParseUUID(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version := b[6] >> 4; version != 7 {
+			t.Fatalf("result %s has version %d; want 7", result, version)
+		}
+		if variant := b[8] >> 6; variant != 2 {
+			t.Fatalf("result %s has variant %b; want 10", result, variant)
+		}
+
+		timestamp := time.UnixMilli(int64(b[0])<<40 | int64(b[1])<<32 | int64(b[2])<<24 |
+			int64(b[3])<<16 | int64(b[4])<<8 | int64(b[5]))
+		if !timestamp.Equal(now) {
+			t.Fatalf("result %s has timestamp %s; want %s", result, timestamp, now)
+		}
+
+		if result <= previous {
+			t.Fatalf("expected %s to sort before %s", previous, result)
+		}
+		previous = result
+	}
+}
+
+func TestAccResourceUUIDNameBased(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceUUIDConfigNameBased,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("random_uuid.name_based", "result", "2ed6657d-e927-568b-95e1-2665a8aea6a2"),
+				),
+			},
+			{
+				Config:      testAccResourceUUIDConfigInvalidNamespace,
+				ExpectError: regexp.MustCompile(`expected namespace to be a valid uuid`),
+			},
+		},
+	})
+}
+
+func TestIsUUIDNamespaceAndName(t *testing.T) {
+	// unknownConfigValue is the value used by the SDK to represent values that are not known until apply.
+	const unknownConfigValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+	const dns = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		err    string
+	}{
+		{
+			name:   "neither set",
+			config: map[string]interface{}{},
+		},
+		{
+			name:   "both set",
+			config: map[string]interface{}{"namespace": dns, "name": "www.example.com"},
+		},
+		{
+			name:   "namespace only",
+			config: map[string]interface{}{"namespace": dns},
+			err:    "name must be set when namespace is set",
+		},
+		{
+			name:   "name only",
+			config: map[string]interface{}{"name": "www.example.com"},
+			err:    "namespace must be set when name is set",
+		},
+		{
+			name:   "empty name",
+			config: map[string]interface{}{"namespace": dns, "name": ""},
+			err:    "name must be set when namespace is set",
+		},
+		{
+			name:   "invalid namespace",
+			config: map[string]interface{}{"namespace": "not-a-uuid", "name": "www.example.com"},
+			err:    `expected namespace to be a valid uuid, got "not-a-uuid"`,
+		},
+		{
+			name:   "namespace unknown",
+			config: map[string]interface{}{"namespace": unknownConfigValue, "name": "www.example.com"},
+		},
+		{
+			name:   "name unknown",
+			config: map[string]interface{}{"namespace": dns, "name": unknownConfigValue},
+		},
+	}
+
+	r := &schema.Resource{
+		Schema:        resourceUuid().Schema,
+		CustomizeDiff: isUUIDNamespaceAndName("namespace", "name"),
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(c.config), nil)
+
+			if c.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), c.err) {
+					t.Errorf("expected: %q, got: %v", c.err, err)
+				}
+			} else if err != nil {
+				t.Errorf("err should be nil, actual: %v", err)
+			}
+		})
+	}
+}
+
+func TestGenerateNameBasedUUID(t *testing.T) {
+	cases := []struct {
+		namespace string
+		name      string
+		expected  string
+	}{
+		{
+			namespace: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", // DNS
+			name:      "python.org",
+			expected:  "886313e1-3b8a-5372-9b90-0c9aee199e5d",
+		},
+		{
+			namespace: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", // DNS
+			name:      "www.example.com",
+			expected:  "2ed6657d-e927-568b-95e1-2665a8aea6a2",
+		},
+		{
+			namespace: "6ba7b811-9dad-11d1-80b4-00c04fd430c8", // URL
+			name:      "http://python.org/",
+			expected:  "4c565f0d-3f5a-5890-b41b-20cf47701c5e",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := generateNameBasedUUID(c.namespace, c.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != c.expected {
+				t.Errorf("expected: %s, got: %s", c.expected, actual)
+			}
+		})
+	}
+
+	if _, err := generateNameBasedUUID("not-a-uuid", "python.org"); err == nil {
+		t.Error("expected error for invalid namespace")
+	}
+}
+
+func TestAccResourceUUIDFormat(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceUUIDConfigFormat,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_uuid.uppercase", "result",
+						regexp.MustCompile(`^[\dA-F]{8}-[\dA-F]{4}-[\dA-F]{4}-[\dA-F]{4}-[\dA-F]{12}$`)),
+					resource.TestMatchResourceAttr("random_uuid.uppercase", "id",
+						regexp.MustCompile(`^[\da-f]{8}-[\da-f]{4}-[\da-f]{4}-[\da-f]{4}-[\da-f]{12}$`)),
+					resource.TestMatchResourceAttr("random_uuid.compact", "result", regexp.MustCompile(`^[\da-f]{32}$`)),
+					resource.TestMatchResourceAttr("random_uuid.compact", "id",
+						regexp.MustCompile(`^[\da-f]{8}-[\da-f]{4}-[\da-f]{4}-[\da-f]{4}-[\da-f]{12}$`)),
+				),
+			},
+			{
+				ResourceName:      "random_uuid.uppercase",
+				ImportState:       true,
+				ImportStateIdFunc: testAccResourceUUIDImportID("random_uuid.uppercase", uuidFormatUppercase),
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "random_uuid.compact",
+				ImportState:       true,
+				ImportStateIdFunc: testAccResourceUUIDImportID("random_uuid.compact", uuidFormatCompact),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testAccResourceUUIDImportID returns an import ID made up of the result of the named resource, as presented in its
+// configured format, and format.
+func testAccResourceUUIDImportID(name, format string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", name)
+		}
+
+		return rs.Primary.Attributes["result"] + "," + format, nil
+	}
+}
+
+func TestAccResourceUUIDPrefixSuffix(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceUUIDConfigPrefixSuffix,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("random_uuid.order", "result",
+						regexp.MustCompile(`^order-[\dA-F]{8}-[\dA-F]{4}-[\dA-F]{4}-[\dA-F]{4}-[\dA-F]{12}-v1$`)),
+					resource.TestMatchResourceAttr("random_uuid.order", "id",
+						regexp.MustCompile(`^[\da-f]{8}-[\da-f]{4}-[\da-f]{4}-[\da-f]{4}-[\da-f]{12}$`)),
+				),
+			},
+			{
+				ResourceName: "random_uuid.order",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["random_uuid.order"]
+					if !ok {
+						return "", fmt.Errorf("Not found: random_uuid.order")
+					}
+
+					return rs.Primary.Attributes["result"] + ",uppercase,order-,-v1", nil
+				},
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestImportUuid(t *testing.T) {
+	const id = "aabbccdd-eeff-0011-2233-445566778899"
+
+	cases := []struct {
+		name           string
+		importID       string
+		expectedResult string
+		expectedPrefix string
+		expectedSuffix string
+		err            string
+	}{
+		{name: "uuid", importID: id, expectedResult: id},
+		{name: "format", importID: "AABBCCDDEEFF00112233445566778899,compact", expectedResult: "aabbccddeeff00112233445566778899"},
+		{
+			name:           "prefix and suffix",
+			importID:       "order-" + id + "-v1,,order-,-v1",
+			expectedResult: "order-" + id + "-v1",
+			expectedPrefix: "order-",
+			expectedSuffix: "-v1",
+		},
+		{
+			name:           "prefix with format",
+			importID:       "order-AABBCCDD-EEFF-0011-2233-445566778899,uppercase,order-",
+			expectedResult: "order-AABBCCDD-EEFF-0011-2233-445566778899",
+			expectedPrefix: "order-",
+		},
+		{
+			name:           "prefix not in uuid",
+			importID:       id + ",,order-",
+			expectedResult: "order-" + id,
+			expectedPrefix: "order-",
+		},
+		{name: "prefix not stripped", importID: "order-" + id, err: "error parsing uuid bytes"},
+		{name: "too many parts", importID: id + ",,a,b,c", err: "invalid import format"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := resourceUuid().TestResourceData()
+			d.SetId(c.importID)
+
+			_, err := ImportUuid(context.Background(), d, nil)
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("expected error containing %q, got: %v", c.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if d.Id() != id {
+				t.Errorf("expected id %q, got: %q", id, d.Id())
+			}
+			for k, expected := range map[string]string{"result": c.expectedResult, "prefix": c.expectedPrefix, "suffix": c.expectedSuffix} {
+				if got := d.Get(k).(string); got != expected {
+					t.Errorf("expected %s %q, got: %q", k, expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestParseUUIDInput(t *testing.T) {
+	expected := "aabbccdd-eeff-0011-2233-445566778899"
+
+	for _, input := range []string{
+		"aabbccdd-eeff-0011-2233-445566778899",
+		"AABBCCDD-EEFF-0011-2233-445566778899",
+		"aabbccddeeff00112233445566778899",
+		"AABBCCDDEEFF00112233445566778899",
+	} {
+		b, err := parseUUIDInput(input)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %s", input, err)
+		}
+
+		actual, err := uuid.FormatUUID(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != expected {
+			t.Errorf("parsed %q as %s; want %s", input, actual, expected)
+		}
+	}
+
+	if _, err := parseUUIDInput("aabbccdd"); err == nil {
+		t.Error("expected error parsing a truncated uuid")
+	}
+}
+
+func TestAccResourceUUIDKeepersUpdate(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: testAccKeepersUpdateSteps("random_uuid.keepers", `resource "random_uuid" "keepers" {
+							keepers                  = %s
+							replace_on_keeper_change = false
+						}`, "result"),
+	})
+}
+
+func TestGenerateCombUUIDOrdering(t *testing.T) {
+	start := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var previous []byte
+	for i := 0; i < 1000; i++ {
+		result, err := generateCombUUID(start.Add(time.Duration(i) * time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		current, err := uuid.ParseUUID(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if previous != nil && compareSQLServerUniqueIdentifier(previous, current) >= 0 {
+			t.Fatalf("expected %x to sort before %x", previous, current)
+		}
+		previous = current
+	}
+}
+
+// compareSQLServerUniqueIdentifier compares a and b in the order SQL Server uses for uniqueidentifier values, which
+// compares byte groups 10-15, 8-9, 6-7, 4-5 and then 0-3, each left to right.
+func compareSQLServerUniqueIdentifier(a, b []byte) int {
+	for _, group := range [][2]int{{10, 16}, {8, 10}, {6, 8}, {4, 6}, {0, 4}} {
+		if c := bytes.Compare(a[group[0]:group[1]], b[group[0]:group[1]]); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
 const (
 	testAccResourceUUIDConfig = `
 resource "random_uuid" "basic" { }
+`
+
+	testAccResourceUUIDConfigComb = `
+resource "random_uuid" "comb" {
+  comb = true
+}
+`
+
+	testAccResourceUUIDConfigVersion7 = `
+resource "random_uuid" "v7" {
+  version = 7
+}
+`
+
+	testAccResourceUUIDConfigFormat = `
+resource "random_uuid" "uppercase" {
+  format = "uppercase"
+}
+
+resource "random_uuid" "compact" {
+  format = "compact"
+}
+`
+
+	testAccResourceUUIDConfigPrefixSuffix = `
+resource "random_uuid" "order" {
+  format = "uppercase"
+  prefix = "order-"
+  suffix = "-v1"
+}
+`
+
+	testAccResourceUUIDConfigNameBased = `
+resource "random_uuid" "name_based" {
+  namespace = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+  name      = "www.example.com"
+}
+`
+
+	testAccResourceUUIDConfigInvalidNamespace = `
+resource "random_uuid" "name_based" {
+  namespace = "not-a-uuid"
+  name      = "www.example.com"
+}
 `
 )
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"io"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+)
+
+// randomAttempts is the number of times reading from a source of randomness
+// is attempted before giving up, so that a temporarily starved entropy pool
+// does not fail an apply.
+const randomAttempts = 3
+
+// randomRetryBackoff is the delay before the second attempt to read from a
+// source of randomness. The delay doubles before each later attempt.
+var randomRetryBackoff = 20 * time.Millisecond
+
+// retryRandom calls generate until it succeeds, up to randomAttempts times,
+// waiting between attempts. The error of the final attempt is returned when
+// every attempt fails.
+func retryRandom(generate func() error) error {
+	backoff := randomRetryBackoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = generate(); err == nil || attempt >= randomAttempts {
+			return err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// generateRandomUUID returns a random (version 4) uuid generated from
+// reader, retrying as described by retryRandom.
+func generateRandomUUID(reader io.Reader) (string, error) {
+	var result string
+	err := retryRandom(func() (err error) {
+		result, err = uuid.GenerateUUIDWithReader(reader)
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// failingReader fails the failures reads after the first skip reads, and otherwise reads from reader.
+type failingReader struct {
+	reader   io.Reader
+	skip     int
+	failures int
+	reads    int
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	r.reads++
+	if r.reads > r.skip && r.reads <= r.skip+r.failures {
+		return 0, errors.New("entropy pool starved")
+	}
+
+	return r.reader.Read(p)
+}
+
+func TestGenerateRandomUUIDRetry(t *testing.T) {
+	reader := &failingReader{reader: rand.Reader, failures: randomAttempts - 1}
+	result, err := generateRandomUUID(reader)
+	if err != nil {
+		t.Fatalf("expected success after %d failures, got: %s", reader.failures, err)
+	}
+	if len(result) != 36 {
+		t.Errorf("got %q; want a uuid", result)
+	}
+
+	reader = &failingReader{reader: rand.Reader, failures: randomAttempts}
+	if _, err := generateRandomUUID(reader); err == nil {
+		t.Fatalf("expected error after %d failures", reader.failures)
+	}
+	if reader.reads != randomAttempts {
+		t.Errorf("got %d reads; want %d", reader.reads, randomAttempts)
+	}
+}
+
+func TestGenerateRandomBytesRetry(t *testing.T) {
+	charSet := numChars
+
+	reader := &failingReader{reader: rand.Reader, failures: randomAttempts - 1}
+	result, err := generateRandomBytes(reader, &charSet, 8)
+	if err != nil {
+		t.Fatalf("expected success after %d failures, got: %s", reader.failures, err)
+	}
+	if len(result) != 8 {
+		t.Errorf("got %q; want 8 digits", result)
+	}
+
+	reader = &failingReader{reader: rand.Reader, failures: randomAttempts}
+	if _, err := generateRandomBytes(reader, &charSet, 8); err == nil {
+		t.Fatalf("expected error after %d failures", reader.failures)
+	}
+}
+
+func TestGenerateRandomUnitsRetry(t *testing.T) {
+	reader := &failingReader{reader: rand.Reader, failures: randomAttempts - 1}
+	result, err := generateRandomUnits(reader, splitBytes(numChars), 8)
+	if err != nil {
+		t.Fatalf("expected success after %d failures, got: %s", reader.failures, err)
+	}
+	if len(result) != 8 {
+		t.Errorf("got %q; want 8 digits", result)
+	}
+
+	reader = &failingReader{reader: rand.Reader, failures: randomAttempts}
+	if _, err := generateRandomUnits(reader, splitBytes(numChars), 8); err == nil {
+		t.Fatalf("expected error after %d failures", reader.failures)
+	}
+}
+
+func TestGenerateStringShuffleRetry(t *testing.T) {
+	// Drawing units from a single character takes a single read, so that the following reads are those of the
+	// shuffle.
+	reader := &failingReader{reader: rand.Reader, skip: 1, failures: randomAttempts - 1}
+	result, err := generateString(reader, "a", nil, 8, splitBytes)
+	if err != nil {
+		t.Fatalf("expected success after %d failures, got: %s", reader.failures, err)
+	}
+	if len(result) != 8 {
+		t.Errorf("got %q; want 8 characters", result)
+	}
+
+	reader = &failingReader{reader: rand.Reader, skip: 1, failures: randomAttempts}
+	if _, err := generateString(reader, "a", nil, 8, splitBytes); err == nil {
+		t.Fatalf("expected error after %d failures", reader.failures)
+	}
+}
+
+func TestGenerateTimestampUUIDRetry(t *testing.T) {
+	for name, generate := range map[string]func(time.Time) (string, error){
+		"comb": generateCombUUID,
+		"v7":   generateV7UUID,
+	} {
+		t.Run(name, func(t *testing.T) {
+			setRandReader(t, &failingReader{reader: rand.Reader, failures: randomAttempts - 1})
+			result, err := generate(time.Now())
+			if err != nil {
+				t.Fatalf("expected success after %d failures, got: %s", randomAttempts-1, err)
+			}
+			if len(result) != 36 {
+				t.Errorf("got %q; want a uuid", result)
+			}
+
+			setRandReader(t, &failingReader{reader: rand.Reader, failures: randomAttempts})
+			if _, err := generate(time.Now()); err == nil {
+				t.Fatalf("expected error after %d failures", randomAttempts)
+			}
+		})
+	}
+}
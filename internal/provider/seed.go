@@ -1,24 +1,134 @@
 package provider
 
 import (
+	"crypto/hmac"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
 	"hash/crc64"
+	"io"
 	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // NewRand returns a seeded random number generator, using a seed derived
-// from the provided string.
+// from the provided string by stringSeed.
 //
 // If the seed string is empty, the current time is used as a seed.
 func NewRand(seed string) *rand.Rand {
 	var seedInt int64
 	if seed != "" {
-		crcTable := crc64.MakeTable(crc64.ISO)
-		seedInt = int64(crc64.Checksum([]byte(seed), crcTable))
+		seedInt = stringSeed(seed)
 	} else {
 		seedInt = time.Now().UnixNano()
 	}
 
-	randSource := rand.NewSource(seedInt)
-	return rand.New(randSource)
+	return newIntRand(seedInt)
+}
+
+// newIntRand returns a random number generator seeded with seed.
+func newIntRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// stringSeed returns the math/rand seed derived from seed, which is the CRC-64
+// checksum of seed using the ISO polynomial, interpreted as an int64. It must
+// not change, as the results of existing configurations depend on it.
+func stringSeed(seed string) int64 {
+	return int64(crc64.Checksum([]byte(seed), crc64.MakeTable(crc64.ISO)))
+}
+
+// randReader is the source of randomness for results that are not seeded.
+// It is crypto/rand, but tests may replace it with a deterministic reader to
+// assert exact results.
+var randReader io.Reader = cryptorand.Reader
+
+// newReaderRand returns a random number generator seeded from reader. If
+// reading from reader fails, the current time is used as a seed, as NewRand
+// does for an empty seed.
+func newReaderRand(reader io.Reader) *rand.Rand {
+	var seed [8]byte
+	if _, err := io.ReadFull(reader, seed[:]); err != nil {
+		return NewRand("")
+	}
+
+	return rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(seed[:]))))
+}
+
+// resourceSeed returns the seed for the random number generator of a
+// resource of type typeName, derived from the seed of the provider
+// configuration held in meta, or an empty string if the provider has no seed.
+//
+// The address of the resource is not available to the provider, so the seed
+// is an HMAC, keyed by the provider seed, of typeName and the configurable
+// arguments in d of schema s, each encoded by canonicalSeedValue. Resources of
+// the same type with identical configuration therefore share a seed.
+func resourceSeed(meta interface{}, typeName string, s map[string]*schema.Schema, d *schema.ResourceData) string {
+	config, ok := meta.(*providerConfig)
+	if !ok || config.seed == "" {
+		return ""
+	}
+
+	keys := make([]string, 0, len(s))
+	for k, v := range s {
+		if v.Required || v.Optional {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	mac := hmac.New(sha256.New, []byte(config.seed))
+	fmt.Fprintf(mac, "%s\n", typeName)
+	for _, k := range keys {
+		fmt.Fprintf(mac, "%s=%s\n", k, canonicalSeedValue(d.Get(k)))
+	}
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// canonicalSeedValue returns an encoding of v, a value returned by
+// ResourceData.Get, that depends only on the configuration it holds. The
+// elements of a set, such as special_set, are sorted by their encoding, as
+// the order in which a set holds them is not meaningful, and the entries of a
+// map are sorted by key.
+func canonicalSeedValue(v interface{}) string {
+	switch v := v.(type) {
+	case *schema.Set:
+		elems := make([]string, 0, v.Len())
+		for _, e := range v.List() {
+			elems = append(elems, canonicalSeedValue(e))
+		}
+		sort.Strings(elems)
+
+		return "set{" + strings.Join(elems, ",") + "}"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		entries := make([]string, 0, len(keys))
+		for _, k := range keys {
+			entries = append(entries, strconv.Quote(k)+":"+canonicalSeedValue(v[k]))
+		}
+
+		return "map{" + strings.Join(entries, ",") + "}"
+	case []interface{}:
+		elems := make([]string, 0, len(v))
+		for _, e := range v {
+			elems = append(elems, canonicalSeedValue(e))
+		}
+
+		return "[" + strings.Join(elems, ",") + "]"
+	default:
+		return fmt.Sprintf("%#v", v)
+	}
 }
@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// sequenceReader is a deterministic source of randomness that reads the bytes 0, 1, 2 and so on, wrapping after 255.
+type sequenceReader struct {
+	next byte
+}
+
+func (r *sequenceReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = r.next
+		r.next++
+	}
+
+	return len(p), nil
+}
+
+// setRandReader replaces randReader with reader until the test completes.
+func setRandReader(t *testing.T, reader io.Reader) {
+	t.Helper()
+
+	previous := randReader
+	randReader = reader
+	t.Cleanup(func() {
+		randReader = previous
+	})
+}
+
+func TestNewReaderRand(t *testing.T) {
+	first := newReaderRand(&sequenceReader{}).Perm(10)
+	if next := newReaderRand(&sequenceReader{}).Perm(10); !cmp.Equal(first, next) {
+		t.Errorf("expected permutations from the same reader to match, got %v and %v", first, next)
+	}
+
+	failing := &failingReader{reader: &sequenceReader{}, failures: 1}
+	if perm := newReaderRand(failing).Perm(10); len(perm) != 10 {
+		t.Errorf("got permutation %v; want 10 items", perm)
+	}
+}
+
+func TestStringSeed(t *testing.T) {
+	// The seed derived from a string must not change, as the results of existing configurations depend on it.
+	if got, want := stringSeed("-"), int64(6007801902912241664); got != want {
+		t.Errorf("stringSeed(%q) = %d; want %d", "-", got, want)
+	}
+
+	if got, want := NewRand("-").Perm(10), newIntRand(stringSeed("-")).Perm(10); !cmp.Equal(got, want) {
+		t.Errorf("NewRand(%q) permutation %v; want %v", "-", got, want)
+	}
+}
+
+func TestResourceSeed(t *testing.T) {
+	newData := func(length int, keepers map[string]interface{}) *schema.ResourceData {
+		d := resourceString().TestResourceData()
+		if err := d.Set("length", length); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.Set("keepers", keepers); err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	s := resourceString().Schema
+	config := &providerConfig{seed: "preview"}
+
+	if seed := resourceSeed(nil, "random_string", s, newData(8, nil)); seed != "" {
+		t.Errorf("expected no seed without provider configuration, got %q", seed)
+	}
+	if seed := resourceSeed(&providerConfig{}, "random_string", s, newData(8, nil)); seed != "" {
+		t.Errorf("expected no seed without provider seed, got %q", seed)
+	}
+
+	seed := resourceSeed(config, "random_string", s, newData(8, map[string]interface{}{"a": "1"}))
+	if seed == "" {
+		t.Fatal("expected a seed")
+	}
+	if again := resourceSeed(config, "random_string", s, newData(8, map[string]interface{}{"a": "1"})); again != seed {
+		t.Errorf("expected the same seed for the same configuration, got %q and %q", seed, again)
+	}
+
+	for name, other := range map[string]string{
+		"provider seed": resourceSeed(&providerConfig{seed: "other"}, "random_string", s, newData(8, map[string]interface{}{"a": "1"})),
+		"type name":     resourceSeed(config, "random_password", s, newData(8, map[string]interface{}{"a": "1"})),
+		"length":        resourceSeed(config, "random_string", s, newData(9, map[string]interface{}{"a": "1"})),
+		"keepers":       resourceSeed(config, "random_string", s, newData(8, map[string]interface{}{"a": "2"})),
+	} {
+		if other == seed {
+			t.Errorf("expected a different seed when %s changes, got %q for both", name, seed)
+		}
+	}
+}
+
+func TestCanonicalSeedValue(t *testing.T) {
+	hashRune := func(v interface{}) int { return int([]rune(v.(string))[0]) }
+
+	cases := []struct {
+		name     string
+		a, b     interface{}
+		expected string
+	}{
+		{
+			name:     "set order and hash function",
+			a:        schema.NewSet(schema.HashString, []interface{}{"#", "!", "%"}),
+			b:        schema.NewSet(hashRune, []interface{}{"%", "#", "!"}),
+			expected: `set{"!","#","%"}`,
+		},
+		{
+			name:     "map",
+			a:        map[string]interface{}{"b": "2", "a": "1"},
+			b:        map[string]interface{}{"a": "1", "b": "2"},
+			expected: `map{"a":"1","b":"2"}`,
+		},
+		{
+			name:     "list of maps",
+			a:        []interface{}{map[string]interface{}{"parts": 3, "threshold": 2}},
+			b:        []interface{}{map[string]interface{}{"threshold": 2, "parts": 3}},
+			expected: `[map{"parts":3,"threshold":2}]`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := canonicalSeedValue(c.a); got != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, got)
+			}
+			if got := canonicalSeedValue(c.b); got != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestStringRandReaderProviderSeed(t *testing.T) {
+	config := &providerConfig{seed: "preview"}
+	generate := func(d *schema.ResourceData, typeName string) string {
+		result, diags := generateStringResult(d, config, stringRandReader(d, config, typeName))
+		if diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		return string(result)
+	}
+
+	newString := func() *schema.ResourceData {
+		d := resourceString().TestResourceData()
+		for k, v := range map[string]interface{}{"length": 16, "lower": true} {
+			if err := d.Set(k, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return d
+	}
+	if a, b := generate(newString(), "random_string"), generate(newString(), "random_string"); a != b {
+		t.Errorf("expected random_string to be reproducible from the provider seed, got %q and %q", a, b)
+	}
+
+	// random_password is not seeded by the provider.
+	newPassword := func() *schema.ResourceData {
+		d := resourcePassword().TestResourceData()
+		for k, v := range map[string]interface{}{"length": 16, "lower": true} {
+			if err := d.Set(k, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return d
+	}
+	if a, b := generate(newPassword(), "random_password"), generate(newPassword(), "random_password"); a == b {
+		t.Errorf("expected random_password to ignore the provider seed, got %q twice", a)
+	}
+}
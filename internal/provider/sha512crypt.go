@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	// cryptAlphabet is the alphabet used by crypt(3) for both salts and encoded hashes.
+	cryptAlphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	sha512CryptPrefix        = "$6$"
+	sha512CryptRoundsPrefix  = "rounds="
+	sha512CryptDefaultRounds = 5000
+	sha512CryptMinRounds     = 1000
+	sha512CryptMaxRounds     = 999999999
+	sha512CryptMaxSaltLength = 16
+)
+
+// sha512CryptByteOrder is the order in which the bytes of the final digest are encoded, in groups of three.
+var sha512CryptByteOrder = [...][3]int{
+	{0, 21, 42}, {22, 43, 1}, {44, 2, 23}, {3, 24, 45}, {25, 46, 4}, {47, 5, 26}, {6, 27, 48},
+	{28, 49, 7}, {50, 8, 29}, {9, 30, 51}, {31, 52, 10}, {53, 11, 32}, {12, 33, 54}, {34, 55, 13},
+	{56, 14, 35}, {15, 36, 57}, {37, 58, 16}, {59, 17, 38}, {18, 39, 60}, {40, 61, 19}, {62, 20, 41},
+}
+
+// generateSHA512Crypt returns a SHA-512 crypt string of the form $6$<salt>$<hash> for toHash, using a random 16
+// character salt and the default number of rounds, as used in /etc/shadow on Linux.
+func generateSHA512Crypt(toHash string) (string, error) {
+	salt := make([]byte, sha512CryptMaxSaltLength)
+	for i := range salt {
+		idx, err := rand.Int(rand.Reader, big.NewInt(int64(len(cryptAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		salt[i] = cryptAlphabet[idx.Int64()]
+	}
+
+	return sha512Crypt(toHash, sha512CryptPrefix+string(salt))
+}
+
+// sha512Crypt implements the SHA-512 based crypt(3) algorithm described in
+// https://www.akkadia.org/drepper/SHA-crypt.txt. The setting is the leading part of a crypt string, i.e.
+// $6$[rounds=<n>$]<salt>, and may be a complete crypt string, in which case the hash is ignored.
+func sha512Crypt(key, setting string) (string, error) {
+	if !strings.HasPrefix(setting, sha512CryptPrefix) {
+		return "", fmt.Errorf("setting must start with %q", sha512CryptPrefix)
+	}
+	setting = strings.TrimPrefix(setting, sha512CryptPrefix)
+
+	rounds := sha512CryptDefaultRounds
+	customRounds := false
+	if strings.HasPrefix(setting, sha512CryptRoundsPrefix) {
+		sep := strings.IndexByte(setting, '$')
+		if sep < 0 {
+			return "", fmt.Errorf("rounds must be followed by $")
+		}
+
+		var err error
+		rounds, err = strconv.Atoi(setting[len(sha512CryptRoundsPrefix):sep])
+		if err != nil {
+			return "", fmt.Errorf("error parsing rounds: %w", err)
+		}
+		if rounds < sha512CryptMinRounds {
+			rounds = sha512CryptMinRounds
+		}
+		if rounds > sha512CryptMaxRounds {
+			rounds = sha512CryptMaxRounds
+		}
+
+		customRounds = true
+		setting = setting[sep+1:]
+	}
+
+	salt := setting
+	if sep := strings.IndexByte(salt, '$'); sep >= 0 {
+		salt = salt[:sep]
+	}
+	if len(salt) > sha512CryptMaxSaltLength {
+		salt = salt[:sha512CryptMaxSaltLength]
+	}
+
+	k, s := []byte(key), []byte(salt)
+
+	b := sha512.New()
+	b.Write(k)
+	b.Write(s)
+	b.Write(k)
+	digestB := b.Sum(nil)
+
+	a := sha512.New()
+	a.Write(k)
+	a.Write(s)
+	for n := len(k); n > 0; n -= sha512.Size {
+		a.Write(digestB[:minInt(n, sha512.Size)])
+	}
+	for n := len(k); n > 0; n >>= 1 {
+		if n&1 != 0 {
+			a.Write(digestB)
+		} else {
+			a.Write(k)
+		}
+	}
+	digestA := a.Sum(nil)
+
+	dp := sha512.New()
+	for i := 0; i < len(k); i++ {
+		dp.Write(k)
+	}
+	p := repeatBytes(dp.Sum(nil), len(k))
+
+	ds := sha512.New()
+	for i := 0; i < 16+int(digestA[0]); i++ {
+		ds.Write(s)
+	}
+	sBytes := repeatBytes(ds.Sum(nil), len(s))
+
+	digest := digestA
+	for i := 0; i < rounds; i++ {
+		c := sha512.New()
+		if i&1 != 0 {
+			c.Write(p)
+		} else {
+			c.Write(digest)
+		}
+		if i%3 != 0 {
+			c.Write(sBytes)
+		}
+		if i%7 != 0 {
+			c.Write(p)
+		}
+		if i&1 != 0 {
+			c.Write(digest)
+		} else {
+			c.Write(p)
+		}
+		digest = c.Sum(nil)
+	}
+
+	var out strings.Builder
+	out.WriteString(sha512CryptPrefix)
+	if customRounds {
+		fmt.Fprintf(&out, "%s%d$", sha512CryptRoundsPrefix, rounds)
+	}
+	out.WriteString(salt)
+	out.WriteByte('$')
+
+	for _, group := range sha512CryptByteOrder {
+		writeCryptBase64(&out, uint(digest[group[0]])<<16|uint(digest[group[1]])<<8|uint(digest[group[2]]), 4)
+	}
+	writeCryptBase64(&out, uint(digest[63]), 2)
+
+	return out.String(), nil
+}
+
+// writeCryptBase64 writes the low 6*n bits of w to out using cryptAlphabet, least significant bits first.
+func writeCryptBase64(out *strings.Builder, w uint, n int) {
+	for ; n > 0; n-- {
+		out.WriteByte(cryptAlphabet[w&0x3f])
+		w >>= 6
+	}
+}
+
+// repeatBytes returns length bytes consisting of b repeated as many times as necessary.
+func repeatBytes(b []byte, length int) []byte {
+	result := make([]byte, 0, length)
+	for len(result) < length {
+		result = append(result, b[:minInt(length-len(result), len(b))]...)
+	}
+
+	return result
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
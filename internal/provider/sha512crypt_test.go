@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestSHA512Crypt(t *testing.T) {
+	// Test vectors from https://www.akkadia.org/drepper/SHA-crypt.txt.
+	cases := []struct {
+		setting  string
+		key      string
+		expected string
+	}{
+		{
+			setting:  "$6$saltstring",
+			key:      "Hello world!",
+			expected: "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1",
+		},
+		{
+			setting:  "$6$rounds=10000$saltstringsaltstring",
+			key:      "Hello world!",
+			expected: "$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v.",
+		},
+		{
+			setting:  "$6$rounds=5000$toolongsaltstring",
+			key:      "This is just a test",
+			expected: "$6$rounds=5000$toolongsaltstrin$lQ8jolhgVRVhY4b5pZKaysCLi0QBxGoNeKQzQ3glMhwllF7oGDZxUhx1yxdYcz/e1JSbq3y6JMxxl8audkUEm0",
+		},
+		{
+			setting:  "$6$rounds=10$roundstoolow",
+			key:      "the minimum number is still observed",
+			expected: "$6$rounds=1000$roundstoolow$kUMsbe306n21p9R.FRkW3IGn.S9NPN0x50YhH1xhLsPuWGsUSklZt58jaTfF4ZEQpyUNGc0dqbpBYYBaHHrsX.",
+		},
+	}
+
+	for _, c := range cases {
+		got, err := sha512Crypt(c.key, c.setting)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.expected {
+			t.Errorf("sha512Crypt(%q, %q) = %q; want %q", c.key, c.setting, got, c.expected)
+		}
+	}
+
+	if _, err := sha512Crypt("key", "$5$saltstring"); err == nil {
+		t.Error("expected error for a setting that is not SHA-512 crypt")
+	}
+}
+
+func TestGenerateSHA512Crypt(t *testing.T) {
+	crypted, err := generateSHA512Crypt("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !regexp.MustCompile(`^\$6\$[./0-9A-Za-z]{16}\$[./0-9A-Za-z]{86}$`).MatchString(crypted) {
+		t.Fatalf("crypt string %q is not in the expected format", crypted)
+	}
+
+	if err := verifySHA512Crypt("hunter2", crypted); err != nil {
+		t.Error(err)
+	}
+	if err := verifySHA512Crypt("hunter3", crypted); err == nil {
+		t.Error("expected crypt string not to verify against a different password")
+	}
+
+	other, err := generateSHA512Crypt("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == crypted {
+		t.Error("expected a different random salt for each crypt string")
+	}
+}
+
+// verifySHA512Crypt returns an error unless crypted is the SHA-512 crypt string of key, using the salt and rounds
+// held in crypted.
+func verifySHA512Crypt(key, crypted string) error {
+	got, err := sha512Crypt(key, crypted)
+	if err != nil {
+		return err
+	}
+	if got != crypted {
+		return fmt.Errorf("crypt string %q does not verify against the key, got %q", crypted, got)
+	}
+
+	return nil
+}
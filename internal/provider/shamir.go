@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// shamirSchema returns the shamir entry of a resource whose generated bytes can be split into the shares held in its
+// shamirSharesSchema entry.
+func shamirSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Splits the generated bytes into shares using Shamir's Secret Sharing, such that " +
+			"any `threshold` of the `parts` shares can be combined to reconstruct them. The shares are " +
+			"exposed in `shamir_shares`.",
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"parts": {
+					Description:      "The number of shares to produce. The minimum value is 2 and the maximum is 255.",
+					Type:             schema.TypeInt,
+					Required:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(2, 255)),
+				},
+
+				"threshold": {
+					Description: "The number of shares required to reconstruct the generated bytes, which " +
+						"must not exceed `parts`. The minimum value is 2.",
+					Type:             schema.TypeInt,
+					Required:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(2, 255)),
+				},
+			},
+		},
+	}
+}
+
+// shamirSharesSchema returns the shamir_shares entry, which holds the shares generated by shamirShares.
+func shamirSharesSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "The Shamir shares of the generated bytes when `shamir` is set, each presented in " +
+			"padded hexadecimal digits. Each share is the share bytes followed by a single byte " +
+			"holding the share's x coordinate, which is the layout used by HashiCorp Vault.",
+		Type:      schema.TypeList,
+		Computed:  true,
+		Sensitive: true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+// shamirConfig returns the parts and threshold of the shamir entry in d, or 0 for both when it is not set.
+func shamirConfig(d *schema.ResourceData) (parts, threshold int, err error) {
+	v := d.Get("shamir").([]interface{})
+	if len(v) == 0 || v[0] == nil {
+		return 0, 0, nil
+	}
+
+	m := v[0].(map[string]interface{})
+	parts, threshold = m["parts"].(int), m["threshold"].(int)
+	if threshold > parts {
+		return 0, 0, fmt.Errorf("shamir threshold (%d) must not exceed parts (%d)", threshold, parts)
+	}
+
+	return parts, threshold, nil
+}
+
+// shamirShares splits secret into parts shares as shamirSplit does, returning each share in hexadecimal digits, or no
+// shares when parts is 0.
+func shamirShares(reader io.Reader, secret []byte, parts, threshold int) ([]string, error) {
+	if parts == 0 {
+		return nil, nil
+	}
+
+	split, err := shamirSplit(reader, secret, parts, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]string, len(split))
+	for i, share := range split {
+		shares[i] = hex.EncodeToString(share)
+	}
+
+	return shares, nil
+}
+
+// shamirSplit splits secret into parts shares using Shamir's Secret Sharing over GF(2^8), such that any threshold of
+// the shares can be combined to reconstruct secret while fewer reveal nothing about it. Each share is the same length
+// as secret followed by a single byte holding the share's unique, non-zero x coordinate, which is the layout used by
+// HashiCorp Vault.
+func shamirSplit(reader io.Reader, secret []byte, parts, threshold int) ([][]byte, error) {
+	if threshold < 2 || parts < threshold || parts > 255 {
+		return nil, fmt.Errorf("threshold (%d) must be >= 2 and <= parts (%d), which must be <= 255", threshold, parts)
+	}
+
+	xCoordinates, err := shamirXCoordinates(reader, parts)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([][]byte, parts)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][len(secret)] = xCoordinates[i]
+	}
+
+	coefficients := make([]byte, threshold)
+	for idx, b := range secret {
+		// The secret byte is the intercept of a random polynomial of degree threshold-1.
+		coefficients[0] = b
+		if _, err := io.ReadFull(reader, coefficients[1:]); err != nil {
+			return nil, err
+		}
+
+		for i, x := range xCoordinates {
+			shares[i][idx] = gfEvaluate(coefficients, x)
+		}
+	}
+
+	return shares, nil
+}
+
+// shamirXCoordinates returns count distinct, randomly ordered values from 1 to 255.
+func shamirXCoordinates(reader io.Reader, count int) ([]byte, error) {
+	values := make([]byte, 255)
+	for i := range values {
+		values[i] = byte(i + 1)
+	}
+
+	for i := len(values) - 1; i > 0; i-- {
+		j, err := rand.Int(reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return nil, err
+		}
+		values[i], values[j.Int64()] = values[j.Int64()], values[i]
+	}
+
+	return values[:count], nil
+}
+
+// gfEvaluate evaluates the polynomial with the given coefficients, lowest degree first, at x.
+func gfEvaluate(coefficients []byte, x byte) byte {
+	var result byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result = gfMultiply(result, x) ^ coefficients[i]
+	}
+	return result
+}
+
+// gfMultiply multiplies a and b in GF(2^8), using the AES reducing polynomial x^8 + x^4 + x^3 + x + 1.
+func gfMultiply(a, b byte) byte {
+	var result byte
+	for b > 0 {
+		if b&1 != 0 {
+			result ^= a
+		}
+		carry := a & 0x80
+		a <<= 1
+		if carry != 0 {
+			a ^= 0x1b
+		}
+		b >>= 1
+	}
+	return result
+}
@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestShamirSplit(t *testing.T) {
+	secret := []byte("correct horse battery staple")
+
+	shares, err := shamirSplit(rand.Reader, secret, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("got %d shares; want 5", len(shares))
+	}
+
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		var selected [][]byte
+		for _, i := range subset {
+			selected = append(selected, shares[i])
+		}
+
+		got, err := shamirCombine(selected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, secret) {
+			t.Errorf("shares %v combined to %q; want %q", subset, got, secret)
+		}
+	}
+
+	got, err := shamirCombine(shares[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(got, secret) {
+		t.Error("expected fewer than threshold shares not to reconstruct the secret")
+	}
+
+	for _, c := range []struct{ parts, threshold int }{{3, 1}, {2, 3}, {256, 2}} {
+		if _, err := shamirSplit(rand.Reader, secret, c.parts, c.threshold); err == nil {
+			t.Errorf("expected error splitting into %d parts with threshold %d", c.parts, c.threshold)
+		}
+	}
+}
+
+func TestGFMultiply(t *testing.T) {
+	// Example from FIPS-197 section 4.2.
+	if got := gfMultiply(0x57, 0x83); got != 0xc1 {
+		t.Errorf("got %#x; want 0xc1", got)
+	}
+
+	for a := 1; a < 256; a++ {
+		if got := gfMultiply(byte(a), gfInverse(byte(a))); got != 1 {
+			t.Errorf("%#x * inverse = %#x; want 1", a, got)
+		}
+	}
+}
+
+// shamirCombine reconstructs the secret from shares produced by shamirSplit using Lagrange interpolation at zero.
+// Combining fewer shares than the threshold yields an unrelated value rather than an error.
+func shamirCombine(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, fmt.Errorf("at least 2 shares are required")
+	}
+
+	length := len(shares[0]) - 1
+	xs := make([]byte, len(shares))
+	for i, share := range shares {
+		if len(share) != length+1 {
+			return nil, fmt.Errorf("shares must all be the same length")
+		}
+		xs[i] = share[length]
+	}
+
+	secret := make([]byte, length)
+	for idx := range secret {
+		var value byte
+		for i, xi := range xs {
+			basis := byte(1)
+			for j, xj := range xs {
+				if i == j {
+					continue
+				}
+				if xi == xj {
+					return nil, fmt.Errorf("duplicate share x coordinate %d", xi)
+				}
+				basis = gfMultiply(basis, gfMultiply(xj, gfInverse(xi^xj)))
+			}
+			value ^= gfMultiply(shares[i][idx], basis)
+		}
+		secret[idx] = value
+	}
+
+	return secret, nil
+}
+
+// gfInverse returns the multiplicative inverse of a non-zero a in GF(2^8), which is a^254.
+func gfInverse(a byte) byte {
+	result := byte(1)
+	for i := 0; i < 254; i++ {
+		result = gfMultiply(result, a)
+	}
+	return result
+}
+
+// testAccShamirCheck verifies that every threshold-sized window of
+// the named resource's shamir_shares reconstructs its hex value, and that
+// fewer shares do not.
+func testAccShamirCheck(id string, threshold int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("Not found: %s", id)
+		}
+
+		count, err := strconv.Atoi(rs.Primary.Attributes["shamir_shares.#"])
+		if err != nil {
+			return err
+		}
+
+		shares := make([][]byte, count)
+		for i := range shares {
+			if shares[i], err = hex.DecodeString(rs.Primary.Attributes[fmt.Sprintf("shamir_shares.%d", i)]); err != nil {
+				return err
+			}
+		}
+
+		want := rs.Primary.Attributes["hex"]
+		for i := 0; i+threshold <= count; i++ {
+			secret, err := shamirCombine(shares[i : i+threshold])
+			if err != nil {
+				return err
+			}
+			if got := hex.EncodeToString(secret); got != want {
+				return fmt.Errorf("shares %d to %d combined to %s; want %s", i, i+threshold-1, got, want)
+			}
+		}
+
+		secret, err := shamirCombine(shares[:threshold-1])
+		if err != nil {
+			return err
+		}
+		if hex.EncodeToString(secret) == want {
+			return fmt.Errorf("expected %d shares not to reconstruct %s", threshold-1, want)
+		}
+
+		return nil
+	}
+}
@@ -4,19 +4,267 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"math/big"
 	"sort"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// passwordSchemaV3 uses passwordSchemaV2 to obtain the V2 version of the Schema key-value entries but requires that the
+// bcrypt_cost, sha256_hash, crypt_sha512, quantity, mutual_distance, passphrase, word_count, word_separator, wordlist,
+// results, secret_file, secret_name, trailing_newline, result_base64, result_hex, derive, hash_algorithm,
+// argon2_memory, argon2_iterations, argon2_parallelism, password_hash, replace_on_keeper_change and generation entries
+// be configured, that the length entry be altered to be optional and that the keepers entry be altered not to be
+// ForceNew.
+func passwordSchemaV3() map[string]*schema.Schema {
+	passwordSchema := passwordSchemaV2()
+	passwordSchema["bcrypt_cost"] = &schema.Schema{
+		Description: "The cost factor used when generating `bcrypt_hash`. Must be between 4 and 31. " +
+			"Default value is `10`.",
+		Type:             schema.TypeInt,
+		Optional:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(bcrypt.MinCost, bcrypt.MaxCost)),
+	}
+
+	passwordSchema["sha256_hash"] = &schema.Schema{
+		Description: "A hex-encoded SHA-256 hash of the generated random string.",
+		Type:        schema.TypeString,
+		Computed:    true,
+		Sensitive:   true,
+	}
+
+	passwordSchema["crypt_sha512"] = &schema.Schema{
+		Description: "A SHA-512 crypt string of the generated random string, of the form `$6$<salt>$<hash>` " +
+			"with a random salt, as used in `/etc/shadow` for Linux user management.",
+		Type:      schema.TypeString,
+		Computed:  true,
+		Sensitive: true,
+	}
+
+	passwordSchema["hash_algorithm"] = &schema.Schema{
+		Description: "The algorithm used to generate `password_hash`, one of `bcrypt` or `argon2id`. bcrypt only " +
+			"hashes the first 72 bytes of its input, so `argon2id` should be used for longer results, such as " +
+			"passphrases. `bcrypt_hash` is always generated with bcrypt. Default value is `bcrypt`.",
+		Type:             schema.TypeString,
+		Optional:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(hashAlgorithms, false)),
+	}
+
+	passwordSchema["argon2_memory"] = &schema.Schema{
+		Description: fmt.Sprintf("The amount of memory, in KiB, used when generating `password_hash` with "+
+			"argon2id. Requires `hash_algorithm` to be `argon2id`. Default value is `%d`.", argon2DefaultMemory),
+		Type:             schema.TypeInt,
+		Optional:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(8, math.MaxInt32)),
+	}
+
+	passwordSchema["argon2_iterations"] = &schema.Schema{
+		Description: fmt.Sprintf("The number of passes over the memory made when generating `password_hash` with "+
+			"argon2id. Requires `hash_algorithm` to be `argon2id`. Default value is `%d`.", argon2DefaultIterations),
+		Type:             schema.TypeInt,
+		Optional:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(1, math.MaxInt32)),
+	}
+
+	passwordSchema["argon2_parallelism"] = &schema.Schema{
+		Description: fmt.Sprintf("The number of threads used when generating `password_hash` with argon2id. "+
+			"Must be between 1 and 255. Requires `hash_algorithm` to be `argon2id`. Default value is `%d`.",
+			argon2DefaultParallelism),
+		Type:             schema.TypeInt,
+		Optional:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(1, math.MaxUint8)),
+	}
+
+	passwordSchema["password_hash"] = &schema.Schema{
+		Description: "A hash of the generated random string using `hash_algorithm`. bcrypt hashes are in the " +
+			"modular crypt format, e.g., `$2a$10$...`, and argon2id hashes are in the PHC string format, e.g., " +
+			"`$argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>`.",
+		Type:      schema.TypeString,
+		Computed:  true,
+		Sensitive: true,
+	}
+
+	passwordSchema["quantity"] = &schema.Schema{
+		Description: "The number of distinct passwords to generate into `results`. Each password is generated " +
+			"using the same configuration as `result`, which is always the first element of `results`.",
+		Type:             schema.TypeInt,
+		Optional:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+	}
+
+	passwordSchema["mutual_distance"] = &schema.Schema{
+		Description: "When set, no two passwords in `results` will share a common substring of this length. " +
+			"Requires `quantity` to be set.",
+		Type:             schema.TypeInt,
+		Optional:         true,
+		ForceNew:         true,
+		RequiredWith:     []string{"quantity"},
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+	}
+
+	passwordSchema["length"].Description = "The length of the string desired. The minimum value for length is 1 " +
+		"and, length must also be >= (`min_upper` + `min_lower` + `min_numeric` + `min_special`). Exactly one of " +
+		"`length` or `word_count` must be set."
+	passwordSchema["length"].Required = false
+	passwordSchema["length"].Optional = true
+	passwordSchema["length"].ExactlyOneOf = []string{"length", "word_count"}
+
+	passwordSchema["passphrase"] = &schema.Schema{
+		Description: "Generate a passphrase of `word_count` randomly chosen words joined by `word_separator` " +
+			"instead of a string of random characters. When `true`, `word_count` must be set and the character " +
+			"class arguments (e.g., `upper`, `min_numeric`) are ignored. Default value is `false`.",
+		Type:         schema.TypeBool,
+		Optional:     true,
+		ForceNew:     true,
+		RequiredWith: []string{"word_count"},
+	}
+
+	passwordSchema["word_count"] = &schema.Schema{
+		Description:      "The number of words in the passphrase. Requires `passphrase` to be `true`.",
+		Type:             schema.TypeInt,
+		Optional:         true,
+		ForceNew:         true,
+		ExactlyOneOf:     []string{"length", "word_count"},
+		RequiredWith:     []string{"passphrase"},
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+	}
+
+	passwordSchema["word_separator"] = &schema.Schema{
+		Description: "The string used to separate words in the passphrase. Default value is ` ` (a single space).",
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    true,
+	}
+
+	passwordSchema["wordlist"] = &schema.Schema{
+		Description: "A newline-delimited list of candidate words to draw the passphrase from, in place of the " +
+			"built-in list. The list must contain at least 2 words and no word may contain `word_separator`. " +
+			"Requires `passphrase` to be `true`.",
+		Type:         schema.TypeString,
+		Optional:     true,
+		ForceNew:     true,
+		RequiredWith: []string{"passphrase"},
+	}
+
+	passwordSchema["secret_file"] = &schema.Schema{
+		Description: "The generated random string rendered as the contents of a secret file, for example for use " +
+			"with `docker secret create`. The contents are preceded by a `# <secret_name>` header line when " +
+			"`secret_name` is set and followed by a newline when `trailing_newline` is `true`.",
+		Type:      schema.TypeString,
+		Computed:  true,
+		Sensitive: true,
+	}
+
+	passwordSchema["secret_name"] = &schema.Schema{
+		Description: "The name to include in a header line at the start of `secret_file`. No header is " +
+			"included when unset.",
+		Type:     schema.TypeString,
+		Optional: true,
+		ForceNew: true,
+	}
+
+	passwordSchema["trailing_newline"] = &schema.Schema{
+		Description: "Whether `secret_file` ends with a newline. Some systems read the newline as part of the " +
+			"secret, so it is omitted by default. Default value is `false`.",
+		Type:     schema.TypeBool,
+		Optional: true,
+		ForceNew: true,
+	}
+
+	passwordSchema["result_base64"] = &schema.Schema{
+		Description: "The generated random string encoded as standard, padded base64.",
+		Type:        schema.TypeString,
+		Computed:    true,
+		Sensitive:   true,
+	}
+
+	passwordSchema["result_hex"] = &schema.Schema{
+		Description: "The generated random string encoded as lowercase hexadecimal digits.",
+		Type:        schema.TypeString,
+		Computed:    true,
+		Sensitive:   true,
+	}
+
+	passwordSchema["derive"] = &schema.Schema{
+		Description: "Derive the result from `root_key`, `environment` and `purpose` using HKDF-SHA256 instead of " +
+			"generating it randomly, so that the same inputs always produce the same result without it needing " +
+			"to be stored. The configured character class arguments continue to apply. Changing any of the " +
+			"inputs replaces the result, so rotating `root_key` rotates every password derived from it at once. " +
+			"Anyone with access to `root_key` can derive every password from it, so it should be protected at " +
+			"least as well as the passwords themselves.",
+		Type:          schema.TypeList,
+		Optional:      true,
+		ForceNew:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"passphrase", "quantity"},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"root_key": {
+					Description:      "The secret input keying material that passwords are derived from.",
+					Type:             schema.TypeString,
+					Required:         true,
+					ForceNew:         true,
+					Sensitive:        true,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsNotEmpty),
+				},
+
+				"environment": {
+					Description: "The environment the password is for, e.g. `production`.",
+					Type:        schema.TypeString,
+					Required:    true,
+					ForceNew:    true,
+				},
+
+				"purpose": {
+					Description: "What the password is used for, e.g. `database`. Different purposes derive " +
+						"unrelated passwords from the same `root_key` and `environment`.",
+					Type:     schema.TypeString,
+					Required: true,
+					ForceNew: true,
+				},
+			},
+		},
+	}
+
+	allowKeepersUpdate(passwordSchema)
+
+	passwordSchema["generation"] = generationSchema(true)
+
+	passwordSchema["results"] = &schema.Schema{
+		Description: "The generated random strings, when `quantity` is set.",
+		Type:        schema.TypeList,
+		Computed:    true,
+		Sensitive:   true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+
+	return passwordSchema
+}
+
 // passwordSchemaV2 uses passwordSchemaV1 to obtain the V1 version of the Schema key-value entries but requires that
 // the numeric entry be configured and that the number entry be altered to include ConflictsWith.
 func passwordSchemaV2() map[string]*schema.Schema {
@@ -50,10 +298,11 @@ func passwordSchemaV2() map[string]*schema.Schema {
 func passwordSchemaV1() map[string]*schema.Schema {
 	passwordSchema := passwordSchemaV0()
 	passwordSchema["bcrypt_hash"] = &schema.Schema{
-		Description: "A bcrypt hash of the generated random string.",
-		Type:        schema.TypeString,
-		Computed:    true,
-		Sensitive:   true,
+		Description: "A bcrypt hash of the generated random string. bcrypt only hashes the first 72 bytes of its " +
+			"input, so a warning is returned when the result is longer.",
+		Type:      schema.TypeString,
+		Computed:  true,
+		Sensitive: true,
 	}
 
 	return passwordSchema
@@ -69,8 +318,21 @@ func passwordSchemaV0() map[string]*schema.Schema {
 	return passwordSchema
 }
 
-// stringSchemaV2 uses stringSchemaV1 to obtain the V1 version of the Schema key-value entries but requires that
-// the numeric entry be configured and that the number entry be altered to include ConflictsWith.
+// stringSchemaV3 uses stringSchemaV2 to obtain the V2 version of the Schema key-value entries but requires that
+// the generation entry be configured.
+func stringSchemaV3() map[string]*schema.Schema {
+	stringSchema := stringSchemaV2()
+	stringSchema["generation"] = generationSchema(true)
+
+	return stringSchema
+}
+
+// stringSchemaV2 uses stringSchemaV1 to obtain the V1 version of the Schema key-value entries but requires that the
+// numeric, prefix, suffix, bip39_word_count, seed, length_unit, case, grammar, pattern, regex, require_each_class,
+// min_*_pct, append_luhn, check_digit, result_base64, result_hex, quantity, results, sensitive, sensitive_result,
+// result_length and replace_on_keeper_change entries be configured, that the number entry be altered to include
+// ConflictsWith, that the override_special entry be altered to warn of duplicate characters, that the length entry be
+// altered to be optional and that the keepers entry be altered not to be ForceNew.
 func stringSchemaV2() map[string]*schema.Schema {
 	stringSchema := stringSchemaV1()
 
@@ -94,6 +356,238 @@ func stringSchemaV2() map[string]*schema.Schema {
 		ConflictsWith: []string{"number"},
 	}
 
+	stringSchema["prefix"] = &schema.Schema{
+		Description: "Arbitrary string to prefix the result with. The prefix is not counted towards `length`.",
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    true,
+	}
+
+	stringSchema["suffix"] = &schema.Schema{
+		Description: "Arbitrary string to suffix the result with. The suffix is not counted towards `length`.",
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    true,
+	}
+
+	stringSchema["bip39_word_count"] = &schema.Schema{
+		Description: "Generate the result as a BIP-39 mnemonic of this many words from the BIP-39 English " +
+			"wordlist, separated by single spaces. The final word includes a checksum of the random entropy " +
+			"encoded by the mnemonic. Must be one of `12`, `15`, `18`, `21` or `24`, corresponding to 128 to 256 " +
+			"bits of entropy. When set, the character class arguments (e.g., `upper`, `min_numeric`) are ignored.",
+		Type:             schema.TypeInt,
+		Optional:         true,
+		ForceNew:         true,
+		ExactlyOneOf:     []string{"length", "grammar", "pattern", "regex", "bip39_word_count"},
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntInSlice(bip39WordCounts)),
+	}
+
+	stringSchema["seed"] = &schema.Schema{
+		Description: "Arbitrary string with which to seed the random number generator, in order to produce the " +
+			"same result each time the resource is created with the same configuration, e.g., for test fixtures.\n" +
+			"\n" +
+			"**Important:** When `seed` is set the result is generated using a non-cryptographic random number " +
+			"generator, and anyone who knows the seed can reproduce the result. The result is therefore not " +
+			"suitable for use as a secret. Even with an identical seed, it is not guaranteed that the same result " +
+			"will be produced across different versions of the provider.",
+		Type:     schema.TypeString,
+		Optional: true,
+		ForceNew: true,
+	}
+
+	stringSchema["length_unit"] = &schema.Schema{
+		Description: "The unit in which `length` and the `min_*` arguments are measured, one of `bytes`, `runes` " +
+			"(Unicode code points) or `graphemes` (user-perceived characters, e.g., `e` followed by a combining " +
+			"accent, or an emoji with a skin tone modifier). The characters of `override_special` are split into " +
+			"units in the same way. Only affects the result when `override_special` contains multi-byte " +
+			"characters. Default value is `bytes`.",
+		Type:             schema.TypeString,
+		Optional:         true,
+		ForceNew:         true,
+		ConflictsWith:    []string{"grammar", "pattern", "regex", "bip39_word_count"},
+		ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(lengthUnits, false)),
+	}
+
+	stringSchema["case"] = &schema.Schema{
+		Description: "Change the case of the whole result, including `prefix` and `suffix`, after it has been " +
+			"generated, one of `lower`, `upper` or `mixed`. `mixed` leaves the result unchanged. `lower` cannot be " +
+			"used with `min_upper`, `upper` cannot be used with `min_lower`, and neither can be used with " +
+			"`no_palindrome`, `no_consecutive_duplicates` or `min_unique`, as the change of case could break " +
+			"them. Default value is `mixed`.",
+		Type:             schema.TypeString,
+		Optional:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(stringCases, false)),
+	}
+
+	stringSchema["override_special"].ValidateDiagFunc = warnDuplicateChars
+
+	allowKeepersUpdate(stringSchema)
+
+	stringSchema["length"].Description = "The length of the string desired. The minimum value for length is 1 " +
+		"and, length must also be >= (`min_upper` + `min_lower` + `min_numeric` + `min_special`). Exactly one of " +
+		"`length`, `grammar`, `pattern`, `regex` or `bip39_word_count` must be set."
+	stringSchema["length"].Required = false
+	stringSchema["length"].Optional = true
+	stringSchema["length"].ExactlyOneOf = []string{"length", "grammar", "pattern", "regex", "bip39_word_count"}
+
+	stringSchema["grammar"] = &schema.Schema{
+		Description: "Generate the result by expanding a BNF-like grammar instead of choosing random characters. " +
+			"Each line defines a rule of the form `<name> ::= <other> \"literal\" | \"alternative\"`, where " +
+			"terminals are double-quoted and each alternative is chosen with equal probability. The first rule is " +
+			"expanded to produce the result. Rules must not be recursive, and the longest possible expansion " +
+			"must not exceed the `max_string_length` of the provider or 1048576 bytes. When set, the character " +
+			"class arguments (e.g., `upper`, `min_numeric`) are ignored.",
+		Type:         schema.TypeString,
+		Optional:     true,
+		ForceNew:     true,
+		ExactlyOneOf: []string{"length", "grammar", "pattern", "regex", "bip39_word_count"},
+		ValidateDiagFunc: validation.ToDiagFunc(func(i interface{}, k string) ([]string, []error) {
+			if _, err := parseGrammar(i.(string)); err != nil {
+				return nil, []error{fmt.Errorf("expected %s to be a valid grammar: %w", k, err)}
+			}
+			return nil, nil
+		}),
+	}
+
+	stringSchema["pattern"] = &schema.Schema{
+		Description: "Generate the result from a template in which each `A` is replaced by a random uppercase " +
+			"letter, each `a` by a random lowercase letter, each `9` by a random digit and each `*` by a random " +
+			"character from those enabled by `upper`, `lower`, `numeric`, `special` and `override_special`. " +
+			"Any other character, or any character preceded by `\\`, is included as-is, e.g., `AAA-999-aa`. " +
+			"When set, the `min_*` arguments must not be set.",
+		Type:         schema.TypeString,
+		Optional:     true,
+		ForceNew:     true,
+		ExactlyOneOf: []string{"length", "grammar", "pattern", "regex", "bip39_word_count"},
+		ConflictsWith: []string{"min_upper", "min_lower", "min_numeric", "min_special",
+			"min_upper_pct", "min_lower_pct", "min_numeric_pct", "min_special_pct", "require_each_class"},
+	}
+
+	stringSchema["regex"] = &schema.Schema{
+		Description: "Generate a random result matching a restricted regular expression, e.g., `[A-Z]{3}[0-9]{4}`. " +
+			"The expression is a sequence of literal characters, characters escaped with `\\`, `\\d`, `\\w` " +
+			"and character classes such as `[A-Za-z0-9_-]`, each optionally followed by `?`, `{n}` or `{m,n}`, " +
+			"where `n` is at most 1000. Unbounded quantifiers (`*`, `+`, `{m,}`), anchors (`^`, `$`), `.`, groups, " +
+			"alternation and negated character classes are not supported. The number of repetitions and each " +
+			"character are chosen uniformly. When set, the `min_*` arguments must not be set.",
+		Type:         schema.TypeString,
+		Optional:     true,
+		ForceNew:     true,
+		ExactlyOneOf: []string{"length", "grammar", "pattern", "regex", "bip39_word_count"},
+		ConflictsWith: []string{"min_upper", "min_lower", "min_numeric", "min_special",
+			"min_upper_pct", "min_lower_pct", "min_numeric_pct", "min_special_pct", "require_each_class"},
+		ValidateDiagFunc: validation.ToDiagFunc(func(i interface{}, k string) ([]string, []error) {
+			if _, err := parseRegex(i.(string)); err != nil {
+				return nil, []error{fmt.Errorf("expected %s to be a valid regex: %w", k, err)}
+			}
+			return nil, nil
+		}),
+	}
+
+	stringSchema["require_each_class"] = &schema.Schema{
+		Description: "Include at least one character from each of the enabled character classes, i.e., those of " +
+			"`upper`, `lower`, `numeric` and `special` that are `true`, as if each of their `min_*` arguments " +
+			"were at least `1`. `length` must therefore be at least the number of enabled classes. Default " +
+			"value is `false`.",
+		Type:     schema.TypeBool,
+		Optional: true,
+		ForceNew: true,
+	}
+
+	for _, class := range []struct{ key, name string }{
+		{"min_upper", "uppercase alphabet"},
+		{"min_lower", "lowercase alphabet"},
+		{"min_numeric", "numeric"},
+		{"min_special", "special"},
+	} {
+		stringSchema[class.key+"_pct"] = &schema.Schema{
+			Description: fmt.Sprintf("Minimum number of %s characters in the result, as a fraction of `length` "+
+				"between 0 and 1, e.g., `0.2` for at least 20%%. The number is rounded up. Conflicts with `%s`.",
+				class.name, class.key),
+			Type:             schema.TypeFloat,
+			Optional:         true,
+			ForceNew:         true,
+			ConflictsWith:    []string{class.key},
+			ValidateDiagFunc: validation.ToDiagFunc(validation.FloatBetween(0, 1)),
+		}
+	}
+
+	stringSchema["append_luhn"] = &schema.Schema{
+		Description: "Append a Luhn check digit to the generated characters, before any `suffix`, e.g., for " +
+			"account numbers that must pass a Luhn check. The check digit is not counted towards `length`. When " +
+			"`true`, the only characters enabled must be digits, i.e., `upper`, `lower` and `special` must be " +
+			"`false`. Cannot be used with `grammar`, `pattern`, `regex` or `bip39_word_count`. Default value is " +
+			"`false`.",
+		Type:          schema.TypeBool,
+		Optional:      true,
+		ForceNew:      true,
+		ConflictsWith: []string{"grammar", "pattern", "regex", "bip39_word_count"},
+	}
+
+	stringSchema["check_digit"] = &schema.Schema{
+		Description: "The Luhn check digit appended to the generated characters when `append_luhn` is `true`.",
+		Type:        schema.TypeInt,
+		Computed:    true,
+	}
+
+	stringSchema["result_base64"] = &schema.Schema{
+		Description: "The generated random string encoded as standard, padded base64.",
+		Type:        schema.TypeString,
+		Computed:    true,
+	}
+
+	stringSchema["result_hex"] = &schema.Schema{
+		Description: "The generated random string encoded as lowercase hexadecimal digits.",
+		Type:        schema.TypeString,
+		Computed:    true,
+	}
+
+	stringSchema["quantity"] = &schema.Schema{
+		Description: "The number of distinct strings to generate into `results`, e.g., for a pool of values, in " +
+			"place of a `random_string` resource for each. Each string is generated using the same configuration " +
+			"as `result`, which is always the first element of `results`. Cannot be used with `sensitive`.",
+		Type:             schema.TypeInt,
+		Optional:         true,
+		ForceNew:         true,
+		ConflictsWith:    []string{"sensitive"},
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+	}
+
+	stringSchema["results"] = &schema.Schema{
+		Description: "The generated random strings, when `quantity` is set.",
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+
+	stringSchema["sensitive"] = &schema.Schema{
+		Description: "Store the generated random string in `sensitive_result`, which is hidden in plan output, " +
+			"instead of `result`. As whether an attribute is sensitive is fixed by the schema, `result`, " +
+			"`result_base64` and `result_hex` are left empty and `id` is set to `none` when `true`, so references " +
+			"to them must be changed to `sensitive_result`, and any value derived from it is also sensitive. " +
+			"Changing this argument replaces the resource. Default value is `false`.",
+		Type:     schema.TypeBool,
+		Optional: true,
+		ForceNew: true,
+	}
+
+	stringSchema["sensitive_result"] = &schema.Schema{
+		Description: "The generated random string when `sensitive` is `true`, otherwise empty.",
+		Type:        schema.TypeString,
+		Computed:    true,
+		Sensitive:   true,
+	}
+
+	stringSchema["result_length"] = &schema.Schema{
+		Description: "The length of `result` in bytes, including `prefix`, `suffix` and any check digit, e.g., to " +
+			"validate the length of the result when it is not given by `length`.",
+		Type:     schema.TypeInt,
+		Computed: true,
+	}
+
 	return stringSchema
 }
 
@@ -193,13 +687,61 @@ func passwordStringSchema() map[string]*schema.Schema {
 
 		"override_special": {
 			Description: "Supply your own list of special characters to use for string generation.  This " +
-				"overrides the default character list in the special argument.  The `special` argument must " +
-				"still be set to true for any overwritten characters to be used in generation.",
-			Type:     schema.TypeString,
+				"overrides the default character list in the special argument, including any " +
+				"`default_special_override` set in the provider configuration.  The `special` argument must " +
+				"still be set to true for any overwritten characters to be used in generation, otherwise a warning is " +
+				"returned when the resource is created. A warning is also returned when it contains letters or " +
+				"digits enabled by `upper`, `lower` or `numeric`. An empty string means that there are no special " +
+				"characters, in which case `min_special` must be `0`.",
+			Type:          schema.TypeString,
+			Optional:      true,
+			ForceNew:      true,
+			ConflictsWith: []string{"special_set"},
+		},
+
+		"special_set": {
+			Description: "Supply your own set of special characters to use for string generation, each of which " +
+				"must be a single character, as an alternative to `override_special`. This overrides the default " +
+				"character list in the special argument, including any `default_special_override` set in the " +
+				"provider configuration. The `special` argument must still be set to true for these characters to " +
+				"be used in generation. Must not be empty when set. Conflicts with `override_special`.",
+			Type:     schema.TypeSet,
+			Optional: true,
+			ForceNew: true,
+			MinItems: 1,
+			Elem: &schema.Schema{
+				Type:             schema.TypeString,
+				ValidateDiagFunc: isSingleRune,
+			},
+			ConflictsWith: []string{"override_special"},
+		},
+
+		"no_palindrome": {
+			Description: "Ensure that the result does not read the same forwards and backwards. When `true`, " +
+				"`length` must be at least 2. Default value is `false`.",
+			Type:     schema.TypeBool,
+			Optional: true,
+			ForceNew: true,
+		},
+
+		"no_consecutive_duplicates": {
+			Description: "Ensure that no character appears twice in a row in the result. When `true`, the enabled " +
+				"character classes must provide at least 2 distinct characters. Default value is `false`.",
+			Type:     schema.TypeBool,
 			Optional: true,
 			ForceNew: true,
 		},
 
+		"min_unique": {
+			Description: "Minimum number of distinct characters in the result, e.g., to prevent results such as " +
+				"`aaaaaaaa`. Must be no more than `length` or the number of distinct characters enabled by the " +
+				"character classes. Default value is `0`.",
+			Type:             schema.TypeInt,
+			Optional:         true,
+			ForceNew:         true,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+		},
+
 		"result": {
 			Description: "The generated random string.",
 			Type:        schema.TypeString,
@@ -213,117 +755,784 @@ func passwordStringSchema() map[string]*schema.Schema {
 	}
 }
 
-func createStringFunc(sensitive bool) func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
-	return func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
-		const numChars = "0123456789"
-		const lowerChars = "abcdefghijklmnopqrstuvwxyz"
-		const upperChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
-		var (
-			specialChars = "!@#$%&*()-_=+[]{}<>:?"
-			diags        diag.Diagnostics
-		)
-
-		length := d.Get("length").(int)
-		upper := d.Get("upper").(bool)
-		minUpper := d.Get("min_upper").(int)
-		lower := d.Get("lower").(bool)
-		minLower := d.Get("min_lower").(int)
+// createStringFunc returns a CreateContextFunc that generates the result of a resource of type typeName, which is
+// either random_string or random_password. The id of a random_password is always none, as its result is sensitive.
+func createStringFunc(typeName string) func(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return func(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 		number := d.Get("number").(bool)
 		numeric := d.Get("numeric").(bool)
-		minNumeric := d.Get("min_numeric").(int)
-		special := d.Get("special").(bool)
-		minSpecial := d.Get("min_special").(int)
-		overrideSpecial := d.Get("override_special").(string)
 
-		if length < minUpper+minLower+minNumeric+minSpecial {
-			return append(diags, diag.Diagnostic{
-				Severity: diag.Error,
-				Summary:  fmt.Sprintf("length (%d) must be >= min_upper + min_lower + min_numeric + min_special (%d)", length, minUpper+minLower+minNumeric+minSpecial),
-			})
+		result, checkDigit, diags := generateStringValue(d, meta, stringRandReader(d, meta, typeName))
+		if diags.HasError() {
+			return diags
+		}
+		if _, ok := d.GetOk("pattern"); ok || d.Get("length").(int) > 0 {
+			diags = append(diags, warnOverrideSpecialIgnored(d)...)
+			diags = append(diags, warnOverrideSpecialOverlap(d)...)
 		}
 
-		if overrideSpecial != "" {
-			specialChars = overrideSpecial
+		// append_luhn is only present in the random_string schema.
+		if v, ok := d.GetOk("append_luhn"); ok && v.(bool) {
+			if err := d.Set("check_digit", checkDigit); err != nil {
+				return append(diags, diag.Errorf("error setting check_digit: %s", err)...)
+			}
 		}
 
-		var chars = string("")
-		if upper {
-			chars += upperChars
+		// sensitive is only present in the random_string schema.
+		sensitiveResult := false
+		if v, ok := d.GetOk("sensitive"); ok {
+			sensitiveResult = v.(bool)
 		}
-		if lower {
-			chars += lowerChars
+
+		if sensitiveResult {
+			if err := d.Set("sensitive_result", string(result)); err != nil {
+				return append(diags, diag.Errorf("error setting sensitive_result: %s", err)...)
+			}
+			if err := d.Set("result_length", len(result)); err != nil {
+				return append(diags, diag.Errorf("error setting result_length: %s", err)...)
+			}
+		} else {
+			if err := d.Set("result", string(result)); err != nil {
+				return append(diags, diag.Errorf("error setting result: %s", err)...)
+			}
+			if err := setResultEncodings(d, string(result)); err != nil {
+				return append(diags, diag.Errorf("error setting result encodings: %s", err)...)
+			}
 		}
-		if numeric {
-			chars += numChars
+
+		if err := d.Set("number", number); err != nil {
+			return append(diags, diag.Errorf("error setting number: %s", err)...)
 		}
-		if special {
-			chars += specialChars
+		if err := d.Set("numeric", numeric); err != nil {
+			return append(diags, diag.Errorf("error setting numeric: %s", err)...)
 		}
 
-		minMapping := map[string]int{
-			numChars:     minNumeric,
-			lowerChars:   minLower,
-			upperChars:   minUpper,
-			specialChars: minSpecial,
+		if typeName == "random_password" || sensitiveResult {
+			d.SetId("none")
+		} else {
+			d.SetId(string(result))
 		}
-		var result = make([]byte, 0, length)
-		for k, v := range minMapping {
-			s, err := generateRandomBytes(&k, v)
-			if err != nil {
-				return append(diags, diag.Errorf("error generating random bytes: %s", err)...)
-			}
-			result = append(result, s...)
+		return diags
+	}
+}
+
+// generateStringValue generates a result as configured in d using reader, then appends any check digit, adds any
+// prefix and suffix and changes its case. The check digit is returned separately, and is only meaningful when
+// append_luhn is true.
+func generateStringValue(d *schema.ResourceData, meta interface{}, reader io.Reader) ([]byte, int, diag.Diagnostics) {
+	var result []byte
+	var diags diag.Diagnostics
+	// prefix, suffix, grammar, pattern, regex, bip39_word_count and seed are only present in the random_string schema.
+	if v, ok := d.GetOk("grammar"); ok {
+		result, diags = generateGrammarResult(reader, v.(string))
+	} else if v, ok := d.GetOk("pattern"); ok {
+		result, diags = generatePatternResult(d, meta, reader, v.(string))
+	} else if v, ok := d.GetOk("regex"); ok {
+		result, diags = generateRegexResult(reader, v.(string))
+	} else if v, ok := d.GetOk("bip39_word_count"); ok {
+		mnemonic, err := generateBIP39Mnemonic(reader, v.(int))
+		if err != nil {
+			return nil, 0, diag.Errorf("error generating bip39 mnemonic: %s", err)
 		}
-		s, err := generateRandomBytes(&chars, length-len(result))
+		result = []byte(mnemonic)
+	} else {
+		result, diags = generateStringResult(d, meta, reader)
+	}
+	if diags.HasError() {
+		return nil, 0, diags
+	}
+
+	var checkDigit int
+	// append_luhn is only present in the random_string schema.
+	if v, ok := d.GetOk("append_luhn"); ok && v.(bool) {
+		var err error
+		checkDigit, err = luhnCheckDigit(string(result))
 		if err != nil {
-			return append(diags, diag.Errorf("error generating random bytes: %s", err)...)
+			return nil, 0, append(diags, diag.Errorf("error computing check digit: %s", err)...)
 		}
-		result = append(result, s...)
-		order := make([]byte, len(result))
-		if _, err := rand.Read(order); err != nil {
-			return append(diags, diag.Errorf("error generating random bytes: %s", err)...)
+		result = append(result, byte('0'+checkDigit))
+	}
+
+	if v, ok := d.GetOk("prefix"); ok {
+		result = append([]byte(v.(string)), result...)
+	}
+	if v, ok := d.GetOk("suffix"); ok {
+		result = append(result, v.(string)...)
+	}
+	// case is only present in the random_string schema.
+	if v, ok := d.GetOk("case"); ok {
+		result = changeCase(result, v.(string))
+	}
+
+	return result, checkDigit, diags
+}
+
+// warnOverrideSpecialIgnored returns a warning when override_special is set in d but special is false, as the
+// characters of override_special are then never used. The warning is returned when creating the resource, as
+// CustomizeDiff cannot return warnings and ValidateDiagFunc cannot see other attributes.
+func warnOverrideSpecialIgnored(d *schema.ResourceData) diag.Diagnostics {
+	if d.Get("override_special").(string) == "" || d.Get("special").(bool) {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "override_special has no effect",
+		Detail: "The characters of override_special are only used when special is true, so none of them appear " +
+			"in the result. Set special to true to use them, or remove override_special.",
+		AttributePath: cty.GetAttrPath("override_special"),
+	}}
+}
+
+// warnOverrideSpecialOverlap returns a warning when special is true in d and override_special contains characters of
+// another enabled character class, listing the characters in the order they first appear. Such a character cannot be
+// told apart from one of the other class, although it still counts only towards min_special when generating.
+func warnOverrideSpecialOverlap(d *schema.ResourceData) diag.Diagnostics {
+	if !d.Get("special").(bool) {
+		return nil
+	}
+
+	var enabled string
+	if d.Get("upper").(bool) {
+		enabled += upperChars
+	}
+	if d.Get("lower").(bool) {
+		enabled += lowerChars
+	}
+	if d.Get("numeric").(bool) {
+		enabled += numChars
+	}
+
+	seen := map[rune]bool{}
+	var overlapping []string
+	for _, r := range d.Get("override_special").(string) {
+		if !seen[r] && strings.ContainsRune(enabled, r) {
+			overlapping = append(overlapping, string(r))
+		}
+		seen[r] = true
+	}
+
+	if len(overlapping) == 0 {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "override_special overlaps other character classes",
+		Detail: fmt.Sprintf("The characters %q of override_special are also uppercase or lowercase letters or digits "+
+			"that are enabled by upper, lower or numeric. They are counted as special characters towards min_special, "+
+			"but cannot be told apart from the characters of the other classes in the result.",
+			strings.Join(overlapping, "")),
+		AttributePath: cty.GetAttrPath("override_special"),
+	}}
+}
+
+// stringRandReader returns the source of randomness for generating a string for a resource of type typeName. This is
+// randReader unless, for a random_string, seed is configured in d or the provider configuration held in meta has a
+// seed, in which case it is a math/rand generator seeded from seed or the resourceSeed of d respectively, or, for a
+// random_password, derive is configured in d, in which case it is an HKDF reader over the derive inputs.
+func stringRandReader(d *schema.ResourceData, meta interface{}, typeName string) io.Reader {
+	switch typeName {
+	case "random_string":
+		if v, ok := d.GetOk("seed"); ok {
+			return NewRand(v.(string))
+		}
+		if seed := resourceSeed(meta, typeName, resourceString().Schema, d); seed != "" {
+			return NewRand(seed)
+		}
+	case "random_password":
+		if v, ok := d.GetOk("derive"); ok {
+			return deriveReader(v.([]interface{})[0].(map[string]interface{}))
+		}
+	}
+
+	return randReader
+}
+
+// generatePatternResult generates a random string from the supplied pattern using reader, replacing each placeholder
+// character with a random character from the corresponding class and including all other characters as-is.
+func generatePatternResult(d *schema.ResourceData, meta interface{}, reader io.Reader, pattern string) ([]byte, diag.Diagnostics) {
+	anyChars, _ := stringCharSets(d, meta)
+
+	result := make([]byte, 0, len(pattern))
+	for i := 0; i < len(pattern); i++ {
+		var charSet string
+		switch pattern[i] {
+		case 'A':
+			charSet = upperChars
+		case 'a':
+			charSet = lowerChars
+		case '9':
+			charSet = numChars
+		case '*':
+			if anyChars == "" {
+				return nil, diag.Errorf("pattern contains * but upper, lower, numeric and special are all false")
+			}
+			charSet = anyChars
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+			result = append(result, pattern[i])
+			continue
+		default:
+			result = append(result, pattern[i])
+			continue
+		}
+
+		b, err := generateRandomBytes(reader, &charSet, 1)
+		if err != nil {
+			return nil, diag.Errorf("error generating random bytes: %s", err)
 		}
-		sort.Slice(result, func(i, j int) bool {
-			return order[i] < order[j]
+		result = append(result, b...)
+	}
+
+	return result, nil
+}
+
+// generateGrammarResult generates a random string by expanding the supplied grammar.
+func generateGrammarResult(reader io.Reader, spec string) ([]byte, diag.Diagnostics) {
+	g, err := parseGrammar(spec)
+	if err != nil {
+		return nil, diag.Errorf("error parsing grammar: %s", err)
+	}
+
+	result, err := g.expand(reader)
+	if err != nil {
+		return nil, diag.Errorf("error expanding grammar: %s", err)
+	}
+
+	return result, nil
+}
+
+// generateRegexResult generates a random string matching the supplied restricted regular expression.
+func generateRegexResult(reader io.Reader, spec string) ([]byte, diag.Diagnostics) {
+	atoms, err := parseRegex(spec)
+	if err != nil {
+		return nil, diag.Errorf("error parsing regex: %s", err)
+	}
+
+	result, err := generateRegex(reader, atoms)
+	if err != nil {
+		return nil, diag.Errorf("error generating regex result: %s", err)
+	}
+
+	return result, nil
+}
+
+// generateStringResult generates a random string using reader that satisfies the configuration held in d, which must
+// conform to the schema returned by passwordStringSchema. meta holds the provider configuration.
+func generateStringResult(d *schema.ResourceData, meta interface{}, reader io.Reader) ([]byte, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	length := d.Get("length").(int)
+	minUpper := minCount(d, "min_upper", length)
+	minLower := minCount(d, "min_lower", length)
+	minNumeric := minCount(d, "min_numeric", length)
+	minSpecial := minCount(d, "min_special", length)
+	noPalindrome := d.Get("no_palindrome").(bool)
+	noConsecutiveDuplicates := d.Get("no_consecutive_duplicates").(bool)
+	minUnique := d.Get("min_unique").(int)
+
+	if length < minUpper+minLower+minNumeric+minSpecial {
+		return nil, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("length (%d) must be >= min_upper + min_lower + min_numeric + min_special (%d)", length, minUpper+minLower+minNumeric+minSpecial),
+		})
+	}
+
+	if noPalindrome && length < 2 {
+		return nil, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("length (%d) must be >= 2 when no_palindrome is true", length),
+		})
+	}
+
+	chars, minimums := stringCharSets(d, meta)
+
+	if specialChars, ok := overrideSpecialChars(d); ok && specialChars == "" && minSpecial > 0 {
+		return nil, append(diags, emptyOverrideSpecialDiagnostic(minSpecial))
+	}
+
+	if chars == "" {
+		return nil, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "upper, lower, numeric and special are all false",
+			Detail:   "At least one of upper, lower, numeric or special must be true to generate a result.",
+		})
+	}
+
+	// append_luhn is only present in the random_string schema.
+	if v, ok := d.GetOk("append_luhn"); ok && v.(bool) && strings.Trim(chars, numChars) != "" {
+		return nil, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "append_luhn requires the enabled characters to be digits only",
+			Detail:   "A Luhn check digit can only be computed for digits. Set upper, lower and special to false.",
 		})
+	}
+
+	split := lengthUnitSplitter(d)
+
+	if noConsecutiveDuplicates && length > 1 && distinctUnits(split(chars)) < 2 {
+		return nil, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("at least 2 distinct characters are required to generate a result of length (%d) when no_consecutive_duplicates is true", length),
+		})
+	}
+
+	if minUnique > length {
+		return nil, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("length (%d) must be >= min_unique (%d)", length, minUnique),
+		})
+	}
 
-		if err := d.Set("result", string(result)); err != nil {
-			return append(diags, diag.Errorf("error setting result: %s", err)...)
+	if available := distinctUnits(split(chars)); minUnique > available {
+		return nil, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("min_unique (%d) must be <= the number of distinct characters enabled (%d)", minUnique, available),
+			Detail:   "Enable additional character classes or characters in override_special, or lower min_unique.",
+		})
+	}
+
+	result, diags := generateCandidateString(reader, chars, minimums, length, split, noConsecutiveDuplicates)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	for attempt := 1; ; attempt++ {
+		palindrome := noPalindrome && isPalindrome(result)
+		tooFewUnique := distinctUnits(result) < minUnique
+		if !palindrome && !tooFewUnique {
+			break
 		}
 
-		if err := d.Set("number", number); err != nil {
-			return append(diags, diag.Errorf("error setting number: %s", err)...)
+		if attempt >= maxGenerateAttempts {
+			if palindrome {
+				return nil, append(diags, diag.Errorf("unable to generate a result that is not a palindrome after %d attempts, "+
+					"consider enabling additional character classes", maxGenerateAttempts)...)
+			}
+
+			return nil, append(diags, diag.Errorf("unable to generate a result with at least %d distinct characters after %d attempts, "+
+				"consider enabling additional character classes or lowering min_unique", minUnique, maxGenerateAttempts)...)
 		}
-		if err := d.Set("numeric", numeric); err != nil {
-			return append(diags, diag.Errorf("error setting numeric: %s", err)...)
+
+		result, diags = generateCandidateString(reader, chars, minimums, length, split, noConsecutiveDuplicates)
+		if diags.HasError() {
+			return nil, diags
 		}
+	}
 
-		if sensitive {
-			d.SetId("none")
-		} else {
-			d.SetId(string(result))
+	return []byte(strings.Join(result, "")), nil
+}
+
+const (
+	numChars   = "0123456789"
+	lowerChars = "abcdefghijklmnopqrstuvwxyz"
+	upperChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
+const (
+	caseLower = "lower"
+	caseUpper = "upper"
+	caseMixed = "mixed"
+)
+
+var stringCases = []string{caseLower, caseUpper, caseMixed}
+
+// changeCase returns result converted to the case named by stringCase. The result is returned unchanged when
+// stringCase is mixed or not set.
+func changeCase(result []byte, stringCase string) []byte {
+	switch stringCase {
+	case caseLower:
+		return bytes.ToLower(result)
+	case caseUpper:
+		return bytes.ToUpper(result)
+	default:
+		return result
+	}
+}
+
+// charSetMinimum is the minimum number of characters that must be drawn from the characters of a single class.
+// Minimums are held per class rather than keyed by the characters themselves, as override_special may contain the
+// same characters as another class.
+type charSetMinimum struct {
+	chars string
+	min   int
+}
+
+// stringCharSets returns all the characters that may appear in a generated string, given the configuration held
+// in d, along with the minimum number of characters that must be drawn from each character class. The special
+// characters are those of override_special when set, even to an empty string, otherwise those of the
+// default_special_override of the provider configuration held in meta when set, otherwise the built-in set.
+func stringCharSets(d *schema.ResourceData, meta interface{}) (string, []charSetMinimum) {
+	var specialChars = "!@#$%&*()-_=+[]{}<>:?"
+
+	upper := d.Get("upper").(bool)
+	lower := d.Get("lower").(bool)
+	numeric := d.Get("numeric").(bool)
+	special := d.Get("special").(bool)
+
+	if config, ok := meta.(*providerConfig); ok && config.defaultSpecialOverride != "" {
+		specialChars = config.defaultSpecialOverride
+	}
+	if overrideSpecial, ok := overrideSpecialChars(d); ok {
+		specialChars = overrideSpecial
+	}
+	if v, ok := d.GetOk("special_set"); ok {
+		specialChars = joinSpecialSet(v.(*schema.Set))
+	}
+
+	var chars = string("")
+	if upper {
+		chars += upperChars
+	}
+	if lower {
+		chars += lowerChars
+	}
+	if numeric {
+		chars += numChars
+	}
+	if special {
+		chars += specialChars
+	}
+
+	length := d.Get("length").(int)
+	minimums := []charSetMinimum{
+		{chars: upperChars, min: minCount(d, "min_upper", length)},
+		{chars: lowerChars, min: minCount(d, "min_lower", length)},
+		{chars: numChars, min: minCount(d, "min_numeric", length)},
+		{chars: specialChars, min: minCount(d, "min_special", length)},
+	}
+
+	return chars, minimums
+}
+
+// joinSpecialSet returns the characters of the special_set entry set joined in sorted order, so that the special
+// characters do not depend on the order in which the set holds them.
+func joinSpecialSet(set *schema.Set) string {
+	chars := make([]string, 0, set.Len())
+	for _, v := range set.List() {
+		chars = append(chars, v.(string))
+	}
+	sort.Strings(chars)
+
+	return strings.Join(chars, "")
+}
+
+// overrideSpecialChars returns the value of override_special in d and whether it is set. An empty string is only set
+// when it is given explicitly in the configuration, which is told apart from override_special not being set using
+// the raw configuration, so that an explicit empty string means that there are no special characters.
+func overrideSpecialChars(d interface {
+	Get(string) interface{}
+	GetRawConfig() cty.Value
+}) (string, bool) {
+	if v := d.Get("override_special").(string); v != "" {
+		return v, true
+	}
+
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return "", false
+	}
+
+	v := config.GetAttr("override_special")
+	return "", !v.IsNull() && v.IsKnown()
+}
+
+// minCount returns the minimum number of characters of a class given by the min_* entry key in d, or by the
+// corresponding min_*_pct entry as a fraction of length rounded up. The minimum is at least 1 when require_each_class
+// is true and the class is enabled. min_*_pct and require_each_class are only present in the random_string schema.
+func minCount(d interface {
+	Get(string) interface{}
+	GetOk(string) (interface{}, bool)
+}, key string, length int) int {
+	n := d.Get(key).(int)
+	if v, ok := d.GetOk(key + "_pct"); ok {
+		// The small tolerance stops a fraction such as 0.1 of 30, which is 3.0000000000000004 in floating point,
+		// being rounded up to 4.
+		n = int(math.Ceil(v.(float64)*float64(length) - 1e-9))
+	}
+
+	if v, ok := d.GetOk("require_each_class"); ok && v.(bool) && n < 1 && d.Get(minCountClass(key)).(bool) {
+		n = 1
+	}
+
+	return n
+}
+
+// minCountClass returns the key of the entry enabling the class whose minimum is given by the min_* entry key.
+func minCountClass(key string) string {
+	return strings.TrimPrefix(key, "min_")
+}
+
+// minCountKeys returns the keys of the entries read by minCount for the min_* entry key that are set from the
+// configuration. The entry enabling the class is left out as numeric is computed, and it is only known when planning
+// once planDefaultIfAllNull has run. A class whose entry is not known is treated as disabled, which can only lower the
+// minimum.
+func minCountKeys(key string) []string {
+	return []string{key, key + "_pct", "require_each_class"}
+}
+
+// generateCandidateString calls generateString and, when noConsecutiveDuplicates is true, rearranges the shuffled
+// result so that no unit appears twice in a row, regenerating the result if that is not possible.
+func generateCandidateString(reader io.Reader, chars string, minimums []charSetMinimum, length int, split func(string) []string, noConsecutiveDuplicates bool) ([]string, diag.Diagnostics) {
+	for attempt := 1; ; attempt++ {
+		result, err := generateString(reader, chars, minimums, length, split)
+		if err != nil {
+			return nil, diag.Errorf("error generating random bytes: %s", err)
+		}
+
+		if !noConsecutiveDuplicates {
+			return result, nil
+		}
+
+		separated, err := separateConsecutiveDuplicates(reader, result)
+		if err != nil {
+			return nil, diag.Errorf("error generating random bytes: %s", err)
+		}
+		if separated {
+			return result, nil
+		}
+
+		if attempt >= maxGenerateAttempts {
+			return nil, diag.Errorf("unable to generate a result without consecutive duplicate characters after %d attempts, "+
+				"consider enabling additional character classes", maxGenerateAttempts)
 		}
-		return nil
 	}
 }
 
-func generateRandomBytes(charSet *string, length int) ([]byte, error) {
-	bytes := make([]byte, length)
-	setLen := big.NewInt(int64(len(*charSet)))
-	for i := range bytes {
-		idx, err := rand.Int(rand.Reader, setLen)
+// separateConsecutiveDuplicates swaps characters within result so that result[i] != result[i-1] for all i, choosing
+// randomly among the positions that can be swapped. As swapping preserves the characters in result, any minimum
+// character counts continue to be met. It returns false if result cannot be rearranged in this way.
+func separateConsecutiveDuplicates(reader io.Reader, result []string) (bool, error) {
+	for i := 1; i < len(result); i++ {
+		c := result[i]
+		if c != result[i-1] {
+			continue
+		}
+
+		var candidates []int
+		for j := i + 1; j < len(result); j++ {
+			if result[j] != c {
+				candidates = append(candidates, j)
+			}
+		}
+
+		if len(candidates) == 0 {
+			// The remainder of result consists solely of c, so look for an earlier position that can take c without
+			// introducing a duplicate.
+			for j := 0; j < i-1; j++ {
+				if result[j] != c && (j == 0 || result[j-1] != c) && result[j+1] != c {
+					candidates = append(candidates, j)
+				}
+			}
+		}
+
+		if len(candidates) == 0 {
+			return false, nil
+		}
+
+		idx, err := rand.Int(reader, big.NewInt(int64(len(candidates))))
+		if err != nil {
+			return false, err
+		}
+
+		j := candidates[idx.Int64()]
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return true, nil
+}
+
+// distinctUnits returns the number of distinct units in units.
+func distinctUnits(units []string) int {
+	seen := map[string]bool{}
+	for _, u := range units {
+		seen[u] = true
+	}
+
+	return len(seen)
+}
+
+// maxGenerateAttempts bounds the number of times a result is regenerated in order to satisfy a constraint that
+// cannot be guaranteed up front (e.g., no_palindrome, no_consecutive_duplicates, min_unique, coprime_with).
+const maxGenerateAttempts = 100
+
+// generateString returns length random units drawn from the units of chars, as returned by split, of which at least
+// min units are drawn from the characters of each of minimums. The result is shuffled so that the units drawn to
+// satisfy minimums are not grouped together.
+func generateString(reader io.Reader, chars string, minimums []charSetMinimum, length int, split func(string) []string) ([]string, error) {
+	var result = make([]string, 0, length)
+	for _, m := range minimums {
+		s, err := generateRandomUnits(reader, split(m.chars), m.min)
 		if err != nil {
 			return nil, err
 		}
-		bytes[i] = (*charSet)[idx.Int64()]
+		result = append(result, s...)
+	}
+	s, err := generateRandomUnits(reader, split(chars), length-len(result))
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, s...)
+	// Fisher-Yates shuffle, so that every permutation of result is equally likely.
+	for i := len(result) - 1; i > 0; i-- {
+		var j *big.Int
+		err := retryRandom(func() (err error) {
+			j, err = rand.Int(reader, big.NewInt(int64(i+1)))
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		result[i], result[j.Int64()] = result[j.Int64()], result[i]
+	}
+
+	return result, nil
+}
+
+// isPalindrome returns true if units reads the same forwards and backwards.
+func isPalindrome(units []string) bool {
+	for i, j := 0, len(units)-1; i < j; i, j = i+1, j-1 {
+		if units[i] != units[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// generateRandomUnits returns length units drawn at random from units, retrying as described by retryRandom when
+// reading from reader fails. length is negative when minimums exceed the length passed to generateString, which is
+// an error rather than a panic.
+func generateRandomUnits(reader io.Reader, units []string, length int) ([]string, error) {
+	if length < 0 {
+		return nil, fmt.Errorf("length (%d) must not be negative", length)
+	}
+
+	var indices []int
+	err := retryRandom(func() (err error) {
+		indices, err = randomIndices(reader, len(units), length)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, length)
+	for i, idx := range indices {
+		result[i] = units[idx]
+	}
+	return result, nil
+}
+
+// generateRandomBytes returns length characters chosen at random from charSet, retrying as described by retryRandom
+// when reading from reader fails.
+func generateRandomBytes(reader io.Reader, charSet *string, length int) ([]byte, error) {
+	if length < 0 {
+		return nil, fmt.Errorf("length (%d) must not be negative", length)
+	}
+
+	var indices []int
+	err := retryRandom(func() (err error) {
+		indices, err = randomIndices(reader, len(*charSet), length)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bytes := make([]byte, length)
+	for i, idx := range indices {
+		bytes[i] = (*charSet)[idx]
 	}
 	return bytes, nil
 }
 
-func readNil(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+// randomIndices returns count values drawn uniformly at random from [0,n). When n fits in a byte, random bytes are
+// read from reader in bulk and any at or above the largest multiple of n that fits in a byte are rejected, so that
+// taking the remainder modulo n is not biased towards smaller values. Otherwise each value is drawn using rand.Int.
+func randomIndices(reader io.Reader, n, count int) ([]int, error) {
+	indices := make([]int, 0, count)
+	if count == 0 {
+		return indices, nil
+	}
+	if n < 1 {
+		return nil, errors.New("unable to choose from an empty set")
+	}
+
+	if n > 256 {
+		setLen := big.NewInt(int64(n))
+		for len(indices) < count {
+			idx, err := rand.Int(reader, setLen)
+			if err != nil {
+				return nil, err
+			}
+			indices = append(indices, int(idx.Int64()))
+		}
+		return indices, nil
+	}
+
+	limit := 256 - 256%n
+	var buf []byte
+	for len(indices) < count {
+		// Read enough bytes to fill the remaining indices once the expected number have been rejected.
+		size := (count-len(indices))*256/limit + 1
+		if cap(buf) < size {
+			buf = make([]byte, size)
+		}
+		buf = buf[:size]
+
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+
+		for _, b := range buf {
+			if int(b) >= limit {
+				continue
+			}
+			indices = append(indices, int(b)%n)
+			if len(indices) == count {
+				break
+			}
+		}
+	}
+
+	return indices, nil
+}
+
+// readResultEncodings populates result_base64, result_hex and result_length from result, so that they are present
+// for resources created before those attributes were introduced.
+func readResultEncodings(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	// The encodings would reveal a sensitive result, so they are left empty. sensitive is only present in the
+	// random_string schema.
+	if v, ok := d.GetOk("sensitive"); ok && v.(bool) {
+		return nil
+	}
+
+	if err := setResultEncodings(d, d.Get("result").(string)); err != nil {
+		return diag.Errorf("error setting result encodings: %s", err)
+	}
+
 	return nil
 }
 
+// setResultEncodings sets result_base64 and result_hex to the encodings of result, and result_length to its length.
+func setResultEncodings(d *schema.ResourceData, result string) error {
+	if err := d.Set("result_base64", base64.StdEncoding.EncodeToString([]byte(result))); err != nil {
+		return err
+	}
+
+	// result_length is only present in the random_string schema.
+	if _, ok := d.Get("result_length").(int); ok {
+		if err := d.Set("result_length", len(result)); err != nil {
+			return err
+		}
+	}
+
+	return d.Set("result_hex", hex.EncodeToString([]byte(result)))
+}
+
 func resourcePasswordStringStateUpgradeV1(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
 	if rawState == nil {
 		return nil, errors.New("state upgrade failed, state is nil")
@@ -380,16 +1589,220 @@ func planDefaultIfAllNull(defaultVal interface{}, keys ...string) []schema.Custo
 	return result
 }
 
+// isSingleRune is a SchemaValidateDiagFunc that returns an error unless the value is exactly one character.
+func isSingleRune(i interface{}, path cty.Path) diag.Diagnostics {
+	v, ok := i.(string)
+	if !ok {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Expected a string",
+			AttributePath: path,
+		}}
+	}
+
+	if utf8.RuneCountInString(v) == 1 {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity:      diag.Error,
+		Summary:       "Expected a single character",
+		Detail:        fmt.Sprintf("Each element of special_set must be exactly one character, got %q.", v),
+		AttributePath: path,
+	}}
+}
+
+// warnDuplicateChars is a SchemaValidateDiagFunc that returns a warning naming any characters that appear more than
+// once in the string value. Duplicates are allowed, for compatibility, but make those characters more likely to be
+// chosen and reduce the number of distinct characters available, which users are unlikely to intend.
+func warnDuplicateChars(i interface{}, path cty.Path) diag.Diagnostics {
+	v, ok := i.(string)
+	if !ok {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Expected a string",
+			AttributePath: path,
+		}}
+	}
+
+	seen := map[rune]int{}
+	var duplicates []string
+	for _, r := range v {
+		seen[r]++
+		if seen[r] == 2 {
+			duplicates = append(duplicates, string(r))
+		}
+	}
+
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Duplicate characters",
+		Detail: fmt.Sprintf("The characters %q appear more than once. Each occurrence makes the character more "+
+			"likely to be chosen without adding any distinct characters, reducing the entropy of the result.",
+			strings.Join(duplicates, "")),
+		AttributePath: path,
+	}}
+}
+
+// isAtLeastSumOf returns a CustomizeDiffFunc that ensures the value of key is at least the sum of the values of
+// sumKeys, so that the error is reported when planning rather than when creating the resource. The values of sumKeys
+// are resolved by minCount against the value of key, so may instead be given as fractions or by require_each_class.
+// Validation is skipped when key is not set or when any of the values are not yet known.
+func isAtLeastSumOf(key string, sumKeys ...string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+		keys := []string{key}
+		for _, k := range sumKeys {
+			keys = append(keys, minCountKeys(k)...)
+		}
+		for _, k := range keys {
+			if !d.NewValueKnown(k) {
+				return nil
+			}
+		}
+
+		value, ok := d.GetOk(key)
+		if !ok {
+			return nil
+		}
+
+		sum := 0
+		for _, k := range sumKeys {
+			sum += minCount(d, k, value.(int))
+		}
+
+		if value.(int) < sum {
+			return fmt.Errorf("%s (%d) must be >= %s (%d)", key, value.(int), strings.Join(sumKeys, " + "), sum)
+		}
+
+		return nil
+	}
+}
+
+// planMaxLength ensures that length, or the longest possible expansion of grammar, does not exceed the
+// max_string_length configured for the provider, which is held in meta, when the resource is created or replaced.
+// Existing resources whose length or grammar is unchanged are not affected by lowering max_string_length.
+func planMaxLength(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	maxLength := maxStringLength(meta)
+
+	if d.HasChange("length") && d.NewValueKnown("length") {
+		if length := d.Get("length").(int); length > maxLength {
+			return fmt.Errorf("length (%d) must be <= max_string_length (%d), set max_string_length in the "+
+				"provider configuration to allow a longer result", length, maxLength)
+		}
+	}
+
+	if d.HasChange("grammar") && d.NewValueKnown("grammar") {
+		if spec := d.Get("grammar").(string); spec != "" {
+			g, err := parseGrammar(spec)
+			if err != nil {
+				return fmt.Errorf("expected grammar to be a valid grammar: %w", err)
+			}
+
+			if g.maxLength > maxLength {
+				return fmt.Errorf("grammar can expand to %d bytes, which must be <= max_string_length (%d), set "+
+					"max_string_length in the provider configuration to allow a longer result", g.maxLength, maxLength)
+			}
+		}
+	}
+
+	return nil
+}
+
+// maxStringLength returns the max_string_length of the provider configuration held in meta, or the default when
+// meta holds no configuration.
+func maxStringLength(meta interface{}) int {
+	if config, ok := meta.(*providerConfig); ok {
+		return config.maxStringLength
+	}
+
+	return defaultMaxStringLength
+}
+
+// planEmptyOverrideSpecial ensures that min_special is 0 when override_special is an explicit empty string, which
+// leaves no special characters to satisfy it, so that the error is reported when planning rather than when creating
+// the resource.
+func planEmptyOverrideSpecial(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	for _, k := range append([]string{"length", "override_special"}, minCountKeys("min_special")...) {
+		if !d.NewValueKnown(k) {
+			return nil
+		}
+	}
+
+	if specialChars, ok := overrideSpecialChars(d); !ok || specialChars != "" {
+		return nil
+	}
+
+	if minSpecial := minCount(d, "min_special", d.Get("length").(int)); minSpecial > 0 {
+		return errors.New(emptyOverrideSpecialDiagnostic(minSpecial).Summary)
+	}
+
+	return nil
+}
+
+// emptyOverrideSpecialDiagnostic returns the error for a min_special of minSpecial when override_special is an
+// explicit empty string.
+func emptyOverrideSpecialDiagnostic(minSpecial int) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("min_special (%d) cannot be satisfied when override_special is an empty string", minSpecial),
+		Detail: "An empty override_special means that there are no special characters. Remove override_special to " +
+			"use the default special characters, or set min_special to 0.",
+	}
+}
+
+// planCase ensures that case is not lower or upper when set with arguments that the change of case could make
+// unsatisfiable, so that the error is reported when planning rather than when creating the resource. case is only
+// present in the random_string schema.
+func planCase(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	keys := append([]string{"case", "length", "no_palindrome", "no_consecutive_duplicates", "min_unique"}, minCountKeys("min_upper")...)
+	for _, k := range append(keys, minCountKeys("min_lower")...) {
+		if !d.NewValueKnown(k) {
+			return nil
+		}
+	}
+
+	stringCase := d.Get("case").(string)
+	if stringCase != caseLower && stringCase != caseUpper {
+		return nil
+	}
+
+	length := d.Get("length").(int)
+	if minUpper := minCount(d, "min_upper", length); stringCase == caseLower && minUpper > 0 {
+		return fmt.Errorf("min_upper (%d) cannot be satisfied when case is %s", minUpper, stringCase)
+	}
+	if minLower := minCount(d, "min_lower", length); stringCase == caseUpper && minLower > 0 {
+		return fmt.Errorf("min_lower (%d) cannot be satisfied when case is %s", minLower, stringCase)
+	}
+	for _, k := range []string{"no_palindrome", "no_consecutive_duplicates"} {
+		if d.Get(k).(bool) {
+			return fmt.Errorf("%s cannot be guaranteed when case is %s", k, stringCase)
+		}
+	}
+	if minUnique := d.Get("min_unique").(int); minUnique > 0 {
+		return fmt.Errorf("min_unique (%d) cannot be guaranteed when case is %s", minUnique, stringCase)
+	}
+
+	return nil
+}
+
 // planSyncIfChange handles keeping `number` and `numeric` in-sync. If either is changed the value of both is
-// set to the new value of the attribute that has changed.
+// set to the new value of the attribute that has changed. The value is also copied when the attribute to sync has no
+// known value, as is the case when creating a resource with only one of them set to `false`, which is the zero value
+// and so is not seen as a change.
 func planSyncIfChange(key, keyToSync string) func(context.Context, *schema.ResourceDiff, interface{}) error {
-	return customdiff.IfValueChange(
-		key,
-		func(ctx context.Context, oldValue, newValue, meta interface{}) bool {
-			return oldValue != newValue
-		},
-		func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	return func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+
+		if d.HasChange(key) || !d.NewValueKnown(keyToSync) {
 			return d.SetNew(keyToSync, d.Get(key))
-		},
-	)
+		}
+
+		return nil
+	}
 }
@@ -2,10 +2,23 @@ package provider
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestResourcePasswordStringStateUpgradeV1(t *testing.T) {
@@ -55,3 +68,1475 @@ func TestResourcePasswordStringStateUpgradeV1(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPalindrome(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{
+			name:     "empty",
+			input:    "",
+			expected: true,
+		},
+		{
+			name:     "single character",
+			input:    "a",
+			expected: true,
+		},
+		{
+			name:     "odd length palindrome",
+			input:    "a!a",
+			expected: true,
+		},
+		{
+			name:     "even length palindrome",
+			input:    "abba",
+			expected: true,
+		},
+		{
+			name:     "not palindrome",
+			input:    "ab",
+			expected: false,
+		},
+		{
+			name:     "multi-byte palindrome",
+			input:    "é!é",
+			expected: true,
+		},
+		{
+			name:     "multi-byte not palindrome",
+			input:    "éa",
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := isPalindrome(splitRunes(c.input))
+
+			if actual != c.expected {
+				t.Errorf("expected: %t, got: %t", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSeparateConsecutiveDuplicates(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{
+			name:     "no duplicates",
+			input:    "abcabc",
+			expected: true,
+		},
+		{
+			name:     "duplicate followed by other characters",
+			input:    "aabbcc",
+			expected: true,
+		},
+		{
+			name:     "duplicates at end",
+			input:    "acbb",
+			expected: true,
+		},
+		{
+			name:     "alternation only",
+			input:    "aaabb",
+			expected: true,
+		},
+		{
+			name:     "too many of one character",
+			input:    "aaaab",
+			expected: false,
+		},
+		{
+			name:     "single character",
+			input:    "aa",
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result := splitBytes(c.input)
+
+			separated, err := separateConsecutiveDuplicates(rand.Reader, result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if separated != c.expected {
+				t.Fatalf("expected %t, got %t for %q", c.expected, separated, result)
+			}
+			if !separated {
+				return
+			}
+
+			for i := 1; i < len(result); i++ {
+				if result[i] == result[i-1] {
+					t.Errorf("result %q has consecutive duplicates at position %d", result, i)
+				}
+			}
+			if !cmp.Equal(sortedStrings(result), sortedStrings(splitBytes(c.input))) {
+				t.Errorf("result %q is not a rearrangement of %q", result, c.input)
+			}
+		})
+	}
+}
+
+func sortedStrings(s []string) []string {
+	sorted := append([]string(nil), s...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func TestGenerateStringSeeded(t *testing.T) {
+	chars := "abcdefghijklmnopqrstuvwxyz0123456789"
+	minimums := []charSetMinimum{
+		{chars: "abcdefghijklmnopqrstuvwxyz", min: 4},
+		{chars: "0123456789", min: 4},
+	}
+
+	first, err := generateString(NewRand("fixture"), chars, minimums, 16, splitBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		next, err := generateString(NewRand("fixture"), chars, minimums, 16, splitBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(first, next) {
+			t.Fatalf("expected seeded results to match, got %q and %q", first, next)
+		}
+	}
+}
+
+func TestGenerateStringResultRandReader(t *testing.T) {
+	setRandReader(t, &sequenceReader{})
+
+	d := resourceString().TestResourceData()
+	for k, v := range map[string]interface{}{
+		"length":  12,
+		"lower":   true,
+		"numeric": true,
+	} {
+		if err := d.Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, diags := generateStringResult(d, nil, stringRandReader(d, nil, "random_string"))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if expected := "ihgjklfedcba"; string(result) != expected {
+		t.Errorf("got %q; want %q", result, expected)
+	}
+}
+
+func TestCreateStringSensitive(t *testing.T) {
+	d := resourceString().TestResourceData()
+	for k, v := range map[string]interface{}{
+		"length":    12,
+		"lower":     true,
+		"numeric":   true,
+		"sensitive": true,
+	} {
+		if err := d.Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if diags := createStringFunc("random_string")(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if result := d.Get("sensitive_result").(string); !regexp.MustCompile(`^[a-z0-9]{12}$`).MatchString(result) {
+		t.Errorf("expected sensitive_result to be 12 lowercase or numeric characters, got: %q", result)
+	}
+	for _, k := range []string{"result", "result_base64", "result_hex"} {
+		if v := d.Get(k).(string); v != "" {
+			t.Errorf("expected %s to be empty, got: %q", k, v)
+		}
+	}
+	if d.Id() != "none" {
+		t.Errorf("expected id to be none, got: %q", d.Id())
+	}
+	if length := d.Get("result_length").(int); length != 12 {
+		t.Errorf("expected result_length to be 12, got: %d", length)
+	}
+
+	if diags := readResultEncodings(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if v := d.Get("result_base64").(string); v != "" {
+		t.Errorf("expected result_base64 to remain empty after read, got: %q", v)
+	}
+}
+
+func TestGenerateStringResultNoCharacterClasses(t *testing.T) {
+	d := resourceString().TestResourceData()
+	for k, v := range map[string]interface{}{
+		"length":  8,
+		"upper":   false,
+		"lower":   false,
+		"numeric": false,
+		"special": false,
+	} {
+		if err := d.Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, diags := generateStringResult(d, nil, stringRandReader(d, nil, "random_string"))
+	if !diags.HasError() {
+		t.Fatal("expected error when every character class is disabled")
+	}
+	if expected := "upper, lower, numeric and special are all false"; diags[0].Summary != expected {
+		t.Errorf("got summary %q; want %q", diags[0].Summary, expected)
+	}
+}
+
+// constantReader returns the same byte for every read, so that random choices always pick the same index.
+type constantReader struct{}
+
+func (constantReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+
+	return len(p), nil
+}
+
+func TestGenerateStringResultMinUnique(t *testing.T) {
+	cases := []struct {
+		name      string
+		minUnique int
+		reader    io.Reader
+		err       string
+	}{
+		{
+			name:      "satisfied",
+			minUnique: 2,
+			reader:    &sequenceReader{},
+		},
+		{
+			name:      "more than length",
+			minUnique: 5,
+			reader:    &sequenceReader{},
+			err:       "length (4) must be >= min_unique (5)",
+		},
+		{
+			name:      "more than alphabet",
+			minUnique: 3,
+			reader:    &sequenceReader{},
+			err:       "min_unique (3) must be <= the number of distinct characters enabled (2)",
+		},
+		{
+			name:      "retries exhausted",
+			minUnique: 2,
+			reader:    constantReader{},
+			err: fmt.Sprintf("unable to generate a result with at least 2 distinct characters after %d attempts, "+
+				"consider enabling additional character classes or lowering min_unique", maxGenerateAttempts),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			setRandReader(t, c.reader)
+
+			d := resourceString().TestResourceData()
+			for k, v := range map[string]interface{}{
+				"length":           4,
+				"upper":            false,
+				"lower":            false,
+				"numeric":          false,
+				"special":          true,
+				"override_special": "!@",
+				"min_unique":       c.minUnique,
+			} {
+				if err := d.Set(k, v); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			result, diags := generateStringResult(d, nil, stringRandReader(d, nil, "random_string"))
+			if c.err != "" {
+				if !diags.HasError() || diags[0].Summary != c.err {
+					t.Fatalf("expected error %q, got: %v", c.err, diags)
+				}
+				return
+			}
+
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if unique := distinctUnits(strings.Split(string(result), "")); unique < c.minUnique {
+				t.Errorf("expected at least %d distinct characters in %q, got: %d", c.minUnique, result, unique)
+			}
+		})
+	}
+}
+
+func TestGenerateStringResultLengthUnit(t *testing.T) {
+	cases := []struct {
+		unit    string
+		measure func(string) int
+	}{
+		{
+			unit:    lengthUnitBytes,
+			measure: func(s string) int { return len(s) },
+		},
+		{
+			unit:    lengthUnitRunes,
+			measure: utf8.RuneCountInString,
+		},
+		{
+			unit:    lengthUnitGraphemes,
+			measure: func(s string) int { return len(splitGraphemes(s)) },
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.unit, func(t *testing.T) {
+			d := resourceString().TestResourceData()
+			for k, v := range map[string]interface{}{
+				"length":           6,
+				"length_unit":      c.unit,
+				"upper":            false,
+				"lower":            false,
+				"numeric":          false,
+				"special":          true,
+				"override_special": "e\u0301\U0001f44d\U0001f3fd\U0001f1fa\U0001f1f8",
+				"min_special":      2,
+			} {
+				if err := d.Set(k, v); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for i := 0; i < 100; i++ {
+				result, diags := generateStringResult(d, nil, stringRandReader(d, nil, "random_string"))
+				if diags.HasError() {
+					t.Fatalf("unexpected error: %v", diags)
+				}
+				if n := c.measure(string(result)); n != 6 {
+					t.Fatalf("result %q measures %d %s; want 6", result, n, c.unit)
+				}
+			}
+		})
+	}
+}
+
+func TestStringCharSetsOverlappingOverrideSpecial(t *testing.T) {
+	d := resourceString().TestResourceData()
+	for k, v := range map[string]interface{}{
+		"upper":            false,
+		"lower":            false,
+		"numeric":          true,
+		"special":          true,
+		"override_special": "abc123",
+		"min_numeric":      2,
+		"min_special":      2,
+	} {
+		if err := d.Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chars, minimums := stringCharSets(d, nil)
+
+	expected := []charSetMinimum{
+		{chars: upperChars},
+		{chars: lowerChars},
+		{chars: numChars, min: 2},
+		{chars: "abc123", min: 2},
+	}
+	if !cmp.Equal(minimums, expected, cmp.AllowUnexported(charSetMinimum{})) {
+		t.Fatalf("got minimums %+v; want %+v", minimums, expected)
+	}
+
+	reader := NewRand("overlap")
+	for i := 0; i < 1000; i++ {
+		result, err := generateString(reader, chars, minimums, 4, splitBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := countCharsIn(result, numChars); n < 2 {
+			t.Fatalf("result %q contains %d numeric characters; want at least 2", result, n)
+		}
+		if n := countCharsIn(result, "abc123"); n < 2 {
+			t.Fatalf("result %q contains %d special characters; want at least 2", result, n)
+		}
+	}
+}
+
+func TestStringCharSetsDefaultSpecialOverride(t *testing.T) {
+	config := &providerConfig{defaultSpecialOverride: "-_"}
+
+	cases := []struct {
+		name            string
+		meta            interface{}
+		overrideSpecial string
+		expected        string
+	}{
+		{name: "no provider config", meta: nil, expected: "!@#$%&*()-_=+[]{}<>:?"},
+		{name: "provider default", meta: config, expected: "-_"},
+		{name: "override_special wins", meta: config, overrideSpecial: "#", expected: "#"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := resourceString().TestResourceData()
+			for k, v := range map[string]interface{}{
+				"upper":            false,
+				"lower":            false,
+				"numeric":          false,
+				"special":          true,
+				"override_special": c.overrideSpecial,
+			} {
+				if err := d.Set(k, v); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			chars, _ := stringCharSets(d, c.meta)
+			if chars != c.expected {
+				t.Fatalf("got chars %q; want %q", chars, c.expected)
+			}
+		})
+	}
+}
+
+func TestStringCharSetsSpecialSet(t *testing.T) {
+	d := resourceString().TestResourceData()
+	for k, v := range map[string]interface{}{
+		"upper":       false,
+		"lower":       false,
+		"numeric":     false,
+		"special":     true,
+		"special_set": []interface{}{"-", "!", "é"},
+	} {
+		if err := d.Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chars, minimums := stringCharSets(d, &providerConfig{defaultSpecialOverride: "#"})
+	if chars != "!-é" {
+		t.Errorf("got chars %q; want %q", chars, "!-é")
+	}
+	if minimums[3].chars != "!-é" {
+		t.Errorf("got special minimum chars %q; want %q", minimums[3].chars, "!-é")
+	}
+}
+
+// countCharsIn returns the number of units in units that appear in chars.
+func countCharsIn(units []string, chars string) int {
+	n := 0
+	for _, u := range units {
+		if strings.Contains(chars, u) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestIsAtLeastSumOf(t *testing.T) {
+	// unknownConfigValue is the value used by the SDK to represent values that are not known until apply.
+	const unknownConfigValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		err    error
+	}{
+		{
+			name:   "length greater than sum",
+			config: map[string]interface{}{"length": 12, "min_upper": 2, "min_lower": 2, "min_numeric": 2, "min_special": 2},
+		},
+		{
+			name:   "length equal to sum",
+			config: map[string]interface{}{"length": 8, "min_upper": 2, "min_lower": 2, "min_numeric": 2, "min_special": 2},
+		},
+		{
+			name:   "length less than sum",
+			config: map[string]interface{}{"length": 7, "min_upper": 2, "min_lower": 2, "min_numeric": 2, "min_special": 2},
+			err:    errors.New("length (7) must be >= min_upper + min_lower + min_numeric + min_special (8)"),
+		},
+		{
+			name:   "length equal to sum with fractions",
+			config: map[string]interface{}{"length": 10, "min_upper": 2, "min_lower_pct": 0.2, "min_numeric_pct": 0.6},
+		},
+		{
+			name:   "length less than sum with fractions rounded up",
+			config: map[string]interface{}{"length": 10, "min_upper": 2, "min_lower_pct": 0.25, "min_numeric_pct": 0.55},
+			err:    errors.New("length (10) must be >= min_upper + min_lower + min_numeric + min_special (11)"),
+		},
+		{
+			name:   "length equal to enabled classes",
+			config: map[string]interface{}{"length": 3, "require_each_class": true, "numeric": true, "special": false},
+		},
+		{
+			name:   "length less than enabled classes",
+			config: map[string]interface{}{"length": 3, "require_each_class": true, "numeric": true},
+			err:    errors.New("length (3) must be >= min_upper + min_lower + min_numeric + min_special (4)"),
+		},
+		{
+			name:   "length unknown",
+			config: map[string]interface{}{"length": unknownConfigValue, "min_lower": 3},
+		},
+		{
+			name:   "fraction unknown",
+			config: map[string]interface{}{"length": 2, "min_lower_pct": unknownConfigValue},
+		},
+		{
+			name:   "min unknown",
+			config: map[string]interface{}{"length": 2, "min_lower": unknownConfigValue},
+		},
+		{
+			name:   "length not set",
+			config: map[string]interface{}{"grammar": `<a> ::= "a"`, "min_lower": 3},
+		},
+	}
+
+	r := &schema.Resource{
+		Schema:        stringSchemaV2(),
+		CustomizeDiff: isAtLeastSumOf("length", "min_upper", "min_lower", "min_numeric", "min_special"),
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(c.config), nil)
+
+			if c.err != nil {
+				if err == nil || !cmp.Equal(c.err.Error(), err.Error()) {
+					t.Errorf("expected: %q, got: %v", c.err.Error(), err)
+				}
+			} else if err != nil {
+				t.Errorf("err should be nil, actual: %v", err)
+			}
+		})
+	}
+}
+
+func TestPlanMaxLength(t *testing.T) {
+	cases := []struct {
+		name    string
+		length  int
+		grammar string
+		meta    interface{}
+		err     string
+	}{
+		{
+			name:   "default maximum",
+			length: defaultMaxStringLength,
+		},
+		{
+			name:   "exceeds default maximum",
+			length: defaultMaxStringLength + 1,
+			err: "length (1025) must be <= max_string_length (1024), set max_string_length in the provider " +
+				"configuration to allow a longer result",
+		},
+		{
+			name:   "raised maximum",
+			length: 4096,
+			meta:   &providerConfig{maxStringLength: 4096},
+		},
+		{
+			name:   "lowered maximum",
+			length: 32,
+			meta:   &providerConfig{maxStringLength: 16},
+			err: "length (32) must be <= max_string_length (16), set max_string_length in the provider " +
+				"configuration to allow a longer result",
+		},
+		{
+			name:    "grammar within default maximum",
+			grammar: exponentialGrammar(10),
+		},
+		{
+			name:    "grammar exceeds default maximum",
+			grammar: exponentialGrammar(11),
+			err: "grammar can expand to 2048 bytes, which must be <= max_string_length (1024), set " +
+				"max_string_length in the provider configuration to allow a longer result",
+		},
+		{
+			name:    "grammar within raised maximum",
+			grammar: exponentialGrammar(11),
+			meta:    &providerConfig{maxStringLength: 4096},
+		},
+	}
+
+	r := &schema.Resource{
+		Schema:        stringSchemaV2(),
+		CustomizeDiff: planMaxLength,
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config := map[string]interface{}{"length": c.length}
+			if c.grammar != "" {
+				config = map[string]interface{}{"grammar": c.grammar}
+			}
+
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), c.meta)
+
+			if c.err != "" {
+				if err == nil || err.Error() != c.err {
+					t.Errorf("expected: %q, got: %v", c.err, err)
+				}
+			} else if err != nil {
+				t.Errorf("err should be nil, actual: %v", err)
+			}
+		})
+	}
+}
+
+func TestChangeCase(t *testing.T) {
+	for stringCase, expected := range map[string]string{
+		"":        "Ab1-Éz",
+		caseMixed: "Ab1-Éz",
+		caseLower: "ab1-éz",
+		caseUpper: "AB1-ÉZ",
+	} {
+		if actual := string(changeCase([]byte("Ab1-Éz"), stringCase)); actual != expected {
+			t.Errorf("case %q: expected %q, got %q", stringCase, expected, actual)
+		}
+	}
+}
+
+func TestPlanEmptyOverrideSpecial(t *testing.T) {
+	cases := []struct {
+		name   string
+		config map[string]cty.Value
+		err    string
+	}{
+		{
+			name:   "override_special unset with min_special",
+			config: map[string]cty.Value{"min_special": cty.NumberIntVal(2)},
+		},
+		{
+			name:   "override_special set with min_special",
+			config: map[string]cty.Value{"override_special": cty.StringVal("%"), "min_special": cty.NumberIntVal(2)},
+		},
+		{
+			name:   "override_special empty",
+			config: map[string]cty.Value{"override_special": cty.StringVal("")},
+		},
+		{
+			name:   "override_special empty with min_special",
+			config: map[string]cty.Value{"override_special": cty.StringVal(""), "min_special": cty.NumberIntVal(2)},
+			err:    "min_special (2) cannot be satisfied when override_special is an empty string",
+		},
+		{
+			name:   "override_special unknown with min_special",
+			config: map[string]cty.Value{"override_special": cty.UnknownVal(cty.String), "min_special": cty.NumberIntVal(2)},
+		},
+	}
+
+	for _, typeName := range []string{"random_string", "random_password"} {
+		for _, c := range cases {
+			t.Run(typeName+" "+c.name, func(t *testing.T) {
+				config := map[string]cty.Value{"length": cty.NumberIntVal(12)}
+				for k, v := range c.config {
+					config[k] = v
+				}
+
+				var errs []string
+				for _, d := range planResourceChangeResponse(t, typeName, nil, config).Diagnostics {
+					if d.Severity == tfprotov5.DiagnosticSeverityError {
+						errs = append(errs, d.Summary)
+					}
+				}
+
+				if c.err == "" {
+					if len(errs) != 0 {
+						t.Errorf("unexpected errors: %v", errs)
+					}
+					return
+				}
+				if len(errs) != 1 || !strings.Contains(errs[0], c.err) {
+					t.Errorf("expected error: %q, got: %v", c.err, errs)
+				}
+			})
+		}
+	}
+}
+
+func TestOverrideSpecialChars(t *testing.T) {
+	cases := []struct {
+		name          string
+		value         string
+		config        cty.Value
+		expectedChars string
+		expectedSet   bool
+	}{
+		{
+			name:   "no config",
+			config: cty.NullVal(cty.Object(map[string]cty.Type{"override_special": cty.String})),
+		},
+		{
+			name:   "unset",
+			config: cty.ObjectVal(map[string]cty.Value{"override_special": cty.NullVal(cty.String)}),
+		},
+		{
+			name:        "empty",
+			config:      cty.ObjectVal(map[string]cty.Value{"override_special": cty.StringVal("")}),
+			expectedSet: true,
+		},
+		{
+			name:          "set",
+			value:         "%",
+			config:        cty.ObjectVal(map[string]cty.Value{"override_special": cty.StringVal("%")}),
+			expectedChars: "%",
+			expectedSet:   true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			chars, set := overrideSpecialChars(rawConfigData{values: map[string]interface{}{"override_special": c.value}, config: c.config})
+			if chars != c.expectedChars || set != c.expectedSet {
+				t.Errorf("expected (%q, %t), got (%q, %t)", c.expectedChars, c.expectedSet, chars, set)
+			}
+		})
+	}
+}
+
+// rawConfigData holds values and a raw configuration, as ResourceData does once the configuration is known.
+type rawConfigData struct {
+	values map[string]interface{}
+	config cty.Value
+}
+
+func (d rawConfigData) Get(key string) interface{} {
+	return d.values[key]
+}
+
+func (d rawConfigData) GetRawConfig() cty.Value {
+	return d.config
+}
+
+func TestPlanCase(t *testing.T) {
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		err    string
+	}{
+		{
+			name:   "mixed with minimums",
+			config: map[string]interface{}{"case": caseMixed, "min_upper": 2, "min_lower": 2, "no_palindrome": true},
+		},
+		{
+			name:   "lower with min_lower",
+			config: map[string]interface{}{"case": caseLower, "min_lower": 2},
+		},
+		{
+			name:   "lower with min_upper",
+			config: map[string]interface{}{"case": caseLower, "min_upper": 2},
+			err:    "min_upper (2) cannot be satisfied when case is lower",
+		},
+		{
+			name:   "upper with min_lower",
+			config: map[string]interface{}{"case": caseUpper, "min_lower": 1},
+			err:    "min_lower (1) cannot be satisfied when case is upper",
+		},
+		{
+			name:   "upper with no_consecutive_duplicates",
+			config: map[string]interface{}{"case": caseUpper, "no_consecutive_duplicates": true},
+			err:    "no_consecutive_duplicates cannot be guaranteed when case is upper",
+		},
+		{
+			name:   "lower with min_unique",
+			config: map[string]interface{}{"case": caseLower, "min_unique": 4},
+			err:    "min_unique (4) cannot be guaranteed when case is lower",
+		},
+	}
+
+	r := &schema.Resource{
+		Schema:        stringSchemaV2(),
+		CustomizeDiff: planCase,
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config := map[string]interface{}{"length": 12}
+			for k, v := range c.config {
+				config[k] = v
+			}
+
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), nil)
+
+			if c.err != "" {
+				if err == nil || err.Error() != c.err {
+					t.Errorf("expected: %q, got: %v", c.err, err)
+				}
+			} else if err != nil {
+				t.Errorf("err should be nil, actual: %v", err)
+			}
+		})
+	}
+}
+
+func TestMinCount(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   map[string]interface{}
+		expected int
+	}{
+		{name: "not set", config: map[string]interface{}{}, expected: 0},
+		{name: "count", config: map[string]interface{}{"min_numeric": 3}, expected: 3},
+		{name: "exact fraction", config: map[string]interface{}{"min_numeric_pct": 0.1}, expected: 3},
+		{name: "fraction rounded up", config: map[string]interface{}{"min_numeric_pct": 0.11}, expected: 4},
+		{name: "whole length", config: map[string]interface{}{"min_numeric_pct": 1.0}, expected: 30},
+		{name: "require_each_class", config: map[string]interface{}{"require_each_class": true, "numeric": true}, expected: 1},
+		{name: "require_each_class with count", config: map[string]interface{}{"require_each_class": true, "numeric": true, "min_numeric": 3}, expected: 3},
+		{name: "require_each_class with class disabled", config: map[string]interface{}{"require_each_class": true, "numeric": false}, expected: 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := resourceString().TestResourceData()
+			for k, v := range c.config {
+				if err := d.Set(k, v); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if actual := minCount(d, "min_numeric", 30); actual != c.expected {
+				t.Errorf("expected %d, got %d", c.expected, actual)
+			}
+		})
+	}
+
+	// min_numeric_pct is not present in the random_password schema.
+	d := resourcePassword().TestResourceData()
+	if err := d.Set("min_numeric", 2); err != nil {
+		t.Fatal(err)
+	}
+	if actual := minCount(d, "min_numeric", 30); actual != 2 {
+		t.Errorf("expected 2 for random_password, got %d", actual)
+	}
+}
+
+func TestStringMinDefaultsPlan(t *testing.T) {
+	cases := []struct {
+		name         string
+		minUpper     string
+		config       map[string]interface{}
+		expectChange bool
+	}{
+		{
+			name:     "default unchanged",
+			minUpper: "0",
+			config:   map[string]interface{}{"length": 12},
+		},
+		{
+			name:     "explicit zero matches default",
+			minUpper: "0",
+			config:   map[string]interface{}{"length": 12, "min_upper": 0},
+		},
+		{
+			name:     "explicit value unchanged",
+			minUpper: "3",
+			config:   map[string]interface{}{"length": 12, "min_upper": 3},
+		},
+		{
+			name:         "explicit value removed",
+			minUpper:     "3",
+			config:       map[string]interface{}{"length": 12},
+			expectChange: true,
+		},
+		{
+			name:         "explicit value added",
+			minUpper:     "0",
+			config:       map[string]interface{}{"length": 12, "min_upper": 3},
+			expectChange: true,
+		},
+	}
+
+	r := &schema.Resource{
+		Schema: stringSchemaV2(),
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			state := &terraform.InstanceState{
+				ID: "none",
+				Attributes: map[string]string{
+					"id":          "none",
+					"result":      "abcdefghijkl",
+					"length":      "12",
+					"upper":       "true",
+					"lower":       "true",
+					"numeric":     "true",
+					"number":      "true",
+					"special":     "true",
+					"min_upper":   c.minUpper,
+					"min_lower":   "0",
+					"min_numeric": "0",
+					"min_special": "0",
+					"results.#":   "0",
+				},
+			}
+
+			diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(c.config), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if changed := diff != nil && !diff.Empty(); changed != c.expectChange {
+				t.Errorf("expected change: %t, got: %t (%v)", c.expectChange, changed, diff)
+			}
+			if c.expectChange && !diff.RequiresNew() {
+				t.Errorf("expected the change to require replacement")
+			}
+		})
+	}
+}
+
+func TestPlanNumberNumeric(t *testing.T) {
+	cases := []struct {
+		name            string
+		prior           map[string]cty.Value
+		config          map[string]cty.Value
+		expectedNumber  bool
+		expectedNumeric bool
+	}{
+		{
+			name:            "create with neither set",
+			config:          map[string]cty.Value{},
+			expectedNumber:  true,
+			expectedNumeric: true,
+		},
+		{
+			name:            "create with number false",
+			config:          map[string]cty.Value{"number": cty.False},
+			expectedNumber:  false,
+			expectedNumeric: false,
+		},
+		{
+			name:            "create with numeric false",
+			config:          map[string]cty.Value{"numeric": cty.False},
+			expectedNumber:  false,
+			expectedNumeric: false,
+		},
+		{
+			name:            "create with numeric true",
+			config:          map[string]cty.Value{"numeric": cty.True},
+			expectedNumber:  true,
+			expectedNumeric: true,
+		},
+		{
+			name:            "update to numeric false",
+			prior:           map[string]cty.Value{"number": cty.True, "numeric": cty.True},
+			config:          map[string]cty.Value{"numeric": cty.False},
+			expectedNumber:  false,
+			expectedNumeric: false,
+		},
+		{
+			name:            "update to number false",
+			prior:           map[string]cty.Value{"number": cty.True, "numeric": cty.True},
+			config:          map[string]cty.Value{"number": cty.False},
+			expectedNumber:  false,
+			expectedNumeric: false,
+		},
+		{
+			name:            "update to neither set",
+			prior:           map[string]cty.Value{"number": cty.False, "numeric": cty.False},
+			config:          map[string]cty.Value{},
+			expectedNumber:  true,
+			expectedNumeric: true,
+		},
+	}
+
+	for _, typeName := range []string{"random_string", "random_password"} {
+		for _, c := range cases {
+			t.Run(typeName+" "+c.name, func(t *testing.T) {
+				config := map[string]cty.Value{"length": cty.NumberIntVal(12)}
+				for k, v := range c.config {
+					config[k] = v
+				}
+
+				var prior map[string]cty.Value
+				if c.prior != nil {
+					prior = map[string]cty.Value{"id": cty.StringVal("none"), "result": cty.StringVal("abcdefghijkl")}
+					for k, v := range c.prior {
+						prior[k] = v
+					}
+				}
+
+				planned, _ := planResourceChange(t, typeName, prior, config)
+
+				if got := planned.GetAttr("number"); !got.RawEquals(cty.BoolVal(c.expectedNumber)) {
+					t.Errorf("expected number: %t, got: %#v", c.expectedNumber, got)
+				}
+				if got := planned.GetAttr("numeric"); !got.RawEquals(cty.BoolVal(c.expectedNumeric)) {
+					t.Errorf("expected numeric: %t, got: %#v", c.expectedNumeric, got)
+				}
+			})
+		}
+	}
+}
+
+func TestPatternConflictsWith(t *testing.T) {
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		err    string
+	}{
+		{
+			name:   "pattern only",
+			config: map[string]interface{}{"pattern": "AAA-999"},
+		},
+		{
+			name:   "pattern with character classes",
+			config: map[string]interface{}{"pattern": "****", "upper": false, "special": true},
+		},
+		{
+			name:   "pattern with min_upper",
+			config: map[string]interface{}{"pattern": "AAA-999", "min_upper": 1},
+			err:    `"pattern": conflicts with min_upper`,
+		},
+		{
+			name:   "pattern with min_special set to zero",
+			config: map[string]interface{}{"pattern": "AAA-999", "min_special": 0},
+			err:    `"pattern": conflicts with min_special`,
+		},
+		{
+			name:   "pattern with min_numeric_pct",
+			config: map[string]interface{}{"pattern": "AAA-999", "min_numeric_pct": 0.5},
+			err:    `"pattern": conflicts with min_numeric_pct`,
+		},
+		{
+			name:   "min_numeric with min_numeric_pct",
+			config: map[string]interface{}{"length": 8, "min_numeric": 2, "min_numeric_pct": 0.5},
+			err:    `"min_numeric_pct": conflicts with min_numeric`,
+		},
+		{
+			name:   "min_numeric with min_upper_pct",
+			config: map[string]interface{}{"length": 8, "min_numeric": 2, "min_upper_pct": 0.5},
+		},
+		{
+			name:   "min_numeric without pattern",
+			config: map[string]interface{}{"length": 8, "min_numeric": 2},
+		},
+	}
+
+	r := resourceString()
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			diags := r.Validate(terraform.NewResourceConfigRaw(c.config))
+
+			if c.err == "" {
+				if diags.HasError() {
+					t.Errorf("expected no error, got: %v", diags)
+				}
+				return
+			}
+
+			found := false
+			for _, d := range diags {
+				if d.Detail == c.err {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected error %q, got: %v", c.err, diags)
+			}
+		})
+	}
+}
+
+func TestIsSingleRune(t *testing.T) {
+	cases := []struct {
+		input    interface{}
+		expected bool
+	}{
+		{input: "!", expected: true},
+		{input: "é", expected: true},
+		{input: "", expected: false},
+		{input: "!@", expected: false},
+		{input: 1, expected: false},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%v", c.input), func(t *testing.T) {
+			diags := isSingleRune(c.input, cty.GetAttrPath("special_set"))
+
+			if c.expected && diags.HasError() {
+				t.Errorf("expected no error, got: %v", diags)
+			}
+			if !c.expected && !diags.HasError() {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestWarnDuplicateChars(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "no duplicates",
+			input: "!@#$%&*()-_=+[]{}<>:?",
+		},
+		{
+			name:  "empty",
+			input: "",
+		},
+		{
+			name:     "repeated character",
+			input:    "!!!!",
+			expected: `"!"`,
+		},
+		{
+			name:     "multiple repeated characters",
+			input:    "!@é!#é@",
+			expected: `"!é@"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			diags := warnDuplicateChars(c.input, cty.GetAttrPath("override_special"))
+
+			if c.expected == "" {
+				if len(diags) != 0 {
+					t.Errorf("expected no diagnostics, got: %v", diags)
+				}
+				return
+			}
+
+			if len(diags) != 1 || diags[0].Severity != diag.Warning {
+				t.Fatalf("expected a single warning, got: %v", diags)
+			}
+			if !strings.Contains(diags[0].Detail, c.expected) {
+				t.Errorf("expected detail to name %s, got: %q", c.expected, diags[0].Detail)
+			}
+		})
+	}
+}
+
+func TestWarnOverrideSpecialIgnored(t *testing.T) {
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		warn   bool
+	}{
+		{name: "neither set", config: map[string]interface{}{"special": false}},
+		{name: "special only", config: map[string]interface{}{"special": true}},
+		{name: "both set", config: map[string]interface{}{"special": true, "override_special": "-_"}},
+		{name: "override_special without special", config: map[string]interface{}{"special": false, "override_special": "-_"}, warn: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := resourceString().TestResourceData()
+			for k, v := range c.config {
+				if err := d.Set(k, v); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			diags := warnOverrideSpecialIgnored(d)
+			if !c.warn {
+				if len(diags) != 0 {
+					t.Errorf("expected no diagnostics, got: %v", diags)
+				}
+				return
+			}
+
+			if len(diags) != 1 || diags[0].Severity != diag.Warning || diags[0].Summary != "override_special has no effect" {
+				t.Errorf("expected a warning that override_special has no effect, got: %v", diags)
+			}
+		})
+	}
+}
+
+func TestWarnOverrideSpecialOverlap(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   map[string]interface{}
+		expected string
+	}{
+		{name: "no overlap", config: map[string]interface{}{"special": true, "upper": true, "lower": true, "numeric": true, "override_special": "-_"}},
+		{name: "overlapping class disabled", config: map[string]interface{}{"special": true, "upper": true, "override_special": "a1"}},
+		{name: "special disabled", config: map[string]interface{}{"special": false, "lower": true, "override_special": "a"}},
+		{name: "overlap", config: map[string]interface{}{"special": true, "upper": true, "numeric": true, "override_special": "-Z9aZ"}, expected: `"Z9"`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := resourceString().TestResourceData()
+			for k, v := range c.config {
+				if err := d.Set(k, v); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			diags := warnOverrideSpecialOverlap(d)
+			if c.expected == "" {
+				if len(diags) != 0 {
+					t.Errorf("expected no diagnostics, got: %v", diags)
+				}
+				return
+			}
+
+			if len(diags) != 1 || diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Detail, c.expected) {
+				t.Errorf("expected a warning listing %s, got: %v", c.expected, diags)
+			}
+		})
+	}
+}
+
+func TestReadResultEncodings(t *testing.T) {
+	d := resourcePassword().TestResourceData()
+	if err := d.Set("result", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if diags := readResultEncodings(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if got, want := d.Get("result_base64").(string), "aHVudGVyMg=="; got != want {
+		t.Errorf("got result_base64 %q; want %q", got, want)
+	}
+	if got, want := d.Get("result_hex").(string), "68756e74657232"; got != want {
+		t.Errorf("got result_hex %q; want %q", got, want)
+	}
+
+	d = resourceString().TestResourceData()
+	if err := d.Set("result", "svc-hunter2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if diags := readResultEncodings(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if got, want := d.Get("result_length").(int), 11; got != want {
+		t.Errorf("got result_length %d; want %d", got, want)
+	}
+}
+
+func TestGeneratePatternResult(t *testing.T) {
+	cases := []struct {
+		name     string
+		pattern  string
+		expected *regexp.Regexp
+	}{
+		{
+			name:     "classes",
+			pattern:  "AAA-999-aa",
+			expected: regexp.MustCompile(`^[A-Z]{3}-[0-9]{3}-[a-z]{2}$`),
+		},
+		{
+			name:     "any",
+			pattern:  "****",
+			expected: regexp.MustCompile(`^[A-Za-z0-9!@#$%&*()\-_=+\[\]{}<>:?]{4}$`),
+		},
+		{
+			name:     "escaped",
+			pattern:  `\A\a\9\*\\`,
+			expected: regexp.MustCompile(`^Aa9\*\\$`),
+		},
+		{
+			name:     "literals only",
+			pattern:  "svc-",
+			expected: regexp.MustCompile(`^svc-$`),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := resourceString().TestResourceData()
+			if err := d.Set("pattern", c.pattern); err != nil {
+				t.Fatal(err)
+			}
+			for _, k := range []string{"upper", "lower", "numeric", "special"} {
+				if err := d.Set(k, true); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			result, diags := generatePatternResult(d, nil, stringRandReader(d, nil, "random_string"), c.pattern)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if !c.expected.Match(result) {
+				t.Errorf("result %q does not match %s", result, c.expected)
+			}
+		})
+	}
+}
+
+func TestRandomIndices(t *testing.T) {
+	const trials = 30000
+
+	for _, n := range []int{1, 3, 62, 129, 256, 300} {
+		indices, err := randomIndices(NewRand("indices"), n, trials)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(indices) != trials {
+			t.Fatalf("n=%d: got %d indices; want %d", n, len(indices), trials)
+		}
+
+		counts := make([]int, n)
+		for _, idx := range indices {
+			if idx < 0 || idx >= n {
+				t.Fatalf("n=%d: index %d out of range", n, idx)
+			}
+			counts[idx]++
+		}
+
+		// With few values each should be chosen about equally often. With many, the counts are too small to compare
+		// individually, so only check that the lower and upper halves of the range are chosen about equally often.
+		if n <= 62 {
+			expected := trials / n
+			tolerance := expected / 5
+			for idx, count := range counts {
+				if count < expected-tolerance || count > expected+tolerance {
+					t.Errorf("n=%d: index %d was chosen %d times; want %d +/- %d", n, idx, count, expected, tolerance)
+				}
+			}
+		} else {
+			lower := 0
+			for _, count := range counts[:n/2] {
+				lower += count
+			}
+			expected := trials * (n / 2) / n
+			if tolerance := trials / 50; lower < expected-tolerance || lower > expected+tolerance {
+				t.Errorf("n=%d: lower half was chosen %d times; want %d +/- %d", n, lower, expected, tolerance)
+			}
+		}
+	}
+
+	if indices, err := randomIndices(rand.Reader, 0, 0); err != nil || len(indices) != 0 {
+		t.Errorf("expected no indices and no error for a count of 0, got %v, %v", indices, err)
+	}
+	if _, err := randomIndices(rand.Reader, 0, 1); err == nil {
+		t.Error("expected error choosing from an empty set")
+	}
+}
+
+func BenchmarkGenerateRandomBytes(b *testing.B) {
+	const length = 4096
+	charSet := upperChars + lowerChars + numChars + "!@#$%&*()-_=+[]{}<>:?"
+
+	b.Run("bulk", func(b *testing.B) {
+		b.SetBytes(length)
+		for i := 0; i < b.N; i++ {
+			if _, err := generateRandomBytes(rand.Reader, &charSet, length); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	// per-byte reads a value from rand.Reader for each byte, as generateRandomBytes previously did.
+	b.Run("per-byte", func(b *testing.B) {
+		b.SetBytes(length)
+		setLen := big.NewInt(int64(len(charSet)))
+		for i := 0; i < b.N; i++ {
+			bytes := make([]byte, length)
+			for j := range bytes {
+				idx, err := rand.Int(rand.Reader, setLen)
+				if err != nil {
+					b.Fatal(err)
+				}
+				bytes[j] = charSet[idx.Int64()]
+			}
+		}
+	})
+}
+
+func TestGenerateStringLengthEqualToMinimums(t *testing.T) {
+	// When length is the sum of the minimums no characters are drawn from chars once the minimums are satisfied, so
+	// the result is made up of exactly the minimum number of characters of each class.
+	minimums := []charSetMinimum{
+		{chars: upperChars, min: 3},
+		{chars: lowerChars, min: 2},
+		{chars: numChars, min: 4},
+		{chars: "!#@", min: 1},
+	}
+
+	for i := 0; i < 100; i++ {
+		result, err := generateString(rand.Reader, upperChars+lowerChars+numChars+"!#@", minimums, 10, splitBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s := strings.Join(result, "")
+		if len(s) != 10 {
+			t.Fatalf("expected a result of length 10, got %q", s)
+		}
+		for _, m := range minimums {
+			count := 0
+			for _, r := range s {
+				if strings.ContainsRune(m.chars, r) {
+					count++
+				}
+			}
+			if count != m.min {
+				t.Fatalf("expected %d characters of %q in %q, got %d", m.min, m.chars, s, count)
+			}
+		}
+	}
+}
+
+func TestGenerateNegativeLength(t *testing.T) {
+	charSet := numChars
+
+	if _, err := generateRandomBytes(rand.Reader, &charSet, -1); err == nil {
+		t.Error("expected an error generating bytes of length -1")
+	}
+
+	if _, err := generateRandomUnits(rand.Reader, splitBytes(charSet), -1); err == nil {
+		t.Error("expected an error generating units of length -1")
+	}
+
+	// Minimums that exceed length are rejected by validation, but must not cause a panic if that is bypassed.
+	minimums := []charSetMinimum{{chars: numChars, min: 3}, {chars: lowerChars, min: 3}}
+	if _, err := generateString(rand.Reader, numChars+lowerChars, minimums, 5, splitBytes); err == nil {
+		t.Error("expected an error when minimums exceed length")
+	}
+}
+
+func TestGenerateStringPositionDistribution(t *testing.T) {
+	const (
+		length = 10
+		trials = 20000
+	)
+
+	// A single "b" is drawn to satisfy minimums and every other character is "a", so the position of "b" in each
+	// result shows how the shuffle places characters drawn to satisfy minimums.
+	reader := NewRand("distribution")
+	counts := make([]int, length)
+	for i := 0; i < trials; i++ {
+		result, err := generateString(reader, "a", []charSetMinimum{{chars: "b", min: 1}}, length, splitBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[strings.Index(strings.Join(result, ""), "b")]++
+	}
+
+	expected := trials / length
+	tolerance := expected / 10
+	for position, count := range counts {
+		if count < expected-tolerance || count > expected+tolerance {
+			t.Errorf("position %d was chosen %d times; want %d +/- %d", position, count, expected, tolerance)
+		}
+	}
+}